@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/readium/go-toolkit/pkg/archive"
 	"github.com/readium/go-toolkit/pkg/asset"
 	"github.com/readium/go-toolkit/pkg/streamer"
 	"github.com/spf13/cobra"
@@ -20,6 +21,9 @@ var inferA11yFlag InferA11yMetadata
 // Infer the number of pages from the generated position list.
 var inferPageCountFlag bool
 
+// Verify the integrity of the publication's archive.
+var verifyFlag bool
+
 var manifestCmd = &cobra.Command{
 	Use:   "manifest <pub-path>",
 	Short: "Generate a Readium Web Publication Manifest for a publication",
@@ -54,9 +58,23 @@ Examples:
 		cmd.SilenceUsage = true
 
 		path := filepath.Clean(args[0])
+		archiveFactory := archive.NewArchiveFactory()
+		if verifyFlag {
+			archiveFactory = archive.NewVerifyingArchiveFactory()
+			a, err := archiveFactory.Open(path, "")
+			if err == nil {
+				err = archive.Verify(a)
+				a.Close()
+				if err != nil {
+					return fmt.Errorf("integrity check failed for %s: %w", path, err)
+				}
+			}
+		}
+
 		pub, err := streamer.New(streamer.Config{
 			InferA11yMetadata: streamer.InferA11yMetadata(inferA11yFlag),
 			InferPageCount:    inferPageCountFlag,
+			ArchiveFactory:    archiveFactory,
 		}).Open(
 			asset.File(path), "",
 		)
@@ -84,6 +102,7 @@ func init() {
 	manifestCmd.Flags().StringVarP(&indentFlag, "indent", "i", "", "Indentation used to pretty-print")
 	manifestCmd.Flags().Var(&inferA11yFlag, "infer-a11y", "Infer accessibility metadata: no, merged, split")
 	manifestCmd.Flags().BoolVar(&inferPageCountFlag, "infer-page-count", false, "Infer the number of pages from the generated position list.")
+	manifestCmd.Flags().BoolVar(&verifyFlag, "verify", false, "Verify the CRC32 checksums of the publication's archive entries.")
 }
 
 type InferA11yMetadata streamer.InferA11yMetadata
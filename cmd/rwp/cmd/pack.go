@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/packager"
+	"github.com/readium/go-toolkit/pkg/streamer"
+	"github.com/spf13/cobra"
+)
+
+// Type of package to produce.
+var packTypeFlag string
+
+var packCmd = &cobra.Command{
+	Use:   "pack <pub-path> [output-path]",
+	Short: "Package a publication as a Readium Web Publication",
+	Long: `Package a publication as a Readium Web Publication.
+
+This command will parse a publication file (such as EPUB, PDF, audiobook, etc.)
+and write it as a Readium Web Publication package, containing the generated
+manifest and the publication resources. By default, the type of package
+(webpub, audiobook or divina) is deduced from the content of the publication,
+and the output is written next to the publication with the matching extension.
+
+Examples:
+  Package a comic book archive as a Divina.
+  $ rwp pack comic.cbz
+
+  Package an EPUB as a Readium Web Publication.
+  $ rwp pack --type webpub publication.epub publication.webpub
+  `,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("expects a path to the publication")
+		} else if len(args) > 2 {
+			return errors.New("accepts a path to a publication and an optional output path")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// By the time we reach this point, we know that the arguments were
+		// properly parsed, and we don't want to show the usage if an API error
+		// occurs.
+		cmd.SilenceUsage = true
+
+		path := filepath.Clean(args[0])
+		pub, err := streamer.New(streamer.Config{}).Open(asset.File(path), "")
+		if err != nil {
+			return fmt.Errorf("failed opening %s: %w", path, err)
+		}
+		defer pub.Close()
+
+		var mt mediatype.MediaType
+		switch packTypeFlag {
+		case "":
+			mt = packager.MediaTypeOf(pub.Manifest)
+		case "webpub":
+			mt = mediatype.ReadiumWebpub
+		case "audiobook":
+			mt = mediatype.ReadiumAudiobook
+		case "divina":
+			mt = mediatype.ReadiumDivina
+		default:
+			return fmt.Errorf("unknown package type %s, expected webpub, audiobook or divina", packTypeFlag)
+		}
+
+		var output string
+		if len(args) > 1 {
+			output = filepath.Clean(args[1])
+		} else {
+			output = strings.TrimSuffix(path, filepath.Ext(path)) + "." + mt.FileExtension()
+		}
+		if output == path {
+			return fmt.Errorf("output path %s is the same as the publication path", output)
+		}
+
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed creating %s: %w", output, err)
+		}
+		if err := packager.PackAs(pub, mt, f); err != nil {
+			f.Close()
+			os.Remove(output)
+			return fmt.Errorf("failed packaging %s: %w", path, err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed writing %s: %w", output, err)
+		}
+
+		fmt.Println(output)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(packCmd)
+	packCmd.Flags().StringVarP(&packTypeFlag, "type", "t", "", "Type of package: webpub, audiobook or divina (deduced from the publication by default)")
+}
@@ -6,11 +6,13 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"log/slog"
 
 	"github.com/readium/go-toolkit/cmd/rwp/cmd/serve"
+	"github.com/readium/go-toolkit/cmd/rwp/cmd/serve/storage"
 	"github.com/readium/go-toolkit/pkg/streamer"
 	"github.com/spf13/cobra"
 )
@@ -21,8 +23,14 @@ var bindAddressFlag string
 
 var bindPortFlag uint16
 
+var s3EndpointFlag string
+
+var s3RegionFlag string
+
+var s3PathStyleFlag bool
+
 var serveCmd = &cobra.Command{
-	Use:   "serve <directory>",
+	Use:   "serve <directory | s3://bucket/prefix>",
 	Short: "Start a local HTTP server, serving a specified directory of publications",
 	Long: `Start a local HTTP server, serving a specified directory of publications.
 
@@ -33,6 +41,11 @@ as Readium Web Publications. To get started, the manifest can be accessed from
 This file serves as the entry point and contains metadata and links to the rest
 of the files that can be accessed for the publication.
 
+Publications can also be served from an S3-compatible object storage by passing
+an 's3://bucket/prefix' URL instead of a directory. Credentials are read from the
+AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment
+variables. Use '--s3-endpoint' and '--s3-path-style' for services such as MinIO.
+
 For debugging purposes, the server also exposes a '/list.json' endpoint that
 returns a list of all the publications found in the directory along with their
 encoded paths. This will be replaced by an OPDS 2 feed in a future release.
@@ -41,9 +54,9 @@ Note: This server is not meant for production usage, and should not be exposed
 to the internet except for testing/debugging purposes.`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			return errors.New("expects a directory path to serve publications from")
+			return errors.New("expects a directory path or S3 URL to serve publications from")
 		} else if len(args) > 1 {
-			return errors.New("accepts a directory path")
+			return errors.New("accepts a directory path or S3 URL")
 		}
 		return nil
 	},
@@ -55,16 +68,40 @@ to the internet except for testing/debugging purposes.`,
 		// occurs.
 		cmd.SilenceUsage = true
 
-		path := filepath.Clean(args[0])
-		fi, err := os.Stat(path)
-		if err != nil {
-			if os.IsNotExist(err) {
-				return fmt.Errorf("given directory %s does not exist", path)
+		var store storage.Storage
+		if location, ok := strings.CutPrefix(args[0], "s3://"); ok {
+			bucket, prefix, _ := strings.Cut(location, "/")
+			region := s3RegionFlag
+			if region == "" {
+				region = os.Getenv("AWS_REGION")
 			}
-			return fmt.Errorf("failed to stat %s: %w", path, err)
-		}
-		if !fi.IsDir() {
-			return fmt.Errorf("given path %s is not a directory", path)
+			s3, err := storage.NewS3Storage(storage.S3Config{
+				Endpoint:        s3EndpointFlag,
+				Region:          region,
+				Bucket:          bucket,
+				Prefix:          prefix,
+				UsePathStyle:    s3PathStyleFlag,
+				AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+				SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+				SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			})
+			if err != nil {
+				return fmt.Errorf("failed configuring S3 storage: %w", err)
+			}
+			store = s3
+		} else {
+			path := filepath.Clean(args[0])
+			fi, err := os.Stat(path)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return fmt.Errorf("given directory %s does not exist", path)
+				}
+				return fmt.Errorf("failed to stat %s: %w", path, err)
+			}
+			if !fi.IsDir() {
+				return fmt.Errorf("given path %s is not a directory", path)
+			}
+			store = storage.NewLocalStorage(path)
 		}
 
 		// Log level
@@ -76,7 +113,7 @@ to the internet except for testing/debugging purposes.`,
 
 		pubServer := serve.NewServer(serve.ServerConfig{
 			Debug:             debugFlag,
-			BaseDirectory:     path,
+			Storage:           store,
 			JSONIndent:        indentFlag,
 			InferA11yMetadata: streamer.InferA11yMetadata(inferA11yFlag),
 		})
@@ -108,5 +145,8 @@ func init() {
 	serveCmd.Flags().StringVarP(&indentFlag, "indent", "i", "", "Indentation used to pretty-print JSON files")
 	serveCmd.Flags().Var(&inferA11yFlag, "infer-a11y", "Infer accessibility metadata: no, merged, split")
 	serveCmd.Flags().BoolVarP(&debugFlag, "debug", "d", false, "Enable debug mode")
+	serveCmd.Flags().StringVar(&s3EndpointFlag, "s3-endpoint", "", "Endpoint of the S3-compatible storage (defaults to AWS)")
+	serveCmd.Flags().StringVar(&s3RegionFlag, "s3-region", "", "Region of the S3 bucket (defaults to $AWS_REGION, then us-east-1)")
+	serveCmd.Flags().BoolVar(&s3PathStyleFlag, "s3-path-style", false, "Use path-style addressing for the S3 bucket")
 
 }
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
-	"os"
 	"path"
 	"path/filepath"
 	"slices"
@@ -19,7 +18,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/readium/go-toolkit/cmd/rwp/cmd/serve/cache"
 	"github.com/readium/go-toolkit/pkg/archive"
-	"github.com/readium/go-toolkit/pkg/asset"
 	"github.com/readium/go-toolkit/pkg/fetcher"
 	"github.com/readium/go-toolkit/pkg/manifest"
 	"github.com/readium/go-toolkit/pkg/pub"
@@ -34,17 +32,17 @@ type demoListItem struct {
 }
 
 func (s *Server) demoList(w http.ResponseWriter, req *http.Request) {
-	fi, err := os.ReadDir(s.config.BaseDirectory)
+	names, err := s.config.Storage.List()
 	if err != nil {
-		slog.Error("failed reading publications directory", "error", err)
+		slog.Error("failed listing publications", "error", err)
 		w.WriteHeader(500)
 		return
 	}
-	files := make([]demoListItem, len(fi))
-	for i, f := range fi {
+	files := make([]demoListItem, len(names))
+	for i, name := range names {
 		files[i] = demoListItem{
-			Filename: f.Name(),
-			Path:     base64.RawURLEncoding.EncodeToString([]byte(f.Name())),
+			Filename: name,
+			Path:     base64.RawURLEncoding.EncodeToString([]byte(name)),
 		}
 	}
 	enc := json.NewEncoder(w)
@@ -61,9 +59,13 @@ func (s *Server) getPublication(filename string) (*pub.Publication, error) {
 	cp := filepath.Clean(string(fpath))
 	dat, ok := s.lfu.Get(cp)
 	if !ok {
+		a, err := s.config.Storage.Asset(cp)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed locating "+cp)
+		}
 		pub, err := streamer.New(streamer.Config{
 			InferA11yMetadata: s.config.InferA11yMetadata,
-		}).Open(asset.File(filepath.Join(s.config.BaseDirectory, cp)), "")
+		}).Open(a, "")
 		if err != nil {
 			return nil, errors.Wrap(err, "failed opening "+cp)
 		}
@@ -5,12 +5,14 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/readium/go-toolkit/cmd/rwp/cmd/serve/cache"
+	"github.com/readium/go-toolkit/cmd/rwp/cmd/serve/storage"
 	"github.com/readium/go-toolkit/pkg/streamer"
 )
 
 type ServerConfig struct {
 	Debug             bool
-	BaseDirectory     string
+	BaseDirectory     string          // Directory of publications, used when no Storage is provided.
+	Storage           storage.Storage // Library of publications to serve.
 	JSONIndent        string
 	InferA11yMetadata streamer.InferA11yMetadata
 }
@@ -25,6 +27,9 @@ const MaxCachedPublicationAmount = 10
 const MaxCachedPublicationTTL = time.Second * time.Duration(600)
 
 func NewServer(config ServerConfig) *Server {
+	if config.Storage == nil {
+		config.Storage = storage.NewLocalStorage(config.BaseDirectory)
+	}
 	return &Server{
 		config: config,
 		lfu:    cache.NewTinyLFU(MaxCachedPublicationAmount, MaxCachedPublicationTTL),
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/readium/go-toolkit/pkg/asset"
+)
+
+// Serves publications from a directory on the local file system.
+type LocalStorage struct {
+	baseDirectory string
+}
+
+func NewLocalStorage(baseDirectory string) *LocalStorage {
+	return &LocalStorage{baseDirectory: baseDirectory}
+}
+
+// List implements Storage
+func (s *LocalStorage) List() ([]string, error) {
+	fi, err := os.ReadDir(s.baseDirectory)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(fi))
+	for i, f := range fi {
+		names[i] = f.Name()
+	}
+	return names, nil
+}
+
+// Asset implements Storage
+func (s *LocalStorage) Asset(name string) (asset.PublicationAsset, error) {
+	return asset.File(filepath.Join(s.baseDirectory, filepath.Clean(name))), nil
+}
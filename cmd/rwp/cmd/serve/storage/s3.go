@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	gurl "net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/util/url"
+)
+
+// Configuration of a [S3Storage].
+type S3Config struct {
+	Endpoint        string // Base URL of the S3-compatible service. Defaults to AWS for the configured region.
+	Region          string // Region of the bucket, used to sign requests. Defaults to "us-east-1".
+	Bucket          string // Name of the bucket containing the publications.
+	Prefix          string // Only objects whose key starts with this prefix are served.
+	UsePathStyle    bool   // Address the bucket in the path of the URL instead of the host (needed for MinIO and most S3 stand-ins).
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Optional, for temporary credentials.
+}
+
+// Serves publications stored in an S3-compatible object storage.
+// Archives are opened lazily with signed range requests, so only the parts
+// needed to serve a resource are downloaded.
+type S3Storage struct {
+	config   S3Config
+	endpoint *gurl.URL
+	client   *http.Client
+}
+
+func NewS3Storage(config S3Config) (*S3Storage, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("S3 bucket is required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	endpoint, err := gurl.Parse(config.Endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid S3 endpoint")
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, errors.New("S3 endpoint must be an HTTP(S) URL")
+	}
+	if config.Prefix != "" && !strings.HasSuffix(config.Prefix, "/") {
+		config.Prefix += "/"
+	}
+
+	return &S3Storage{
+		config:   config,
+		endpoint: endpoint,
+		client: &http.Client{
+			Transport: &s3SigningTransport{
+				base:            http.DefaultTransport,
+				region:          config.Region,
+				accessKeyID:     config.AccessKeyID,
+				secretAccessKey: config.SecretAccessKey,
+				sessionToken:    config.SessionToken,
+				now:             time.Now,
+			},
+		},
+	}, nil
+}
+
+type s3ListBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List implements Storage
+// Only the objects directly under the configured prefix are listed.
+func (s *S3Storage) List() ([]string, error) {
+	var names []string
+	token := ""
+	for {
+		query := gurl.Values{}
+		query.Set("list-type", "2")
+		query.Set("delimiter", "/")
+		if s.config.Prefix != "" {
+			query.Set("prefix", s.config.Prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		u := s.objectURL("")
+		u.RawQuery = query.Encode()
+
+		res, err := s.client.Get(u.String())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed listing S3 bucket")
+		}
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed reading S3 bucket listing")
+		}
+		if res.StatusCode != http.StatusOK {
+			return nil, errors.New("failed listing S3 bucket: " + res.Status)
+		}
+
+		var result s3ListBucketResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, errors.Wrap(err, "failed parsing S3 bucket listing")
+		}
+		for _, c := range result.Contents {
+			name := strings.TrimPrefix(c.Key, s.config.Prefix)
+			if name == "" {
+				continue
+			}
+			names = append(names, name)
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	return names, nil
+}
+
+// Asset implements Storage
+func (s *S3Storage) Asset(name string) (asset.PublicationAsset, error) {
+	key := path.Clean("/" + filepath.ToSlash(name))[1:] // Rooting the path prevents escaping the prefix
+	if key == "" {
+		return nil, errors.New("empty publication name")
+	}
+	u, err := url.AbsoluteURLFromGo(s.objectURL(s.config.Prefix + key))
+	if err != nil {
+		return nil, err
+	}
+	return asset.HTTP(u, s.client), nil
+}
+
+// URL of the object with the given key, or of the bucket itself if the key is empty.
+func (s *S3Storage) objectURL(key string) *gurl.URL {
+	u := *s.endpoint
+	p := strings.TrimSuffix(u.Path, "/")
+	if s.config.UsePathStyle {
+		p += "/" + s.config.Bucket
+	} else {
+		u.Host = s.config.Bucket + "." + u.Host
+	}
+	p += "/" + key
+	u.Path = p
+	u.RawPath = s3URIEncode(p, false)
+	return &u
+}
@@ -0,0 +1,9 @@
+package storage
+
+import "github.com/readium/go-toolkit/pkg/asset"
+
+// A library of publications served by `rwp serve`.
+type Storage interface {
+	List() ([]string, error)                           // Names of the publications available in the storage.
+	Asset(name string) (asset.PublicationAsset, error) // Asset used to open the publication with the given name.
+}
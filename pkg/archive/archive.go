@@ -2,6 +2,7 @@ package archive
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"os"
 )
@@ -50,6 +51,15 @@ func NewArchiveFactory() DefaultArchiveFactory {
 	return DefaultArchiveFactory{}
 }
 
+// Creates an [ArchiveFactory] whose ZIP archives verify the CRC32 checksum of
+// their entries when they are read or streamed in full.
+// A mismatch is reported as an error wrapping [ErrChecksumMismatch].
+func NewVerifyingArchiveFactory() DefaultArchiveFactory {
+	return DefaultArchiveFactory{
+		gozipFactory: gozipArchiveFactory{verifyChecksums: true},
+	}
+}
+
 // Holds an archive entry's metadata.
 type Entry interface {
 	Path() string                                              // Absolute path to the entry in the archive.
@@ -66,6 +76,32 @@ type Entry interface {
 
 }
 
+// Returned when the data of an archive entry doesn't match its checksum.
+var ErrChecksumMismatch = errors.New("archive entry checksum mismatch")
+
+// Entries able to check the integrity of their whole content.
+type verifiableEntry interface {
+	verifyIntegrity() error
+}
+
+// Reads every entry of the archive to check its integrity, using the CRC32
+// checksums of ZIP entries. All the failures are returned joined together.
+func Verify(a Archive) error {
+	var errs []error
+	for _, entry := range a.Entries() {
+		var err error
+		if ve, ok := entry.(verifiableEntry); ok {
+			err = ve.verifyIntegrity()
+		} else {
+			_, err = entry.Stream(io.Discard, 0, 0)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Path(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Represents an immutable archive.
 type Archive interface {
 	Entries() []Entry                 // List of all the archived file entries.
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"compress/flate"
 	"encoding/binary"
+	"hash/crc32"
 	"io"
 	"io/fs"
 	"math"
@@ -15,8 +16,9 @@ import (
 )
 
 type gozipArchiveEntry struct {
-	file          *zip.File
-	minimizeReads bool
+	file            *zip.File
+	minimizeReads   bool
+	verifyChecksums bool
 }
 
 func (e gozipArchiveEntry) Path() string {
@@ -53,6 +55,15 @@ func (e gozipArchiveEntry) Read(start int64, end int64) ([]byte, error) {
 	if end < start {
 		return nil, errors.New("range not satisfiable")
 	}
+	if !(start == 0 && end == 0) {
+		// Out-of-range indexes are clamped to the length of the entry
+		size := int64(e.file.UncompressedSize64)
+		start = max(start, 0)
+		if start >= size {
+			return []byte{}, nil
+		}
+		end = min(end, size-1)
+	}
 
 	minimizeReads := e.couldMinimizeReads()
 
@@ -87,7 +98,12 @@ func (e gozipArchiveEntry) Read(start int64, end int64) ([]byte, error) {
 		data := make([]byte, e.file.UncompressedSize64)
 		_, err := io.ReadFull(f, data)
 		if err != nil {
-			return nil, err
+			return nil, e.checkError(err)
+		}
+		if e.verifyChecksums {
+			if err := e.verify(crc32.ChecksumIEEE(data)); err != nil {
+				return nil, err
+			}
 		}
 		return data, nil
 	}
@@ -97,7 +113,7 @@ func (e gozipArchiveEntry) Read(start int64, end int64) ([]byte, error) {
 			return nil, err
 		}
 	}
-	data := make([]byte, min(end-start+1, int64(e.file.UncompressedSize64)))
+	data := make([]byte, end-start+1)
 	_, err = io.ReadFull(f, data)
 	if err != nil {
 		return nil, err
@@ -109,6 +125,14 @@ func (e gozipArchiveEntry) Stream(w io.Writer, start int64, end int64) (int64, e
 	if end < start {
 		return -1, errors.New("range not satisfiable")
 	}
+	if !(start == 0 && end == 0) {
+		size := int64(e.file.UncompressedSize64)
+		start = max(start, 0)
+		if start >= size {
+			return 0, nil
+		}
+		end = min(end, size-1)
+	}
 
 	minimizeReads := e.couldMinimizeReads() && start == 0 && end == 0
 
@@ -140,7 +164,19 @@ func (e gozipArchiveEntry) Stream(w io.Writer, start int64, end int64) (int64, e
 	}
 
 	if start == 0 && end == 0 {
-		return io.Copy(w, f)
+		if !e.verifyChecksums {
+			n, err := io.Copy(w, f)
+			return n, e.checkError(err)
+		}
+
+		// The data has already been written when the mismatch is detected,
+		// so the caller is responsible for discarding it.
+		hash := crc32.NewIEEE()
+		n, err := io.Copy(io.MultiWriter(w, hash), f)
+		if err != nil {
+			return n, e.checkError(err)
+		}
+		return n, e.verify(hash.Sum32())
 	}
 	if start > 0 {
 		n, err := io.CopyN(io.Discard, f, start)
@@ -155,6 +191,32 @@ func (e gozipArchiveEntry) Stream(w io.Writer, start int64, end int64) (int64, e
 	return n, nil
 }
 
+// Checks the CRC32 of the uncompressed data against the one in the central directory.
+// Like the standard library, a zero CRC32 is considered as missing and is not checked.
+func (e gozipArchiveEntry) verify(checksum uint32) error {
+	if e.file.CRC32 != 0 && checksum != e.file.CRC32 {
+		return errors.Wrapf(ErrChecksumMismatch, "CRC32 is %08x, expected %08x", checksum, e.file.CRC32)
+	}
+	return nil
+}
+
+// Converts checksum errors from the standard library to [ErrChecksumMismatch]
+// when verification is enabled. Otherwise, errors are returned untouched.
+func (e gozipArchiveEntry) checkError(err error) error {
+	if e.verifyChecksums && errors.Is(err, zip.ErrChecksum) {
+		return ErrChecksumMismatch
+	}
+	return err
+}
+
+// Reads the whole entry, checking its CRC32 even if verification is not enabled on the archive.
+func (e gozipArchiveEntry) verifyIntegrity() error {
+	e.verifyChecksums = true
+	e.minimizeReads = false
+	_, err := e.Stream(io.Discard, 0, 0)
+	return err
+}
+
 func (e gozipArchiveEntry) StreamCompressed(w io.Writer) (int64, error) {
 	if e.file.Method != zip.Deflate {
 		return -1, errors.New("not a compressed resource")
@@ -257,10 +319,11 @@ func (e gozipArchiveEntry) ReadCompressedGzip() ([]byte, error) {
 
 // An archive from a zip file using go's stdlib
 type gozipArchive struct {
-	zip           *zip.Reader
-	closer        func() error
-	cachedEntries sync.Map
-	minimizeReads bool
+	zip             *zip.Reader
+	closer          func() error
+	cachedEntries   sync.Map
+	minimizeReads   bool
+	verifyChecksums bool
 }
 
 func (a *gozipArchive) Close() {
@@ -277,8 +340,9 @@ func (a *gozipArchive) Entries() []Entry {
 		aentry, ok := a.cachedEntries.Load(f.Name)
 		if !ok {
 			aentry = gozipArchiveEntry{
-				file:          f,
-				minimizeReads: a.minimizeReads,
+				file:            f,
+				minimizeReads:   a.minimizeReads,
+				verifyChecksums: a.verifyChecksums,
 			}
 			a.cachedEntries.Store(f.Name, aentry)
 		}
@@ -303,8 +367,9 @@ func (a *gozipArchive) Entry(p string) (Entry, error) {
 		fp := path.Clean(f.Name)
 		if fp == cpath {
 			aentry := gozipArchiveEntry{
-				file:          f,
-				minimizeReads: a.minimizeReads,
+				file:            f,
+				minimizeReads:   a.minimizeReads,
+				verifyChecksums: a.verifyChecksums,
 			}
 			a.cachedEntries.Store(fp, aentry) // Put entry in cache
 			return aentry, nil
@@ -321,7 +386,27 @@ func NewGoZIPArchive(zip *zip.Reader, closer func() error, minimizeReads bool) A
 	}
 }
 
-type gozipArchiveFactory struct{}
+// Creates an archive from a zip file using go's stdlib, whose entries verify
+// their CRC32 checksum when read or streamed in full.
+func NewVerifyingGoZIPArchive(zip *zip.Reader, closer func() error, minimizeReads bool) Archive {
+	return &gozipArchive{
+		zip:             zip,
+		closer:          closer,
+		minimizeReads:   minimizeReads,
+		verifyChecksums: true,
+	}
+}
+
+type gozipArchiveFactory struct {
+	verifyChecksums bool
+}
+
+func (e gozipArchiveFactory) newArchive(zip *zip.Reader, closer func() error, minimizeReads bool) Archive {
+	if e.verifyChecksums {
+		return NewVerifyingGoZIPArchive(zip, closer, minimizeReads)
+	}
+	return NewGoZIPArchive(zip, closer, minimizeReads)
+}
 
 func (e gozipArchiveFactory) Open(filepath string, password string) (Archive, error) {
 	// Go's built-in zip reader doesn't support passwords.
@@ -333,7 +418,7 @@ func (e gozipArchiveFactory) Open(filepath string, password string) (Archive, er
 	if err != nil {
 		return nil, err
 	}
-	return NewGoZIPArchive(&rc.Reader, rc.Close, false), nil
+	return e.newArchive(&rc.Reader, rc.Close, false), nil
 }
 
 func (e gozipArchiveFactory) OpenBytes(data []byte, password string) (Archive, error) {
@@ -346,7 +431,7 @@ func (e gozipArchiveFactory) OpenBytes(data []byte, password string) (Archive, e
 	if err != nil {
 		return nil, err
 	}
-	return NewGoZIPArchive(r, func() error { return nil }, false), nil
+	return e.newArchive(r, func() error { return nil }, false), nil
 }
 
 type ReaderAtCloser interface {
@@ -364,5 +449,5 @@ func (e gozipArchiveFactory) OpenReader(reader ReaderAtCloser, size int64, passw
 	if err != nil {
 		return nil, err
 	}
-	return NewGoZIPArchive(r, reader.Close, minimizeReads), nil
+	return e.newArchive(r, reader.Close, minimizeReads), nil
 }
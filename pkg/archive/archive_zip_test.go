@@ -0,0 +1,124 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Creates a ZIP containing a valid entry and entries whose CRC32 doesn't match their data.
+func corruptedZIP(t *testing.T) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	w, err := zw.Create("valid.txt")
+	assert.NoError(t, err)
+	w.Write([]byte("valid data"))
+
+	stored := []byte("stored data")
+	w, err = zw.CreateRaw(&zip.FileHeader{
+		Name:               "stored.txt",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(stored) + 1,
+		CompressedSize64:   uint64(len(stored)),
+		UncompressedSize64: uint64(len(stored)),
+	})
+	assert.NoError(t, err)
+	w.Write(stored)
+
+	deflated := []byte("deflated data")
+	var compressed bytes.Buffer
+	fw, _ := flate.NewWriter(&compressed, flate.DefaultCompression)
+	fw.Write(deflated)
+	fw.Close()
+	w, err = zw.CreateRaw(&zip.FileHeader{
+		Name:               "deflated.txt",
+		Method:             zip.Deflate,
+		CRC32:              crc32.ChecksumIEEE(deflated) + 1,
+		CompressedSize64:   uint64(compressed.Len()),
+		UncompressedSize64: uint64(len(deflated)),
+	})
+	assert.NoError(t, err)
+	w.Write(compressed.Bytes())
+
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestZIPChecksumNotVerifiedByDefault(t *testing.T) {
+	a, err := NewArchiveFactory().OpenBytes(corruptedZIP(t), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	entry, err := a.Entry("stored.txt")
+	if assert.NoError(t, err) {
+		b, err := entry.Read(0, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "stored data", string(b))
+
+		// The standard library error is kept as is when verification is not enabled
+		_, err = entry.Stream(&bytes.Buffer{}, 0, 0)
+		assert.ErrorIs(t, err, zip.ErrChecksum)
+		assert.NotErrorIs(t, err, ErrChecksumMismatch)
+	}
+}
+
+func TestZIPChecksumVerifiedOnFullReads(t *testing.T) {
+	data := corruptedZIP(t)
+	for _, minimizeReads := range []bool{false, true} {
+		r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if !assert.NoError(t, err) {
+			return
+		}
+		a := NewVerifyingGoZIPArchive(r, func() error { return nil }, minimizeReads)
+
+		for _, p := range []string{"stored.txt", "deflated.txt"} {
+			entry, err := a.Entry(p)
+			if !assert.NoError(t, err) {
+				continue
+			}
+			_, err = entry.Read(0, 0)
+			assert.ErrorIs(t, err, ErrChecksumMismatch, p)
+			_, err = entry.Stream(&bytes.Buffer{}, 0, 0)
+			assert.ErrorIs(t, err, ErrChecksumMismatch, p)
+
+			// Partial reads can't be verified
+			b, err := entry.Read(0, 3)
+			assert.NoError(t, err)
+			assert.Len(t, b, 4)
+		}
+
+		entry, err := a.Entry("valid.txt")
+		if assert.NoError(t, err) {
+			b, err := entry.Read(0, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, "valid data", string(b))
+			var buf bytes.Buffer
+			_, err = entry.Stream(&buf, 0, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, "valid data", buf.String())
+		}
+	}
+}
+
+func TestVerifyReportsCorruptedEntries(t *testing.T) {
+	a, err := NewArchiveFactory().OpenBytes(corruptedZIP(t), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = Verify(a)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.Contains(t, err.Error(), "stored.txt")
+	assert.Contains(t, err.Error(), "deflated.txt")
+	assert.NotContains(t, err.Error(), "valid.txt")
+}
+
+func TestVerifyValidArchives(t *testing.T) {
+	withArchives(t, func(archive Archive) {
+		assert.NoError(t, Verify(archive))
+	})
+}
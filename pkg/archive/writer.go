@@ -0,0 +1,108 @@
+package archive
+
+import (
+	"archive/zip"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Modification time given to every entry written by a [Writer], so that
+// packaging the same content twice produces the same bytes.
+// This is the earliest date that can be represented in a ZIP file.
+var DeterministicModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Builds a ZIP archive following the OCF container rules: the optional
+// `mimetype` entry comes first and is stored uncompressed, and every entry
+// gets the same [DeterministicModTime].
+type Writer struct {
+	zip     *zip.Writer
+	entries map[string]struct{}
+}
+
+// Creates a [Writer] writing a ZIP archive to [w]. When [mediaType] is not
+// empty, it is written in the leading `mimetype` entry.
+func NewWriter(w io.Writer, mediaType string) (*Writer, error) {
+	aw := &Writer{
+		zip:     zip.NewWriter(w),
+		entries: make(map[string]struct{}),
+	}
+	if mediaType != "" {
+		if err := aw.WriteEntry("mimetype", []byte(mediaType), false); err != nil {
+			return nil, errors.Wrap(err, "failed writing mimetype entry")
+		}
+	}
+	return aw, nil
+}
+
+// Creates a new entry at the given path, and returns a writer for its content.
+// The content is written until the next call to [Create], [WriteEntry] or [Close].
+func (w *Writer) Create(p string, compress bool) (io.Writer, error) {
+	header, err := w.header(p, compress)
+	if err != nil {
+		return nil, err
+	}
+	return w.zip.CreateHeader(header)
+}
+
+// Writes an entry with the given content.
+// Uncompressed entries are written with their checksum and sizes in the local
+// header, without a trailing data descriptor, as expected for the OCF `mimetype`.
+func (w *Writer) WriteEntry(p string, data []byte, compress bool) error {
+	if compress {
+		ew, err := w.Create(p, compress)
+		if err != nil {
+			return err
+		}
+		_, err = ew.Write(data)
+		return err
+	}
+
+	header, err := w.header(p, false)
+	if err != nil {
+		return err
+	}
+	header.CRC32 = crc32.ChecksumIEEE(data)
+	header.CompressedSize64 = uint64(len(data))
+	header.UncompressedSize64 = uint64(len(data))
+	ew, err := w.zip.CreateRaw(header)
+	if err != nil {
+		return err
+	}
+	_, err = ew.Write(data)
+	return err
+}
+
+func (w *Writer) header(p string, compress bool) (*zip.FileHeader, error) {
+	if !fs.ValidPath(p) || p == "." {
+		return nil, errors.New("invalid archive entry path " + p)
+	}
+	p = path.Clean(p)
+	if _, ok := w.entries[p]; ok {
+		return nil, errors.New("duplicate archive entry " + p)
+	}
+	w.entries[p] = struct{}{}
+
+	method := zip.Store
+	if compress {
+		method = zip.Deflate
+	}
+
+	// The MS-DOS date fields are used instead of [zip.FileHeader.Modified],
+	// which would add an extended timestamp extra field to every entry.
+	return &zip.FileHeader{
+		Name:         p,
+		Method:       method,
+		ModifiedDate: uint16((DeterministicModTime.Year()-1980)<<9 | int(DeterministicModTime.Month())<<5 | DeterministicModTime.Day()),
+		ModifiedTime: 0,
+	}, nil
+}
+
+// Finishes writing the archive. The underlying writer is not closed.
+func (w *Writer) Close() error {
+	return w.zip.Close()
+}
@@ -0,0 +1,130 @@
+package asset
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/util/url"
+)
+
+// Represents a publication on a remote HTTP server, either packaged as a ZIP
+// archive or as a single file such as a PDF. The file is read lazily using range
+// requests, so only the parts needed to access the requested resources are downloaded.
+type HTTPAsset struct {
+	url            url.AbsoluteURL
+	client         *http.Client
+	reader         *HTTPRangeReader
+	mediatype      *mediatype.MediaType
+	knownMediaType *mediatype.MediaType
+	mediaTypeHint  string
+}
+
+// Creates a [HTTPAsset] for the given URL. A nil [client] falls back on [http.DefaultClient].
+func HTTP(u url.AbsoluteURL, client *http.Client) *HTTPAsset {
+	return &HTTPAsset{
+		url:    u,
+		client: client,
+	}
+}
+
+// Creates a [HTTPAsset] from a URL and an optional media type, when known.
+func HTTPWithMediaType(u url.AbsoluteURL, client *http.Client, mediatype *mediatype.MediaType) *HTTPAsset {
+	return &HTTPAsset{
+		url:            u,
+		client:         client,
+		knownMediaType: mediatype,
+	}
+}
+
+// Creates a [HTTPAsset] from a URL and an optional media type hint.
+func HTTPWithMediaTypeHint(u url.AbsoluteURL, client *http.Client, mediatypeHint string) *HTTPAsset {
+	return &HTTPAsset{
+		url:           u,
+		client:        client,
+		mediaTypeHint: mediatypeHint,
+	}
+}
+
+// Name implements PublicationAsset
+func (a *HTTPAsset) Name() string {
+	return a.url.Filename()
+}
+
+// MediaType implements PublicationAsset
+func (a *HTTPAsset) MediaType() mediatype.MediaType {
+	if a.mediatype == nil {
+		if a.knownMediaType != nil {
+			a.mediatype = a.knownMediaType
+		} else {
+			var extensions []string
+			if ext := a.url.Extension(); ext != "" {
+				extensions = []string{ext}
+			}
+			var hints []string
+			if a.mediaTypeHint != "" {
+				hints = []string{a.mediaTypeHint}
+			}
+			if len(hints) > 0 || len(extensions) > 0 {
+				a.mediatype = mediatype.Of(hints, extensions, mediatype.Sniffers)
+			}
+
+			// Fall back on the type announced by the server, unless it's too generic to be useful
+			if a.mediatype == nil {
+				if r, err := a.open(); err == nil {
+					ct := r.ContentType()
+					if ct != "" && !strings.HasPrefix(ct, "application/octet-stream") && !strings.HasPrefix(ct, "binary/octet-stream") {
+						a.mediatype = mediatype.OfString(ct)
+					}
+				}
+			}
+
+			if a.mediatype == nil { // Still nothing found
+				a.mediatype = &mediatype.Binary
+			}
+		}
+	}
+	return *a.mediatype
+}
+
+// CreateFetcher implements PublicationAsset
+func (a *HTTPAsset) CreateFetcher(dependencies Dependencies, credentials string) (fetcher.Fetcher, error) {
+	r, err := a.open()
+	if err != nil {
+		return nil, err
+	}
+	mt := a.MediaType()
+	if mt.IsZIP() || mt.Equal(&mediatype.Binary) {
+		arc, err := dependencies.ArchiveFactory.OpenReader(r, r.Size(), "", true)
+		if err == nil {
+			return fetcher.NewArchiveFetcher(arc), nil
+		}
+		if mt.IsZIP() {
+			return nil, errors.Wrap(err, "failed opening remote archive")
+		}
+		// An unknown file which is not an archive is served as is
+	}
+
+	href, err := manifest.NewHREFFromString(a.Name(), false)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid name for remote file")
+	}
+	return &httpFetcher{
+		link:   manifest.Link{Href: href, MediaType: &mt},
+		reader: r,
+	}, nil
+}
+
+func (a *HTTPAsset) open() (*HTTPRangeReader, error) {
+	if a.reader == nil {
+		r, err := NewHTTPRangeReader(a.client, a.url.String(), 0, 0)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed opening remote file "+a.url.String())
+		}
+		a.reader = r
+	}
+	return a.reader, nil
+}
@@ -0,0 +1,221 @@
+package asset
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/util/url"
+	"github.com/stretchr/testify/assert"
+)
+
+func testZIP(t *testing.T) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	assert.NoError(t, err)
+	w.Write([]byte("application/epub+zip"))
+	w, err = zw.Create("OEBPS/chapter.xhtml")
+	assert.NoError(t, err)
+	w.Write([]byte(strings.Repeat("<p>Hello world</p>", 1000)))
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func testRangeServer(data []byte, requests *atomic.Int32, ranges bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !ranges {
+			r.Header.Del("Range")
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+	}))
+}
+
+func TestHTTPRangeReaderReadAt(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	var requests atomic.Int32
+	srv := testRangeServer(data, &requests, true)
+	defer srv.Close()
+
+	r, err := NewHTTPRangeReader(srv.Client(), srv.URL, 64, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualValues(t, len(data), r.Size())
+	assert.EqualValues(t, 1, requests.Load(), "the first block is fetched when opening the reader")
+
+	// Spans five missing blocks, fetched with a single request
+	buf := make([]byte, 300)
+	n, err := r.ReadAt(buf, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 300, n)
+	assert.Equal(t, data[100:400], buf)
+	assert.EqualValues(t, 2, requests.Load())
+
+	// Served from the cache
+	n, err = r.ReadAt(buf[:50], 10)
+	assert.NoError(t, err)
+	assert.Equal(t, data[10:60], buf[:50])
+	n, err = r.ReadAt(buf[:100], 200)
+	assert.NoError(t, err)
+	assert.Equal(t, data[200:300], buf[:100])
+	assert.EqualValues(t, 2, requests.Load())
+
+	// Reading past the end
+	n, err = r.ReadAt(buf, 900)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 100, n)
+	assert.Equal(t, data[900:], buf[:n])
+	_, err = r.ReadAt(buf, 1000)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestHTTPRangeReaderEvictsBlocks(t *testing.T) {
+	data := bytes.Repeat([]byte("abcdefgh"), 64)
+	var requests atomic.Int32
+	srv := testRangeServer(data, &requests, true)
+	defer srv.Close()
+
+	r, err := NewHTTPRangeReader(srv.Client(), srv.URL, 16, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	buf := make([]byte, 16)
+	r.ReadAt(buf, 100)
+	r.ReadAt(buf, 200)
+	assert.EqualValues(t, 3, requests.Load())
+	r.ReadAt(buf, 0) // Evicted by the two previous reads
+	assert.EqualValues(t, 4, requests.Load())
+	assert.Equal(t, data[:16], buf)
+}
+
+func TestHTTPRangeReaderRequiresRangeSupport(t *testing.T) {
+	var requests atomic.Int32
+	srv := testRangeServer([]byte("data"), &requests, false)
+	defer srv.Close()
+
+	_, err := NewHTTPRangeReader(srv.Client(), srv.URL, 0, 0)
+	assert.Error(t, err)
+}
+
+func TestHTTPAssetOpensRemoteZIP(t *testing.T) {
+	var requests atomic.Int32
+	srv := testRangeServer(testZIP(t), &requests, true)
+	defer srv.Close()
+
+	u, err := url.AbsoluteURLFromString(srv.URL + "/books/book.epub")
+	if !assert.NoError(t, err) {
+		return
+	}
+	a := HTTP(u, srv.Client())
+	assert.Equal(t, "book.epub", a.Name())
+	assert.Equal(t, mediatype.EPUB, a.MediaType())
+
+	f, err := a.CreateFetcher(Dependencies{ArchiveFactory: archive.NewArchiveFactory()}, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	res := f.Get(manifest.Link{Href: manifest.MustNewHREFFromString("mimetype", false)})
+	str, rerr := res.ReadAsString()
+	assert.Nil(t, rerr)
+	assert.Equal(t, "application/epub+zip", str)
+
+	res = f.Get(manifest.Link{Href: manifest.MustNewHREFFromString("OEBPS/chapter.xhtml", false)})
+	bin, rerr := res.Read(0, 0)
+	assert.Nil(t, rerr)
+	assert.Equal(t, strings.Repeat("<p>Hello world</p>", 1000), string(bin))
+}
+
+func TestHTTPAssetMediaTypeFromServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader("%PDF-1.7"))
+	}))
+	defer srv.Close()
+
+	u, _ := url.AbsoluteURLFromString(srv.URL + "/download")
+	assert.Equal(t, mediatype.PDF, HTTP(u, srv.Client()).MediaType())
+}
+
+func TestHTTPRangeReaderEmptyFile(t *testing.T) {
+	// Like object storages, which can't satisfy any range of an empty object
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Range", "bytes */0")
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+	}))
+	defer srv.Close()
+
+	r, err := NewHTTPRangeReader(srv.Client(), srv.URL, 0, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualValues(t, 0, r.Size())
+	n, err := r.ReadAt(make([]byte, 10), 0)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestHTTPAssetServesSingleFile(t *testing.T) {
+	data := []byte("%PDF-1.7 " + strings.Repeat("0123456789", 100))
+	var requests atomic.Int32
+	srv := testRangeServer(data, &requests, true)
+	defer srv.Close()
+
+	u, err := url.AbsoluteURLFromString(srv.URL + "/books/book.pdf")
+	if !assert.NoError(t, err) {
+		return
+	}
+	f, err := HTTP(u, srv.Client()).CreateFetcher(Dependencies{ArchiveFactory: archive.NewArchiveFactory()}, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	links, err := f.Links()
+	assert.NoError(t, err)
+	if assert.Len(t, links, 1) {
+		assert.Equal(t, "book.pdf", links[0].Href.String())
+		assert.Equal(t, &mediatype.PDF, links[0].MediaType)
+	}
+
+	res := f.Get(manifest.Link{Href: manifest.MustNewHREFFromString("book.pdf", false)})
+	length, rerr := res.Length()
+	assert.Nil(t, rerr)
+	assert.EqualValues(t, len(data), length)
+	bin, rerr := res.Read(0, 0)
+	assert.Nil(t, rerr)
+	assert.Equal(t, data, bin)
+	bin, rerr = res.Read(1, 4)
+	assert.Nil(t, rerr)
+	assert.Equal(t, "PDF-", string(bin))
+	bin, rerr = res.Read(1000, 2000)
+	assert.Nil(t, rerr)
+	assert.Equal(t, data[1000:], bin)
+
+	var buf bytes.Buffer
+	n, rerr := res.Stream(&buf, 5, 0)
+	assert.NotNil(t, rerr)
+	n, rerr = res.Stream(&buf, 0, 0)
+	assert.Nil(t, rerr)
+	assert.EqualValues(t, len(data), n)
+	assert.Equal(t, data, buf.Bytes())
+
+	_, rerr = f.Get(manifest.Link{Href: manifest.MustNewHREFFromString("other.pdf", false)}).Read(0, 0)
+	if assert.NotNil(t, rerr) {
+		assert.Equal(t, fetcher.CodeNotFound, rerr.Code)
+	}
+}
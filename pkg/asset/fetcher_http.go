@@ -0,0 +1,120 @@
+package asset
+
+import (
+	"errors"
+	"io"
+
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/xmlquery"
+)
+
+// Provides access to a single remote file, read lazily using range requests.
+type httpFetcher struct {
+	link   manifest.Link
+	reader *HTTPRangeReader
+}
+
+// Links implements Fetcher
+func (f *httpFetcher) Links() (manifest.LinkList, error) {
+	return manifest.LinkList{f.link}, nil
+}
+
+// Get implements Fetcher
+func (f *httpFetcher) Get(link manifest.Link) fetcher.Resource {
+	if link.Href.String() != f.link.Href.String() {
+		return fetcher.NewFailureResource(link, fetcher.NotFound(errors.New("couldn't find "+link.Href.String()+" in remote file")))
+	}
+	return &httpResource{link: link, reader: f.reader}
+}
+
+// Close implements Fetcher
+func (f *httpFetcher) Close() {
+	f.reader.Close()
+}
+
+// A [fetcher.Resource] serving the content of a remote file.
+type httpResource struct {
+	link   manifest.Link
+	reader *HTTPRangeReader
+}
+
+// File implements Resource
+func (r *httpResource) File() string {
+	return ""
+}
+
+// Close implements Resource
+func (r *httpResource) Close() {}
+
+// Link implements Resource
+func (r *httpResource) Link() manifest.Link {
+	return r.link
+}
+
+// Properties implements Resource
+func (r *httpResource) Properties() manifest.Properties {
+	return manifest.Properties{}
+}
+
+// Length implements Resource
+func (r *httpResource) Length() (int64, *fetcher.ResourceError) {
+	return r.reader.Size(), nil
+}
+
+// Returns the inclusive range of bytes to read, clamped to the size of the file.
+// The range is empty when end < start.
+func (r *httpResource) clamp(start int64, end int64) (int64, int64) {
+	if start == 0 && end == 0 {
+		return 0, r.reader.Size() - 1
+	}
+	return max(start, 0), min(end, r.reader.Size()-1)
+}
+
+// Read implements Resource
+func (r *httpResource) Read(start int64, end int64) ([]byte, *fetcher.ResourceError) {
+	if end < start {
+		return nil, fetcher.RangeNotSatisfiable(errors.New("end of range smaller than start"))
+	}
+	start, end = r.clamp(start, end)
+	if end < start {
+		return []byte{}, nil
+	}
+	data := make([]byte, end-start+1)
+	n, err := r.reader.ReadAt(data, start)
+	if err != nil && err != io.EOF {
+		return nil, fetcher.Unavailable(err)
+	}
+	return data[:n], nil
+}
+
+// Stream implements Resource
+func (r *httpResource) Stream(w io.Writer, start int64, end int64) (int64, *fetcher.ResourceError) {
+	if end < start {
+		return -1, fetcher.RangeNotSatisfiable(errors.New("end of range smaller than start"))
+	}
+	start, end = r.clamp(start, end)
+	if end < start {
+		return 0, nil
+	}
+	n, err := io.Copy(w, io.NewSectionReader(r.reader, start, end-start+1))
+	if err != nil {
+		return n, fetcher.Unavailable(err)
+	}
+	return n, nil
+}
+
+// ReadAsString implements Resource
+func (r *httpResource) ReadAsString() (string, *fetcher.ResourceError) {
+	return fetcher.ReadResourceAsString(r)
+}
+
+// ReadAsJSON implements Resource
+func (r *httpResource) ReadAsJSON() (map[string]interface{}, *fetcher.ResourceError) {
+	return fetcher.ReadResourceAsJSON(r)
+}
+
+// ReadAsXML implements Resource
+func (r *httpResource) ReadAsXML(prefixes map[string]string) (*xmlquery.Node, *fetcher.ResourceError) {
+	return fetcher.ReadResourceAsXML(r, prefixes)
+}
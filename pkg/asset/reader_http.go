@@ -0,0 +1,332 @@
+package asset
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultHTTPBlockSize = 64 * 1024 // Size of the blocks fetched and cached by a [HTTPRangeReader].
+	DefaultHTTPMaxBlocks = 256       // Maximum number of blocks kept in a [HTTPRangeReader]'s cache.
+)
+
+// An [io.ReaderAt] reading a remote file using HTTP range requests.
+// The file is split into fixed-size blocks which are kept in an LRU cache.
+// Contiguous missing blocks needed by a single read are fetched with a single
+// request, and concurrent reads of the same block share the same request.
+type HTTPRangeReader struct {
+	client      *http.Client
+	url         string
+	size        int64
+	contentType string
+	blockSize   int64
+	maxBlocks   int
+
+	mu      sync.Mutex
+	blocks  map[int64]*list.Element
+	lru     *list.List
+	pending map[int64]chan struct{}
+}
+
+// Returned by the server when the requested range starts past the end of the file.
+var errRangeNotSatisfiable = errors.New("range not satisfiable")
+
+type httpBlock struct {
+	index int64
+	data  []byte
+}
+
+// Creates a [HTTPRangeReader] for the file at the given URL.
+// The first block of the file is requested immediately to check that the server
+// supports range requests, and to learn the total size of the file.
+// A zero [blockSize] or [maxBlocks] falls back on the default values.
+func NewHTTPRangeReader(client *http.Client, url string, blockSize int64, maxBlocks int) (*HTTPRangeReader, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if blockSize <= 0 {
+		blockSize = DefaultHTTPBlockSize
+	}
+	if maxBlocks <= 0 {
+		maxBlocks = DefaultHTTPMaxBlocks
+	}
+	r := &HTTPRangeReader{
+		client:    client,
+		url:       url,
+		blockSize: blockSize,
+		maxBlocks: maxBlocks,
+		blocks:    make(map[int64]*list.Element),
+		lru:       list.New(),
+		pending:   make(map[int64]chan struct{}),
+	}
+
+	res, err := r.request(0, blockSize-1)
+	if errors.Is(err, errRangeNotSatisfiable) {
+		// Only an empty file can't satisfy a range starting at 0
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	_, _, total, err := parseContentRange(res.Header.Get("Content-Range"))
+	if err != nil {
+		return nil, err
+	}
+	if total < 0 {
+		return nil, errors.New("server did not report the size of the remote file")
+	}
+	r.size = total
+	r.contentType = res.Header.Get("Content-Type")
+
+	first := make([]byte, min(blockSize, total))
+	if _, err := io.ReadFull(res.Body, first); err != nil {
+		return nil, errors.Wrap(err, "failed reading first block of remote file")
+	}
+	r.mu.Lock()
+	r.store(0, first)
+	r.mu.Unlock()
+
+	return r, nil
+}
+
+// Size returns the total size of the remote file.
+func (r *HTTPRangeReader) Size() int64 {
+	return r.size
+}
+
+// ContentType returns the Content-Type header sent by the server, if any.
+func (r *HTTPRangeReader) ContentType() string {
+	return r.contentType
+}
+
+// ReadAt implements io.ReaderAt
+func (r *HTTPRangeReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	end := min(off+int64(len(p)), r.size) // Exclusive
+	first := off / r.blockSize
+	last := (end - 1) / r.blockSize
+
+	blocks, err := r.blocksFor(first, last)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for i := first; i <= last; i++ {
+		data := blocks[i]
+		blockStart := i * r.blockSize
+		from := max(off, blockStart) - blockStart
+		to := min(end, blockStart+int64(len(data))) - blockStart
+		n += copy(p[n:], data[from:to])
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close implements io.Closer
+// The cache is emptied, but the reader can still be used afterwards.
+func (r *HTTPRangeReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blocks = make(map[int64]*list.Element)
+	r.lru.Init()
+	return nil
+}
+
+// Returns the data of the blocks in the [first, last] range, fetching the missing ones.
+func (r *HTTPRangeReader) blocksFor(first, last int64) (map[int64][]byte, error) {
+	result := make(map[int64][]byte, last-first+1)
+	waits := make(map[int64]chan struct{})
+	var runs [][2]int64
+
+	r.mu.Lock()
+	for i := first; i <= last; i++ {
+		if data := r.load(i); data != nil {
+			result[i] = data
+		} else if ch, ok := r.pending[i]; ok {
+			waits[i] = ch
+		} else {
+			// Either extend the current run of missing blocks, or start a new one
+			if len(runs) > 0 && runs[len(runs)-1][1] == i-1 {
+				runs[len(runs)-1][1] = i
+			} else {
+				runs = append(runs, [2]int64{i, i})
+			}
+			r.pending[i] = make(chan struct{})
+		}
+	}
+	r.mu.Unlock()
+
+	var ferr error
+	for _, run := range runs {
+		fetched, err := r.fetch(run[0], run[1])
+
+		r.mu.Lock()
+		for i := run[0]; i <= run[1]; i++ {
+			if err == nil {
+				r.store(i, fetched[i])
+				result[i] = fetched[i]
+			}
+			close(r.pending[i])
+			delete(r.pending, i)
+		}
+		r.mu.Unlock()
+
+		if err != nil && ferr == nil {
+			ferr = err
+		}
+	}
+	if ferr != nil {
+		return nil, ferr
+	}
+
+	for i, ch := range waits {
+		<-ch
+		r.mu.Lock()
+		data := r.load(i)
+		r.mu.Unlock()
+		if data == nil {
+			// The other request failed, or the block was already evicted
+			fetched, err := r.fetch(i, i)
+			if err != nil {
+				return nil, err
+			}
+			data = fetched[i]
+		}
+		result[i] = data
+	}
+
+	return result, nil
+}
+
+// Fetches the blocks in the [first, last] range using a single range request.
+func (r *HTTPRangeReader) fetch(first, last int64) (map[int64][]byte, error) {
+	start := first * r.blockSize
+	end := min((last+1)*r.blockSize, r.size) - 1
+
+	res, err := r.request(start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	rstart, rend, _, err := parseContentRange(res.Header.Get("Content-Range"))
+	if err != nil {
+		return nil, err
+	}
+	if rstart != start || rend != end {
+		return nil, fmt.Errorf("server returned range %d-%d instead of %d-%d", rstart, rend, start, end)
+	}
+
+	data := make([]byte, end-start+1)
+	if _, err := io.ReadFull(res.Body, data); err != nil {
+		return nil, errors.Wrap(err, "failed reading remote range")
+	}
+
+	blocks := make(map[int64][]byte, last-first+1)
+	for i := first; i <= last; i++ {
+		from := (i - first) * r.blockSize
+		to := min(from+r.blockSize, int64(len(data)))
+		blocks[i] = data[from:to]
+	}
+	return blocks, nil
+}
+
+func (r *HTTPRangeReader) request(start, end int64) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", "bytes="+strconv.FormatInt(start, 10)+"-"+strconv.FormatInt(end, 10))
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed requesting remote range")
+	}
+	if res.StatusCode != http.StatusPartialContent {
+		res.Body.Close()
+		if res.StatusCode == http.StatusOK {
+			return nil, errors.New("server does not support range requests")
+		}
+		if res.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			return nil, errRangeNotSatisfiable
+		}
+		return nil, errors.New("unexpected HTTP status " + res.Status + " for range request")
+	}
+	return res, nil
+}
+
+// Must be called with the lock held.
+func (r *HTTPRangeReader) load(index int64) []byte {
+	el, ok := r.blocks[index]
+	if !ok {
+		return nil
+	}
+	r.lru.MoveToFront(el)
+	return el.Value.(*httpBlock).data
+}
+
+// Must be called with the lock held.
+func (r *HTTPRangeReader) store(index int64, data []byte) {
+	if el, ok := r.blocks[index]; ok {
+		el.Value.(*httpBlock).data = data
+		r.lru.MoveToFront(el)
+		return
+	}
+	r.blocks[index] = r.lru.PushFront(&httpBlock{index: index, data: data})
+	for r.lru.Len() > r.maxBlocks {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.blocks, oldest.Value.(*httpBlock).index)
+	}
+}
+
+// Parses a Content-Range header such as "bytes 0-499/1234".
+// The total is -1 when unknown ("*").
+func parseContentRange(header string) (start, end, total int64, err error) {
+	rest, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, 0, 0, errors.New("invalid Content-Range header \"" + header + "\"")
+	}
+	rng, size, ok := strings.Cut(rest, "/")
+	if !ok {
+		return 0, 0, 0, errors.New("invalid Content-Range header \"" + header + "\"")
+	}
+	rawStart, rawEnd, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, 0, 0, errors.New("invalid Content-Range header \"" + header + "\"")
+	}
+	if start, err = strconv.ParseInt(rawStart, 10, 64); err != nil {
+		return 0, 0, 0, errors.Wrap(err, "invalid Content-Range start")
+	}
+	if end, err = strconv.ParseInt(rawEnd, 10, 64); err != nil {
+		return 0, 0, 0, errors.Wrap(err, "invalid Content-Range end")
+	}
+	if size == "*" {
+		total = -1
+	} else if total, err = strconv.ParseInt(size, 10, 64); err != nil {
+		return 0, 0, 0, errors.Wrap(err, "invalid Content-Range size")
+	}
+	return start, end, total, nil
+}
@@ -60,10 +60,52 @@ type EmbeddedElement interface {
 	EmbeddedLink() manifest.Link // Referenced resource in the publication.
 }
 
+// Kind of a [TextTrack], from the kind attribute of the HTML <track> element.
+type TextTrackKind string
+
+const (
+	TextTrackKindCaptions  TextTrackKind = "captions"
+	TextTrackKindSubtitles TextTrackKind = "subtitles"
+)
+
+// Timed text track of an audio or video clip, such as captions or subtitles.
+type TextTrack struct {
+	Link     manifest.Link                     `json:"link"`               // WebVTT or SubRip file of the track.
+	Kind     TextTrackKind                     `json:"kind"`               // Kind of the track.
+	Language string                            `json:"language,omitempty"` // Language of the track.
+	Label    string                            `json:"label,omitempty"`    // Human-readable title of the track.
+	Guided   []manifest.GuidedNavigationObject `json:"guided,omitempty"`   // Cues of the track, synchronized with the clip.
+}
+
+// Text returns the plain text of all the cues of the track.
+func (t TextTrack) Text() string {
+	texts := make([]string, 0, len(t.Guided))
+	for _, o := range t.Guided {
+		if o.Text != "" {
+			texts = append(texts, o.Text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+// Falls back on the text of the first track with cues, when there's no accessibility label.
+func textOfTracks(label string, tracks []TextTrack) string {
+	if label != "" {
+		return label
+	}
+	for _, t := range tracks {
+		if text := t.Text(); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
 // An audio clip.
 type AudioElement struct {
 	locator      manifest.Locator
 	embeddedLink manifest.Link
+	tracks       []TextTrack
 	AttributesHolder
 }
 
@@ -77,15 +119,23 @@ func (e AudioElement) EmbeddedLink() manifest.Link {
 	return e.embeddedLink
 }
 
+// Captions and subtitles of the clip.
+func (e AudioElement) Tracks() []TextTrack {
+	return e.tracks
+}
+
 // Implements TextualElement
 func (e AudioElement) Text() string {
-	return e.AccessibilityLabel()
+	return textOfTracks(e.AccessibilityLabel(), e.tracks)
 }
 
 func (e AudioElement) MarshalJSON() ([]byte, error) {
 	res := ElementToMap(e)
 	res["text"] = e.Text()
 	res["link"] = e.EmbeddedLink()
+	if len(e.tracks) > 0 {
+		res["tracks"] = e.tracks
+	}
 	res["@type"] = "Video"
 	return json.Marshal(res)
 }
@@ -100,10 +150,17 @@ func NewAudioElement(locator manifest.Locator, embeddedLink manifest.Link, attri
 	}
 }
 
+// Returns a copy of the element with the given captions and subtitles.
+func (e AudioElement) WithTracks(tracks []TextTrack) AudioElement {
+	e.tracks = tracks
+	return e
+}
+
 // A video clip.
 type VideoElement struct {
 	locator      manifest.Locator
 	embeddedLink manifest.Link
+	tracks       []TextTrack
 	AttributesHolder
 }
 
@@ -117,15 +174,23 @@ func (e VideoElement) EmbeddedLink() manifest.Link {
 	return e.embeddedLink
 }
 
+// Captions and subtitles of the clip.
+func (e VideoElement) Tracks() []TextTrack {
+	return e.tracks
+}
+
 // Implements TextualElement
 func (e VideoElement) Text() string {
-	return e.AccessibilityLabel()
+	return textOfTracks(e.AccessibilityLabel(), e.tracks)
 }
 
 func (e VideoElement) MarshalJSON() ([]byte, error) {
 	res := ElementToMap(e)
 	res["text"] = e.Text()
 	res["link"] = e.EmbeddedLink()
+	if len(e.tracks) > 0 {
+		res["tracks"] = e.tracks
+	}
 	res["@type"] = "Video"
 	return json.Marshal(res)
 }
@@ -140,6 +205,12 @@ func NewVideoElement(locator manifest.Locator, embeddedLink manifest.Link, attri
 	}
 }
 
+// Returns a copy of the element with the given captions and subtitles.
+func (e VideoElement) WithTracks(tracks []TextTrack) VideoElement {
+	e.tracks = tracks
+	return e
+}
+
 // A bitmap image.
 // The caption is a short piece of text associated with the image.
 type ImageElement struct {
@@ -220,6 +291,11 @@ func (e TextElement) Role() TextRole {
 	return e.role
 }
 
+// Segments returns the ranged portions of text of the element.
+func (e TextElement) Segments() []TextSegment {
+	return e.segments
+}
+
 func (e TextElement) MarshalJSON() ([]byte, error) {
 	res := ElementToMap(e)
 	res["role"] = e.role.Role()
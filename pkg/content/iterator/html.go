@@ -16,7 +16,8 @@ import (
 type HTMLContentIterator struct {
 	resource        fetcher.Resource
 	locator         manifest.Locator
-	BeforeMaxLength int // Locators will contain a `before` context of up to this amount of characters.
+	BeforeMaxLength int             // Locators will contain a `before` context of up to this amount of characters.
+	Fetcher         fetcher.Fetcher // Used to read the captions of audio and video clips. Optional.
 
 	currentElement *ElementWithDelta
 	currentIndex   *int
@@ -35,9 +36,17 @@ func NewHTML(resource fetcher.Resource, locator manifest.Locator) *HTMLContentIt
 }
 
 func HTMLFactory() ResourceContentIteratorFactory {
+	return HTMLFactoryWithFetcher(nil)
+}
+
+// Creates HTML content iterators reading the captions of audio and video clips with the
+// publication fetcher [f].
+func HTMLFactoryWithFetcher(f fetcher.Fetcher) ResourceContentIteratorFactory {
 	return func(resource fetcher.Resource, locator manifest.Locator) Iterator {
 		if resource.Link().MediaType.Matches(&mediatype.HTML, &mediatype.XHTML) {
-			return NewHTML(resource, locator)
+			it := NewHTML(resource, locator)
+			it.Fetcher = f
+			return it
 		}
 		return nil
 	}
@@ -148,6 +157,7 @@ func (it *HTMLContentIterator) parseElements() (*ParsedElements, error) {
 	contentConverter := HTMLConverter{
 		baseLocator:     it.locator,
 		beforeMaxLength: it.BeforeMaxLength,
+		fetcher:         it.Fetcher,
 	}
 	if sel := it.locator.Locations.CSSSelector(); sel != "" {
 		c, err := cascadia.Parse(sel)
@@ -2,14 +2,17 @@ package iterator
 
 import (
 	nurl "net/url"
+	"path"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/readium/go-toolkit/pkg/content/element"
+	"github.com/readium/go-toolkit/pkg/fetcher"
 	iutil "github.com/readium/go-toolkit/pkg/internal/util"
 	"github.com/readium/go-toolkit/pkg/manifest"
 	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/util/alignment"
 	"github.com/readium/go-toolkit/pkg/util/url"
 	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
@@ -281,6 +284,7 @@ type HTMLConverter struct {
 	baseLocator     manifest.Locator
 	startElement    *html.Node
 	beforeMaxLength int
+	fetcher         fetcher.Fetcher // Reads the text tracks of audio and video clips, when available.
 
 	elements   []element.Element
 	startIndex int
@@ -378,11 +382,11 @@ func (c *HTMLConverter) Head(n *html.Node, depth int) {
 					}
 				} else {
 					sourceNodes := childrenOfType(n, atom.Source, 1)
-					sources := make([]manifest.Link, len(sourceNodes))
+					sources := make([]manifest.Link, 0, len(sourceNodes))
 					for _, source := range sourceNodes {
 						if src := srcRelativeToHref(source, c.baseLocator.Href); src != nil {
 							l := manifest.Link{
-								Href: manifest.NewHREF(href),
+								Href: manifest.NewHREF(src),
 							}
 							if typ := getAttr(source, "type"); typ != "" {
 								if mt, err := mediatype.NewOfString(typ); err == nil {
@@ -401,18 +405,19 @@ func (c *HTMLConverter) Head(n *html.Node, depth int) {
 				}
 
 				if link != nil {
+					tracks := c.textTracks(n, *link)
 					if n.DataAtom == atom.Audio {
 						c.elements = append(c.elements, element.NewAudioElement(
 							elementLocator,
 							*link,
 							[]element.Attribute[any]{},
-						))
+						).WithTracks(tracks))
 					} else if n.DataAtom == atom.Video {
 						c.elements = append(c.elements, element.NewVideoElement(
 							elementLocator,
 							*link,
 							[]element.Attribute[any]{},
-						))
+						).WithTracks(tracks))
 					}
 				}
 			}
@@ -424,6 +429,88 @@ func (c *HTMLConverter) Head(n *html.Node, depth int) {
 	}
 }
 
+// Collects the captions and subtitles of the audio or video clip [n] from its <track> children.
+// Their cues are converted to guided navigation objects referencing the [media] clip, when the
+// track files can be read.
+func (c *HTMLConverter) textTracks(n *html.Node, media manifest.Link) []element.TextTrack {
+	var tracks []element.TextTrack
+	for _, t := range childrenOfType(n, atom.Track, 1) {
+		kind := element.TextTrackKind(strings.ToLower(strings.TrimSpace(getAttr(t, "kind"))))
+		if kind == "" {
+			// The missing value default of the kind attribute
+			kind = element.TextTrackKindSubtitles
+		}
+		if kind != element.TextTrackKindCaptions && kind != element.TextTrackKindSubtitles {
+			continue
+		}
+		src := srcRelativeToHref(t, c.baseLocator.Href)
+		if src == nil {
+			continue
+		}
+
+		track := element.TextTrack{
+			Link:     manifest.Link{Href: manifest.NewHREF(src)},
+			Kind:     kind,
+			Language: getAttr(t, "srclang"),
+			Label:    getAttr(t, "label"),
+		}
+		if cues, mt := c.readTextTrack(track.Link); cues != nil {
+			track.Link.MediaType = mt
+			track.Guided = cuesToGuidedNavigationObjects(media.Href.String(), cues)
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks
+}
+
+// Reads and parses the WebVTT or SubRip file of a text track.
+// Returns nil when it can't be read, so the clip is still available without its captions.
+func (c *HTMLConverter) readTextTrack(link manifest.Link) ([]alignment.Cue, *mediatype.MediaType) {
+	if c.fetcher == nil {
+		return nil, nil
+	}
+	res := c.fetcher.Get(link)
+	defer res.Close()
+	data, rerr := res.Read(0, 0)
+	if rerr != nil {
+		return nil, nil
+	}
+
+	mt := res.Link().MediaType
+	if mt == nil || !mt.Matches(&mediatype.WebVTT, &mediatype.SubRip) {
+		mt = mediatype.OfExtension(strings.TrimPrefix(path.Ext(link.URL(nil, nil).Path()), "."))
+	}
+
+	var cues []alignment.Cue
+	var err error
+	if mt != nil && mt.Equal(&mediatype.SubRip) {
+		cues, err = alignment.ParseSRT(data)
+	} else {
+		mt = &mediatype.WebVTT
+		cues, err = alignment.ParseWebVTT(data)
+	}
+	if err != nil {
+		return nil, nil
+	}
+	return cues, mt
+}
+
+func cuesToGuidedNavigationObjects(media string, cues []alignment.Cue) []manifest.GuidedNavigationObject {
+	objects := make([]manifest.GuidedNavigationObject, 0, len(cues))
+	for _, cue := range cues {
+		end := cue.End
+		o := manifest.GuidedNavigationObject{
+			AudioRef: media + "#" + manifest.TemporalFragment{Start: cue.Start, End: &end}.String(),
+			Text:     cue.Text,
+		}
+		if len(cue.Children) > 0 {
+			o.Children = cuesToGuidedNavigationObjects(media, cue.Children)
+		}
+		objects = append(objects, o)
+	}
+	return objects
+}
+
 // Implements NodeTraversor
 func (c *HTMLConverter) Tail(n *html.Node, depth int) {
 	if n.Type == html.TextNode && !onlySpace(n.Data) {
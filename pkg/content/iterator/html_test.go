@@ -0,0 +1,92 @@
+package iterator
+
+import (
+	"path"
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/content/element"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLMediaTextTracks(t *testing.T) {
+	f := fetcher.NewBytesFetcher()
+	add := func(href string, content string) manifest.Link {
+		link := manifest.Link{Href: manifest.MustNewHREFFromString(href, false), MediaType: mediatype.OfExtension(path.Ext(href)[1:])}
+		f.Add(link, func() []byte { return []byte(content) })
+		return link
+	}
+	chapter := add("text/chapter.xhtml", `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><body>
+<video src="../video/intro.webm">
+	<track kind="captions" src="../video/intro.en.vtt" srclang="en" label="English" />
+	<track src="../video/intro.fr.srt" srclang="fr" />
+	<track kind="chapters" src="../video/chapters.vtt" />
+	<track kind="subtitles" src="missing.vtt" />
+</video>
+<audio><source src="../audio/song.mp3" type="audio/mpeg" /><source src="../audio/song.ogg" /></audio>
+</body></html>`)
+	add("video/intro.en.vtt", "WEBVTT\n\n00:00.000 --> 00:01.500\nCall me <00:00.500>Ishmael.\n\n00:01.500 --> 00:03.000\nSome years ago.\n")
+	add("video/intro.fr.srt", "1\n00:00:00,000 --> 00:00:01,500\nAppelez-moi Ismaël.\n")
+	add("video/chapters.vtt", "WEBVTT\n\n00:00.000 --> 00:03.000\nIntro\n")
+
+	it := HTMLFactoryWithFetcher(f)(f.Get(chapter), manifest.Locator{Href: chapter.URL(nil, nil), MediaType: mediatype.XHTML})
+	if !assert.NotNil(t, it) {
+		return
+	}
+	var elements []element.Element
+	for {
+		hasNext, err := it.HasNext()
+		if !assert.NoError(t, err) || !hasNext {
+			break
+		}
+		elements = append(elements, it.Next())
+	}
+	if !assert.Len(t, elements, 2) {
+		return
+	}
+
+	video, ok := elements[0].(element.VideoElement)
+	if !assert.True(t, ok) {
+		return
+	}
+	tracks := video.Tracks()
+	if assert.Len(t, tracks, 3) {
+		assert.Equal(t, element.TextTrackKindCaptions, tracks[0].Kind)
+		assert.Equal(t, "en", tracks[0].Language)
+		assert.Equal(t, "English", tracks[0].Label)
+		assert.Equal(t, "video/intro.en.vtt", tracks[0].Link.Href.String())
+		assert.Equal(t, &mediatype.WebVTT, tracks[0].Link.MediaType)
+		assert.Equal(t, []manifest.GuidedNavigationObject{
+			{AudioRef: "video/intro.webm#t=0,1.5", Text: "Call me Ishmael.", Children: []manifest.GuidedNavigationObject{
+				{AudioRef: "video/intro.webm#t=0,0.5", Text: "Call me"},
+				{AudioRef: "video/intro.webm#t=0.5,1.5", Text: "Ishmael."},
+			}},
+			{AudioRef: "video/intro.webm#t=1.5,3", Text: "Some years ago."},
+		}, tracks[0].Guided)
+
+		assert.Equal(t, element.TextTrackKindSubtitles, tracks[1].Kind)
+		assert.Equal(t, &mediatype.SubRip, tracks[1].Link.MediaType)
+		assert.Equal(t, []manifest.GuidedNavigationObject{
+			{AudioRef: "video/intro.webm#t=0,1.5", Text: "Appelez-moi Ismaël."},
+		}, tracks[1].Guided)
+
+		// Tracks which can't be read are kept without their cues.
+		assert.Equal(t, "text/missing.vtt", tracks[2].Link.Href.String())
+		assert.Nil(t, tracks[2].Guided)
+	}
+	assert.Equal(t, "Call me Ishmael. Some years ago.", video.Text())
+
+	audio, ok := elements[1].(element.AudioElement)
+	if assert.True(t, ok) {
+		link := audio.EmbeddedLink()
+		assert.Equal(t, "audio/song.mp3", link.Href.String())
+		assert.Equal(t, &mediatype.MP3, link.MediaType)
+		if assert.Len(t, link.Alternates, 1) {
+			assert.Equal(t, "audio/song.ogg", link.Alternates[0].Href.String())
+		}
+		assert.Empty(t, audio.Tracks())
+	}
+}
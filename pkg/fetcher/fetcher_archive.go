@@ -42,8 +42,10 @@ func (f *ArchiveFetcher) Links() (manifest.LinkList, error) {
 }
 
 // Get implements Fetcher
+// The entry is looked up with the unescaped path of the link's HREF, without its query and
+// fragment, e.g. a%20b.mp3#t=5 reads the entry "a b.mp3".
 func (f *ArchiveFetcher) Get(link manifest.Link) Resource {
-	entry, err := f.archive.Entry(link.Href.String())
+	entry, err := f.archive.Entry(link.URL(nil, nil).Path())
 	if err != nil {
 		return NewFailureResource(link, NotFound(err))
 	}
@@ -132,6 +134,11 @@ func (r *entryResource) Read(start int64, end int64) ([]byte, *ResourceError) {
 		return nil, RangeNotSatisfiable(errors.New("end of range smaller than start"))
 	}
 
+	// Corrupted data
+	if errors.Is(err, archive.ErrChecksumMismatch) {
+		return nil, Corrupted(err)
+	}
+
 	// Other error
 	return nil, Other(err)
 }
@@ -148,6 +155,11 @@ func (r *entryResource) Stream(w io.Writer, start int64, end int64) (int64, *Res
 		return -1, RangeNotSatisfiable(errors.New("end of range smaller than start"))
 	}
 
+	// Corrupted data
+	if errors.Is(err, archive.ErrChecksumMismatch) {
+		return n, Corrupted(err)
+	}
+
 	// Other error
 	return -1, Other(err)
 }
@@ -1,9 +1,12 @@
 package fetcher
 
 import (
+	"archive/zip"
 	"bytes"
+	"hash/crc32"
 	"testing"
 
+	"github.com/readium/go-toolkit/pkg/archive"
 	"github.com/readium/go-toolkit/pkg/manifest"
 	"github.com/readium/go-toolkit/pkg/mediatype"
 	"github.com/stretchr/testify/assert"
@@ -100,6 +103,50 @@ func TestArchiveFetcherRead(t *testing.T) {
 	})
 }
 
+func TestArchiveFetcherReadEscapedHref(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("audio book/a b.mp3")
+	assert.NoError(t, err)
+	w.Write([]byte("audio"))
+	assert.NoError(t, zw.Close())
+
+	a, err := archive.NewArchiveFactory().OpenBytes(buf.Bytes(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	f := NewArchiveFetcher(a)
+
+	// Percent-escaped HREFs resolve to the unescaped entry paths.
+	bin, rerr := f.Get(manifest.Link{Href: manifest.MustNewHREFFromString("audio%20book/a%20b.mp3", false)}).Read(0, 0)
+	if assert.Nil(t, rerr) {
+		assert.Equal(t, "audio", string(bin))
+	}
+
+	// The links of the fetcher can be read back.
+	links, err := f.Links()
+	if assert.NoError(t, err) && assert.Len(t, links, 1) {
+		assert.Equal(t, "audio%20book/a%20b.mp3", links[0].Href.String())
+		_, rerr = f.Get(links[0]).Read(0, 0)
+		assert.Nil(t, rerr)
+	}
+}
+
+func TestArchiveFetcherIgnoresQueryAndFragment(t *testing.T) {
+	withArchiveFetcher(t, func(a *ArchiveFetcher) {
+		for _, href := range []string{"mimetype?page=1", "mimetype#t=5", "mimetype?page=1#t=5"} {
+			link := manifest.Link{Href: manifest.MustNewHREFFromString(href, false)}
+			resource := a.Get(link)
+			bin, rerr := resource.Read(0, 0)
+			if assert.Nil(t, rerr, href) {
+				assert.Equal(t, "application/epub+zip", string(bin), href)
+			}
+			// The resource keeps the requested link.
+			assert.Equal(t, href, resource.Link().Href.String())
+		}
+	})
+}
+
 func TestArchiveFetcherReadRange(t *testing.T) {
 	withArchiveFetcher(t, func(a *ArchiveFetcher) {
 		resource := a.Get(manifest.Link{Href: manifest.MustNewHREFFromString("mimetype", false)})
@@ -116,6 +163,26 @@ func TestArchiveFetcherReadRange(t *testing.T) {
 	})
 }
 
+func TestArchiveFetcherReadRangeOutOfBounds(t *testing.T) {
+	withArchiveFetcher(t, func(a *ArchiveFetcher) {
+		resource := a.Get(manifest.Link{Href: manifest.MustNewHREFFromString("mimetype", false)})
+		bin, err := resource.Read(15, 100)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "b+zip", string(bin))
+		}
+		bin, err = resource.Read(30, 40)
+		if assert.Nil(t, err) {
+			assert.Empty(t, bin)
+		}
+		var b bytes.Buffer
+		n, err := resource.Stream(&b, 15, 100)
+		if assert.Nil(t, err) {
+			assert.EqualValues(t, 5, n)
+			assert.Equal(t, "b+zip", b.String())
+		}
+	})
+}
+
 func TestArchiveFetcherComputingLength(t *testing.T) {
 	withArchiveFetcher(t, func(a *ArchiveFetcher) {
 		resource := a.Get(manifest.Link{Href: manifest.MustNewHREFFromString("mimetype", false)})
@@ -152,3 +219,33 @@ func TestArchiveFetcherAddsProperties(t *testing.T) {
 		}, resource.Properties())
 	})
 }
+
+func TestArchiveFetcherChecksumMismatchIsCorrupted(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	data := []byte("corrupted")
+	w, err := zw.CreateRaw(&zip.FileHeader{
+		Name:               "corrupted.txt",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(data) + 1,
+		CompressedSize64:   uint64(len(data)),
+		UncompressedSize64: uint64(len(data)),
+	})
+	assert.NoError(t, err)
+	w.Write(data)
+	assert.NoError(t, zw.Close())
+
+	a, err := archive.NewVerifyingArchiveFactory().OpenBytes(buf.Bytes(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	resource := NewArchiveFetcher(a).Get(manifest.Link{Href: manifest.MustNewHREFFromString("corrupted.txt", false)})
+	_, rerr := resource.Read(0, 0)
+	if assert.NotNil(t, rerr) {
+		assert.Equal(t, CodeCorrupted, rerr.Code)
+	}
+	_, rerr = resource.Stream(&bytes.Buffer{}, 0, 0)
+	if assert.NotNil(t, rerr) {
+		assert.Equal(t, CodeCorrupted, rerr.Code)
+	}
+}
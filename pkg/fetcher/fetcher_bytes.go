@@ -0,0 +1,49 @@
+package fetcher
+
+import "github.com/readium/go-toolkit/pkg/manifest"
+
+// BytesFetcher serves resources generated in memory, such as the documents converted from a
+// publication format which can't be served as is.
+type BytesFetcher struct {
+	links   manifest.LinkList
+	loaders map[string]func() []byte // Indexed by path
+}
+
+// Add serves the bytes returned by [loader] at the HREF of [link].
+func (f *BytesFetcher) Add(link manifest.Link, loader func() []byte) {
+	p := link.URL(nil, nil).Path()
+	if _, ok := f.loaders[p]; !ok {
+		f.links = append(f.links, link)
+	}
+	f.loaders[p] = loader
+}
+
+// Links implements Fetcher
+func (f *BytesFetcher) Links() (manifest.LinkList, error) {
+	return f.links, nil
+}
+
+// Get implements Fetcher
+func (f *BytesFetcher) Get(link manifest.Link) Resource {
+	p := link.URL(nil, nil).Path()
+	loader, ok := f.loaders[p]
+	if !ok {
+		return NewFailureResource(link, NotFound(nil))
+	}
+	for _, l := range f.links {
+		if l.URL(nil, nil).Path() == p {
+			link = l
+			break
+		}
+	}
+	return NewBytesResource(link, loader)
+}
+
+// Close implements Fetcher
+func (f *BytesFetcher) Close() {}
+
+func NewBytesFetcher() *BytesFetcher {
+	return &BytesFetcher{
+		loaders: make(map[string]func() []byte),
+	}
+}
@@ -0,0 +1,42 @@
+package fetcher
+
+import (
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytesFetcherLinks(t *testing.T) {
+	f := NewBytesFetcher()
+	f.Add(manifest.Link{Href: manifest.MustNewHREFFromString("b.txt", false)}, func() []byte { return []byte("b") })
+	f.Add(manifest.Link{Href: manifest.MustNewHREFFromString("a.txt", false)}, func() []byte { return []byte("a") })
+	links, err := f.Links()
+	assert.NoError(t, err)
+	assert.Equal(t, manifest.LinkList{
+		{Href: manifest.MustNewHREFFromString("b.txt", false)},
+		{Href: manifest.MustNewHREFFromString("a.txt", false)},
+	}, links)
+}
+
+func TestBytesFetcherGet(t *testing.T) {
+	f := NewBytesFetcher()
+	f.Add(manifest.Link{
+		Href:      manifest.MustNewHREFFromString("dir/text%20file.txt", false),
+		MediaType: &mediatype.Text,
+	}, func() []byte { return []byte("text") })
+
+	resource := f.Get(manifest.Link{Href: manifest.MustNewHREFFromString("dir/text%20file.txt#fragment", false)})
+	bin, err := resource.Read(0, 0)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "text", string(bin))
+	}
+	assert.Equal(t, &mediatype.Text, resource.Link().MediaType)
+}
+
+func TestBytesFetcherGetNotFound(t *testing.T) {
+	resource := NewBytesFetcher().Get(manifest.Link{Href: manifest.MustNewHREFFromString("unknown", false)})
+	_, err := resource.Read(0, 0)
+	assert.Equal(t, NotFound(err.Cause), err)
+}
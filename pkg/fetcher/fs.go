@@ -0,0 +1,302 @@
+package fetcher
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/util/url"
+)
+
+// Exposes a [Fetcher] as a read-only [fs.FS], e.g. to be used with
+// http.FileServer or template.ParseFS.
+//
+// Directories are derived from the paths of the fetcher's [Fetcher.Links].
+// As these links are not exhaustive, a file which is not listed can still be
+// opened by its path, if the fetcher provides it.
+type FS struct {
+	fetcher Fetcher
+
+	once  sync.Once
+	files map[string]manifest.Link // Known files, by path
+	dirs  map[string][]string      // Sorted names of the children of each directory, by path
+	err   error
+}
+
+var (
+	_ fs.ReadFileFS = (*FS)(nil)
+	_ fs.ReadDirFS  = (*FS)(nil)
+	_ fs.StatFS     = (*FS)(nil)
+)
+
+func NewFS(f Fetcher) *FS {
+	return &FS{fetcher: f}
+}
+
+// Builds the tree of known files and directories from the links of the fetcher.
+func (f *FS) index() error {
+	f.once.Do(func() {
+		links, err := f.fetcher.Links()
+		if err != nil {
+			f.err = err
+			return
+		}
+		f.files = make(map[string]manifest.Link, len(links))
+		f.dirs = map[string][]string{".": {}}
+		for _, link := range links {
+			if link.Href.IsTemplated() {
+				continue
+			}
+			u := link.URL(nil, nil)
+			if _, ok := u.(url.AbsoluteURL); ok {
+				continue
+			}
+			p := path.Clean(strings.TrimPrefix(u.Path(), "/"))
+			if !fs.ValidPath(p) || p == "." {
+				continue
+			}
+			if _, ok := f.files[p]; ok {
+				continue
+			}
+			f.files[p] = link
+
+			// Register the file and its ancestors in their parent directories
+			for child := p; child != "."; {
+				parent := path.Dir(child)
+				_, exists := f.dirs[parent]
+				f.dirs[parent] = append(f.dirs[parent], path.Base(child))
+				if exists {
+					break
+				}
+				child = parent
+			}
+		}
+		for dir := range f.dirs {
+			slices.Sort(f.dirs[dir])
+			f.dirs[dir] = slices.Compact(f.dirs[dir])
+		}
+	})
+	return f.err
+}
+
+// Returns the link used to get the resource at the given path.
+func (f *FS) link(name string) (manifest.Link, error) {
+	if link, ok := f.files[name]; ok {
+		return link, nil
+	}
+	u, err := url.URLFromDecodedPath(name)
+	if err != nil {
+		return manifest.Link{}, err
+	}
+	return manifest.Link{Href: manifest.NewHREF(u)}, nil
+}
+
+// Open implements fs.FS
+func (f *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if err := f.index(); err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	if _, ok := f.dirs[name]; ok {
+		return &fsDir{fsys: f, name: name}, nil
+	}
+
+	link, err := f.link(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	res := f.fetcher.Get(link)
+	file := &fsFile{
+		ResourceReadSeeker: NewResourceReadSeeker(res),
+		name:               name,
+		link:               res.Link(),
+	}
+	// Makes sure the resource exists before handing it out
+	if _, err := file.Length(); err != nil {
+		res.Close()
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fsError(err)}
+	}
+	return file, nil
+}
+
+// ReadFile implements fs.ReadFileFS
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	if err := f.index(); err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	if _, ok := f.dirs[name]; ok {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+	}
+
+	link, err := f.link(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	res := f.fetcher.Get(link)
+	defer res.Close()
+	data, rerr := res.Read(0, 0)
+	if rerr != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fsError(rerr)}
+	}
+	return data, nil
+}
+
+// ReadDir implements fs.ReadDirFS
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if err := f.index(); err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	children, ok := f.dirs[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, child := range children {
+		info, err := f.Stat(path.Join(name, child))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	return entries, nil
+}
+
+// Stat implements fs.StatFS
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	file, err := f.Open(name)
+	if err != nil {
+		if perr, ok := err.(*fs.PathError); ok {
+			perr.Op = "stat"
+		}
+		return nil, err
+	}
+	defer file.Close()
+	return file.Stat()
+}
+
+// Converts a resource error to its fs equivalent, so that it can be checked with errors.Is.
+func fsError(err error) error {
+	var rerr *ResourceError
+	if errors.As(err, &rerr) {
+		switch rerr.Code {
+		case CodeNotFound:
+			return fs.ErrNotExist
+		case CodeForbidden:
+			return fs.ErrPermission
+		}
+	}
+	return err
+}
+
+// A resource opened from a [FS].
+type fsFile struct {
+	*ResourceReadSeeker
+	name string
+	link manifest.Link
+}
+
+// Stat implements fs.File
+func (f *fsFile) Stat() (fs.FileInfo, error) {
+	length, err := f.Length()
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: f.name, Err: fsError(err)}
+	}
+	return fsFileInfo{name: path.Base(f.name), size: length, link: f.link}, nil
+}
+
+// A directory opened from a [FS].
+type fsDir struct {
+	fsys   *FS
+	name   string
+	offset int
+}
+
+// Stat implements fs.File
+func (d *fsDir) Stat() (fs.FileInfo, error) {
+	return fsFileInfo{name: path.Base(d.name), dir: true}, nil
+}
+
+// Read implements fs.File
+func (d *fsDir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+// Close implements fs.File
+func (d *fsDir) Close() error {
+	return nil
+}
+
+// ReadDir implements fs.ReadDirFile
+func (d *fsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries, err := d.fsys.ReadDir(d.name)
+	if err != nil {
+		return nil, err
+	}
+	entries = entries[d.offset:]
+	if n <= 0 {
+		d.offset += len(entries)
+		return entries, nil
+	}
+	if len(entries) == 0 {
+		return nil, io.EOF
+	}
+	if n < len(entries) {
+		entries = entries[:n]
+	}
+	d.offset += len(entries)
+	return entries, nil
+}
+
+type fsFileInfo struct {
+	name string
+	size int64
+	dir  bool
+	link manifest.Link
+}
+
+func (i fsFileInfo) Name() string {
+	return i.name
+}
+
+func (i fsFileInfo) Size() int64 {
+	return i.size
+}
+
+func (i fsFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// Resources don't have a modification time.
+func (i fsFileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (i fsFileInfo) IsDir() bool {
+	return i.dir
+}
+
+// Returns the [manifest.Link] of the resource, or nil for directories.
+func (i fsFileInfo) Sys() any {
+	if i.dir {
+		return nil
+	}
+	return i.link
+}
@@ -0,0 +1,100 @@
+package fetcher
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFSArchiveFetcher(t *testing.T) {
+	// fstest reads every file one byte at a time, so a small archive is used
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"mimetype", "EPUB/package.opf", "EPUB/css/style.css", "META-INF/container.xml"} {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		w.Write([]byte("content of " + name))
+	}
+	assert.NoError(t, zw.Close())
+	a, err := archive.NewArchiveFactory().OpenBytes(buf.Bytes(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, fstest.TestFS(NewFS(NewArchiveFetcher(a)),
+		"mimetype",
+		"EPUB/package.opf",
+		"EPUB/css/style.css",
+		"META-INF/container.xml",
+	))
+}
+
+func TestFSReadDir(t *testing.T) {
+	withArchiveFetcher(t, func(a *ArchiveFetcher) {
+		fsys := NewFS(a)
+		entries, err := fs.ReadDir(fsys, "EPUB")
+		if assert.NoError(t, err) {
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			assert.Equal(t, []string{"cover.xhtml", "css", "images", "nav.xhtml", "package.opf", "s04.xhtml", "toc.ncx"}, names)
+			assert.True(t, entries[1].IsDir())
+		}
+
+		data, err := fs.ReadFile(fsys, "mimetype")
+		assert.NoError(t, err)
+		assert.Equal(t, "application/epub+zip", string(data))
+
+		matches, err := fs.Glob(fsys, "EPUB/css/*.css")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"EPUB/css/epub.css", "EPUB/css/nav.css"}, matches)
+	})
+}
+
+func TestFSFileInfo(t *testing.T) {
+	withArchiveFetcher(t, func(a *ArchiveFetcher) {
+		info, err := fs.Stat(NewFS(a), "EPUB/css/epub.css")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "epub.css", info.Name())
+			assert.EqualValues(t, 1473, info.Size())
+			assert.False(t, info.IsDir())
+			link, ok := info.Sys().(manifest.Link)
+			if assert.True(t, ok) {
+				assert.Equal(t, "text/css", link.MediaType.String())
+			}
+		}
+
+		info, err = fs.Stat(NewFS(a), "EPUB/css")
+		if assert.NoError(t, err) {
+			assert.True(t, info.IsDir())
+			assert.Equal(t, fs.ModeDir, info.Mode().Type())
+		}
+	})
+}
+
+func TestFSNotFound(t *testing.T) {
+	withArchiveFetcher(t, func(a *ArchiveFetcher) {
+		fsys := NewFS(a)
+		_, err := fsys.Open("EPUB/unknown.xhtml")
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+		_, err = fs.ReadFile(fsys, "unknown")
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+		_, err = fs.ReadDir(fsys, "unknown")
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+		_, err = fsys.Open("../mimetype")
+		assert.True(t, errors.Is(err, fs.ErrInvalid))
+	})
+}
+
+func TestFSFileFetcher(t *testing.T) {
+	fsys := NewFS(NewFileFetcher("", "./testdata/directory"))
+	assert.NoError(t, fstest.TestFS(fsys, "text1.txt", "subdirectory/text2.txt", "subdirectory/hello.mp3"))
+}
@@ -2,62 +2,117 @@ package fetcher
 
 import (
 	"errors"
+	"io"
 )
 
-// For opening a fetcher.Resource as a io.ReadSeeker
+// For opening a fetcher.Resource as an io.ReadSeekCloser and io.ReaderAt.
+//
+// [Resource.Length] is only a hint, so the length is resolved lazily the first
+// time it is needed (e.g. to seek from the end), and computed by reading the
+// whole resource when the hint is unavailable. Reads are always bounded by the
+// actual content of the resource, and return io.EOF once it is exhausted.
 type ResourceReadSeeker struct {
 	r      Resource
 	offset int64
 	length int64
+	known  bool // Whether length has been resolved
 }
 
+var (
+	_ io.ReadSeekCloser = (*ResourceReadSeeker)(nil)
+	_ io.ReaderAt       = (*ResourceReadSeeker)(nil)
+)
+
 func NewResourceReadSeeker(r Resource) *ResourceReadSeeker {
 	return &ResourceReadSeeker{
 		r: r,
 	}
 }
 
-// Seek implements io.ReadSeeker
-func (rs *ResourceReadSeeker) Seek(offset int64, whence int) (int64, error) {
-	switch whence {
-	case 0:
-		if offset < 0 {
-			return 0, errors.New("new offset smaller than zero")
-		}
-		rs.offset = offset
-		return rs.offset, nil
-	case 1:
-		if (rs.offset + offset) < 0 {
-			return 0, errors.New("new offset smaller than zero")
+// Length of the resource, see [ResourceReadSeeker] for how it is resolved.
+func (rs *ResourceReadSeeker) Length() (int64, error) {
+	if rs.known {
+		return rs.length, nil
+	}
+	length, rerr := rs.r.Length()
+	if rerr != nil {
+		if rerr.Code == CodeNotFound || rerr.Code == CodeForbidden {
+			return 0, rerr
 		}
-		rs.offset += offset
-		return rs.offset, nil
-	case 2:
-		if rs.length == 0 {
-			length, errx := rs.r.Length()
-			if errx != nil {
-				return 0, errx
-			}
-			rs.length = length
+		bin, rerr := rs.r.Read(0, 0)
+		if rerr != nil {
+			return 0, rerr
 		}
-		if (rs.length + offset) < 0 {
-			return 0, errors.New("new offset smaller than zero")
+		length = int64(len(bin))
+	}
+	rs.length = length
+	rs.known = true
+	return rs.length, nil
+}
+
+// Seek implements io.Seeker
+func (rs *ResourceReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += rs.offset
+	case io.SeekEnd:
+		length, err := rs.Length()
+		if err != nil {
+			return 0, err
 		}
-		rs.offset = rs.length + offset
-		return rs.offset, nil
+		offset += length
 	default:
-		panic("invalid whence value")
+		return 0, errors.New("invalid whence value")
+	}
+	if offset < 0 {
+		return 0, errors.New("new offset smaller than zero")
 	}
+	rs.offset = offset
+	return rs.offset, nil
 }
 
-// Seek implements io.ReadSeeker
+// Read implements io.Reader
 func (rs *ResourceReadSeeker) Read(p []byte) (n int, err error) {
-	bin, errx := rs.r.Read(rs.offset, rs.offset+int64(len(p)))
-	if errx != nil {
-		err = errx
-		return
+	n, err = rs.ReadAt(p, rs.offset)
+	rs.offset += int64(n)
+	if n > 0 && err == io.EOF {
+		err = nil // Reported by the next call
+	}
+	return
+}
+
+// ReadAt implements io.ReaderAt
+// It doesn't affect the offset used by Read and Seek.
+func (rs *ResourceReadSeeker) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if rs.known && off >= rs.length {
+		return 0, io.EOF
+	}
+
+	end := off + int64(len(p)) - 1
+	if end == 0 {
+		end = 1 // The range 0-0 means the whole resource
+	}
+	bin, rerr := rs.r.Read(off, end)
+	if rerr != nil {
+		return 0, rerr
 	}
 	n = copy(p, bin)
-	rs.offset += int64(n)
+	if n < len(p) {
+		err = io.EOF
+	}
 	return
 }
+
+// Close implements io.Closer
+// The underlying resource is closed.
+func (rs *ResourceReadSeeker) Close() error {
+	rs.r.Close()
+	return nil
+}
@@ -0,0 +1,100 @@
+package fetcher
+
+import (
+	"io"
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceReadSeekerReadAll(t *testing.T) {
+	withArchiveFetcher(t, func(a *ArchiveFetcher) {
+		rs := NewResourceReadSeeker(a.Get(manifest.Link{Href: manifest.MustNewHREFFromString("mimetype", false)}))
+		defer rs.Close()
+
+		// Small reads, so that the end of the resource is reached mid-buffer
+		bin, err := io.ReadAll(io.LimitReader(rs, 1000))
+		assert.NoError(t, err)
+		assert.Equal(t, "application/epub+zip", string(bin))
+
+		n, err := rs.Read(make([]byte, 4))
+		assert.Equal(t, 0, n)
+		assert.Equal(t, io.EOF, err)
+	})
+}
+
+func TestResourceReadSeekerSeek(t *testing.T) {
+	withArchiveFetcher(t, func(a *ArchiveFetcher) {
+		rs := NewResourceReadSeeker(a.Get(manifest.Link{Href: manifest.MustNewHREFFromString("mimetype", false)}))
+		defer rs.Close()
+
+		pos, err := rs.Seek(-8, io.SeekEnd)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 12, pos)
+		p := make([]byte, 4)
+		n, err := rs.Read(p)
+		assert.NoError(t, err)
+		assert.Equal(t, "epub", string(p[:n]))
+
+		pos, err = rs.Seek(-16, io.SeekCurrent)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 0, pos)
+		n, err = rs.Read(p[:1])
+		assert.NoError(t, err)
+		assert.Equal(t, "a", string(p[:n]))
+
+		_, err = rs.Seek(-1, io.SeekStart)
+		assert.Error(t, err)
+
+		length, err := rs.Length()
+		assert.NoError(t, err)
+		assert.EqualValues(t, 20, length)
+	})
+}
+
+func TestResourceReadSeekerReadAt(t *testing.T) {
+	withArchiveFetcher(t, func(a *ArchiveFetcher) {
+		rs := NewResourceReadSeeker(a.Get(manifest.Link{Href: manifest.MustNewHREFFromString("mimetype", false)}))
+		defer rs.Close()
+
+		p := make([]byte, 3)
+		n, err := rs.ReadAt(p, 12)
+		assert.NoError(t, err)
+		assert.Equal(t, "epu", string(p[:n]))
+
+		p = make([]byte, 10)
+		n, err = rs.ReadAt(p, 15)
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, "b+zip", string(p[:n]))
+
+		n, err = rs.ReadAt(p, 30)
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, 0, n)
+
+		// ReadAt doesn't move the offset
+		n, err = rs.Read(p[:1])
+		assert.NoError(t, err)
+		assert.Equal(t, "a", string(p[:n]))
+	})
+}
+
+func TestResourceReadSeekerLengthFallback(t *testing.T) {
+	rs := NewResourceReadSeeker(lengthlessResource{ProxyResource{Res: NewBytesResource(manifest.Link{}, func() []byte { return []byte("hello") })}})
+	length, err := rs.Length()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, length)
+
+	rs = NewResourceReadSeeker(NewFailureResource(manifest.Link{}, NotFound(nil)))
+	_, err = rs.Length()
+	assert.Equal(t, NotFound(nil), err)
+}
+
+// A resource whose length is unknown until it is read.
+type lengthlessResource struct {
+	ProxyResource
+}
+
+func (r lengthlessResource) Length() (int64, *ResourceError) {
+	return 0, Unavailable(nil)
+}
@@ -137,6 +137,7 @@ const (
 	_ ResourceErrorCode = iota + 1000 // Starts at 1k to not conflict with HTTP-based codes
 	Offline
 	Cancelled
+	CodeCorrupted // The data of the resource failed an integrity check, e.g. a checksum mismatch.
 )
 
 // Errors occurring while accessing a resource.
@@ -231,6 +232,14 @@ func Timeout(cause error) *ResourceError {
 	}
 }
 
+// Used when the data of the resource doesn't match its checksum.
+func Corrupted(cause error) *ResourceError {
+	return &ResourceError{
+		Code:  CodeCorrupted,
+		Cause: cause,
+	}
+}
+
 // The request was cancelled by the caller.
 // For example, when a coroutine is cancelled.
 // TODO
@@ -1,5 +1,11 @@
 package manifest
 
+import (
+	"slices"
+
+	"github.com/readium/go-toolkit/pkg/util/url"
+)
+
 // Readium Guided Navigation Document
 // https://readium.org/guided-navigation/schema/document.schema.json
 type GuidedNavigationDocument struct {
@@ -9,16 +15,128 @@ type GuidedNavigationDocument struct {
 
 // Readium Guided Navigation Object
 // https://readium.org/guided-navigation/schema/object.schema.json
-// TODO: Role should be typed
-// TODO: all refs should be url.URL
 type GuidedNavigationObject struct {
 	AudioRef string                   `json:"audioref,omitempty"` // References an audio resource or a fragment of it.
 	ImgRef   string                   `json:"imgref,omitempty"`   // References an image or a fragment of it.
 	TextRef  string                   `json:"textref,omitempty"`  // References a textual resource or a fragment of it.
 	Text     string                   `json:"text,omitempty"`     // Textual equivalent of the resources or fragment of the resources referenced by the current Guided Navigation Object.
-	Role     []string                 `json:"role,omitempty"`     // Convey the structural semantics of a publication
+	Role     []GuidedNavigationRole   `json:"role,omitempty"`     // Convey the structural semantics of a publication
 	Children []GuidedNavigationObject `json:"children,omitempty"` // Items that are children of the containing Guided Navigation Object.
 }
 
-// TODO: functions for objects to get e.g. audio time, audio file, text file, fragment id, audio "clip", image xywh, etc.
-// This will come after the URL utility revamp to avoid implementation twice
+// AudioURL returns the parsed [AudioRef], or nil if it is missing or invalid.
+func (o GuidedNavigationObject) AudioURL() url.URL {
+	return parseGuidedNavigationRef(o.AudioRef)
+}
+
+// ImgURL returns the parsed [ImgRef], or nil if it is missing or invalid.
+func (o GuidedNavigationObject) ImgURL() url.URL {
+	return parseGuidedNavigationRef(o.ImgRef)
+}
+
+// TextURL returns the parsed [TextRef], or nil if it is missing or invalid.
+func (o GuidedNavigationObject) TextURL() url.URL {
+	return parseGuidedNavigationRef(o.TextRef)
+}
+
+// AudioFile returns the audio resource referenced by the object, without its media fragment.
+func (o GuidedNavigationObject) AudioFile() url.URL {
+	return removeFragment(o.AudioURL())
+}
+
+// ImgFile returns the image referenced by the object, without its media fragment.
+func (o GuidedNavigationObject) ImgFile() url.URL {
+	return removeFragment(o.ImgURL())
+}
+
+// TextFile returns the textual resource referenced by the object, without its fragment.
+func (o GuidedNavigationObject) TextFile() url.URL {
+	return removeFragment(o.TextURL())
+}
+
+// TextFragmentID returns the ID of the element targeted in the textual resource, if any.
+func (o GuidedNavigationObject) TextFragmentID() string {
+	if u := o.TextURL(); u != nil {
+		return u.Fragment()
+	}
+	return ""
+}
+
+// AudioClip returns the time range of the audio resource referenced by the object, or nil if
+// it references the whole resource.
+func (o GuidedNavigationObject) AudioClip() (*TemporalFragment, error) {
+	u := o.AudioURL()
+	if u == nil {
+		return nil, nil
+	}
+	return ParseTemporalFragment(u.Fragment())
+}
+
+// ImgRegion returns the region of the image referenced by the object, or nil if it references
+// the whole image.
+func (o GuidedNavigationObject) ImgRegion() (*SpatialFragment, error) {
+	u := o.ImgURL()
+	if u == nil {
+		return nil, nil
+	}
+	return ParseSpatialFragment(u.Fragment())
+}
+
+// HasRole returns whether the object has the given structural [role].
+func (o GuidedNavigationObject) HasRole(role GuidedNavigationRole) bool {
+	return slices.Contains(o.Role, role)
+}
+
+func parseGuidedNavigationRef(ref string) url.URL {
+	if ref == "" {
+		return nil
+	}
+	u, err := url.URLFromString(ref)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+func removeFragment(u url.URL) url.URL {
+	if u == nil {
+		return nil
+	}
+	return u.RemoveFragment()
+}
+
+// Structural semantics of a [GuidedNavigationObject].
+// The Guided Navigation roles reuse the EPUB 3 Structural Semantics Vocabulary, which is also
+// where the roles of EPUB media overlays come from.
+// https://www.w3.org/TR/epub-ssv-11/
+type GuidedNavigationRole string
+
+const (
+	// Sections
+	GuidedNavigationRoleAside        GuidedNavigationRole = "aside"
+	GuidedNavigationRoleBackmatter   GuidedNavigationRole = "backmatter"
+	GuidedNavigationRoleBibliography GuidedNavigationRole = "bibliography"
+	GuidedNavigationRoleBodymatter   GuidedNavigationRole = "bodymatter"
+	GuidedNavigationRoleChapter      GuidedNavigationRole = "chapter"
+	GuidedNavigationRoleEndnote      GuidedNavigationRole = "endnote"
+	GuidedNavigationRoleFootnote     GuidedNavigationRole = "footnote"
+	GuidedNavigationRoleFrontmatter  GuidedNavigationRole = "frontmatter"
+	GuidedNavigationRoleGlossary     GuidedNavigationRole = "glossary"
+	GuidedNavigationRoleIndex        GuidedNavigationRole = "index"
+	GuidedNavigationRoleNote         GuidedNavigationRole = "note"
+	GuidedNavigationRolePart         GuidedNavigationRole = "part"
+	GuidedNavigationRoleSection      GuidedNavigationRole = "section"
+	GuidedNavigationRoleSidebar      GuidedNavigationRole = "sidebar"
+	GuidedNavigationRoleTitlePage    GuidedNavigationRole = "titlepage"
+	GuidedNavigationRoleTOC          GuidedNavigationRole = "toc"
+
+	// Content
+	GuidedNavigationRoleFigure    GuidedNavigationRole = "figure"
+	GuidedNavigationRoleList      GuidedNavigationRole = "list"
+	GuidedNavigationRoleListItem  GuidedNavigationRole = "list-item"
+	GuidedNavigationRolePageBreak GuidedNavigationRole = "pagebreak"
+	GuidedNavigationRoleTable     GuidedNavigationRole = "table"
+	GuidedNavigationRoleTableRow  GuidedNavigationRole = "table-row"
+	GuidedNavigationRoleTableCell GuidedNavigationRole = "table-cell"
+	GuidedNavigationRoleTitle     GuidedNavigationRole = "title"
+)
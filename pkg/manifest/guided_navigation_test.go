@@ -0,0 +1,52 @@
+package manifest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuidedNavigationObjectRefs(t *testing.T) {
+	o := GuidedNavigationObject{
+		AudioRef: "audio/chapter%201.mp3#t=2,4.5",
+		ImgRef:   "images/page.jpg#xywh=percent:0,50,100,50",
+		TextRef:  "text/chapter.xhtml#p1",
+	}
+
+	assert.Equal(t, "audio/chapter 1.mp3", o.AudioFile().Path())
+	assert.Equal(t, "t=2,4.5", o.AudioURL().Fragment())
+	clip, err := o.AudioClip()
+	assert.NoError(t, err)
+	assert.Equal(t, &TemporalFragment{Start: 2, End: extensions.Pointer(4.5)}, clip)
+
+	assert.Equal(t, "images/page.jpg", o.ImgFile().String())
+	region, err := o.ImgRegion()
+	assert.NoError(t, err)
+	assert.Equal(t, &SpatialFragment{Unit: SpatialUnitPercent, Y: 50, Width: 100, Height: 50}, region)
+
+	assert.Equal(t, "text/chapter.xhtml", o.TextFile().String())
+	assert.Equal(t, "p1", o.TextFragmentID())
+}
+
+func TestGuidedNavigationObjectMissingRefs(t *testing.T) {
+	o := GuidedNavigationObject{TextRef: "text/chapter.xhtml"}
+	assert.Nil(t, o.AudioURL())
+	assert.Nil(t, o.AudioFile())
+	clip, err := o.AudioClip()
+	assert.NoError(t, err)
+	assert.Nil(t, clip)
+	region, err := o.ImgRegion()
+	assert.NoError(t, err)
+	assert.Nil(t, region)
+	assert.Empty(t, o.TextFragmentID())
+}
+
+func TestGuidedNavigationObjectRoles(t *testing.T) {
+	var o GuidedNavigationObject
+	assert.NoError(t, json.Unmarshal([]byte(`{"textref": "c.xhtml#n1", "role": ["aside", "footnote"]}`), &o))
+	assert.Equal(t, []GuidedNavigationRole{GuidedNavigationRoleAside, GuidedNavigationRoleFootnote}, o.Role)
+	assert.True(t, o.HasRole(GuidedNavigationRoleFootnote))
+	assert.False(t, o.HasRole(GuidedNavigationRoleChapter))
+}
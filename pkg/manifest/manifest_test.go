@@ -493,3 +493,43 @@ func TestManifestLocatorLinkNotFound(t *testing.T) {
 		Href: MustNewHREFFromString("notfound", false),
 	}))
 }
+
+func TestManifestConformsToProfileOfReadingOrder(t *testing.T) {
+	audiobook := Manifest{
+		Links: LinkList{{
+			Href:      MustNewHREFFromString("cover.jpg", false),
+			MediaType: &mediatype.JPEG,
+		}},
+		ReadingOrder: LinkList{{
+			Href:      MustNewHREFFromString("track.mp3", false),
+			MediaType: &mediatype.MP3,
+		}},
+	}
+	assert.True(t, audiobook.ConformsTo(ProfileAudiobook))
+	assert.False(t, audiobook.ConformsTo(ProfileDivina))
+	assert.False(t, audiobook.ConformsTo(ProfilePDF))
+
+	divina := Manifest{
+		ReadingOrder: LinkList{{
+			Href:      MustNewHREFFromString("page.png", false),
+			MediaType: &mediatype.PNG,
+		}},
+	}
+	assert.True(t, divina.ConformsTo(ProfileDivina))
+	assert.False(t, divina.ConformsTo(ProfileAudiobook))
+
+	assert.False(t, Manifest{}.ConformsTo(ProfileAudiobook))
+
+	// Links without a media type don't conform to any profile
+	untyped := Manifest{
+		ReadingOrder: LinkList{
+			{Href: MustNewHREFFromString("track.mp3", false), MediaType: &mediatype.MP3},
+			{Href: MustNewHREFFromString("chapter1", false)},
+		},
+	}
+	for _, profile := range []Profile{ProfileAudiobook, ProfileDivina, ProfilePDF} {
+		assert.False(t, untyped.ConformsTo(profile), profile)
+	}
+	assert.False(t, untyped.ReadingOrder.AllAreVisual())
+	assert.False(t, untyped.ReadingOrder.AllAreVideo())
+}
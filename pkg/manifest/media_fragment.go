@@ -0,0 +1,154 @@
+package manifest
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Temporal dimension of a media fragment, e.g. #t=1.2,3.4
+// https://www.w3.org/TR/media-frags/#naming-time
+type TemporalFragment struct {
+	Start float64  // Start of the clip, in seconds.
+	End   *float64 // End of the clip, in seconds, or nil when it lasts until the end of the resource.
+}
+
+// Contains returns whether the given [time], in seconds, is part of the clip.
+func (f TemporalFragment) Contains(time float64) bool {
+	return time >= f.Start && (f.End == nil || time < *f.End)
+}
+
+// Duration returns the duration of the clip, or nil when it lasts until the end of the resource.
+func (f TemporalFragment) Duration() *float64 {
+	if f.End == nil {
+		return nil
+	}
+	d := *f.End - f.Start
+	return &d
+}
+
+// Returns the media fragment of the clip, without the leading #, e.g. t=1.2,3.4
+func (f TemporalFragment) String() string {
+	s := "t=" + strconv.FormatFloat(f.Start, 'f', -1, 64)
+	if f.End != nil {
+		s += "," + strconv.FormatFloat(*f.End, 'f', -1, 64)
+	}
+	return s
+}
+
+// Parses the temporal dimension of the given media [fragment], without the leading #.
+// Returns nil when the fragment has no temporal dimension.
+func ParseTemporalFragment(fragment string) (*TemporalFragment, error) {
+	value, ok := mediaFragmentDimension(fragment, "t")
+	if !ok {
+		return nil, nil
+	}
+	value = strings.TrimPrefix(value, "npt:")
+	begin, end, hasEnd := strings.Cut(value, ",")
+
+	f := &TemporalFragment{}
+	if begin != "" {
+		start, err := ParseClockValue(begin)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid start of temporal fragment %q", fragment)
+		}
+		f.Start = start
+	}
+	if hasEnd && end != "" {
+		e, err := ParseClockValue(end)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid end of temporal fragment %q", fragment)
+		}
+		if e < f.Start {
+			return nil, errors.Errorf("temporal fragment %q ends before it starts", fragment)
+		}
+		f.End = &e
+	}
+	return f, nil
+}
+
+// ParseClockValue parses a time in seconds, or as a [[hh:]mm:]ss[.fraction] clock value such as
+// a Normal Play Time. Negative and non-finite values are rejected.
+func ParseClockValue(value string) (float64, error) {
+	var seconds float64
+	for _, part := range strings.Split(value, ":") {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, errors.Errorf("invalid time %q", value)
+		}
+		seconds = seconds*60 + v
+	}
+	if math.IsInf(seconds, 0) {
+		return 0, errors.Errorf("invalid time %q", value)
+	}
+	return seconds, nil
+}
+
+// Unit of the coordinates of a [SpatialFragment].
+type SpatialUnit string
+
+const (
+	SpatialUnitPixel   SpatialUnit = "pixel"
+	SpatialUnitPercent SpatialUnit = "percent"
+)
+
+// Spatial dimension of a media fragment, e.g. #xywh=percent:25,25,50,50
+// https://www.w3.org/TR/media-frags/#naming-space
+type SpatialFragment struct {
+	Unit   SpatialUnit
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// Parses the spatial dimension of the given media [fragment], without the leading #.
+// Returns nil when the fragment has no spatial dimension.
+func ParseSpatialFragment(fragment string) (*SpatialFragment, error) {
+	value, ok := mediaFragmentDimension(fragment, "xywh")
+	if !ok {
+		return nil, nil
+	}
+
+	f := &SpatialFragment{Unit: SpatialUnitPixel}
+	if unit, rest, ok := strings.Cut(value, ":"); ok {
+		switch SpatialUnit(unit) {
+		case SpatialUnitPixel, SpatialUnitPercent:
+			f.Unit = SpatialUnit(unit)
+		default:
+			return nil, errors.Errorf("invalid unit of spatial fragment %q", fragment)
+		}
+		value = rest
+	}
+
+	coords := strings.Split(value, ",")
+	if len(coords) != 4 {
+		return nil, errors.Errorf("spatial fragment %q must have 4 coordinates", fragment)
+	}
+	values := make([]float64, 4)
+	for i, c := range coords {
+		v, err := strconv.ParseFloat(c, 64)
+		if err != nil || v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errors.Errorf("invalid coordinate in spatial fragment %q", fragment)
+		}
+		values[i] = v
+	}
+	if values[2] == 0 || values[3] == 0 {
+		return nil, errors.Errorf("spatial fragment %q has an empty area", fragment)
+	}
+	f.X, f.Y, f.Width, f.Height = values[0], values[1], values[2], values[3]
+	return f, nil
+}
+
+// Returns the value of the [name] dimension of a media fragment made of name=value pairs
+// separated by &.
+func mediaFragmentDimension(fragment string, name string) (string, bool) {
+	for _, pair := range strings.Split(fragment, "&") {
+		if value, ok := strings.CutPrefix(pair, name+"="); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
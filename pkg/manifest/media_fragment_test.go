@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTemporalFragment(t *testing.T) {
+	for fragment, expected := range map[string]*TemporalFragment{
+		"":                   nil,
+		"id1":                nil,
+		"t=1.5":              {Start: 1.5},
+		"t=1.5,3":            {Start: 1.5, End: extensions.Pointer(3.0)},
+		"t=,3":               {End: extensions.Pointer(3.0)},
+		"t=npt:10,20":        {Start: 10, End: extensions.Pointer(20.0)},
+		"t=npt:1:02.5,1:0:0": {Start: 62.5, End: extensions.Pointer(3600.0)},
+		"xywh=1,2,3,4&t=5":   {Start: 5},
+	} {
+		f, err := ParseTemporalFragment(fragment)
+		assert.NoError(t, err, fragment)
+		assert.Equal(t, expected, f, fragment)
+	}
+
+	for _, fragment := range []string{"t=abc", "t=5,2", "t=-1", "t=NaN", "t=0,Inf"} {
+		_, err := ParseTemporalFragment(fragment)
+		assert.Error(t, err, fragment)
+	}
+}
+
+func TestParseClockValue(t *testing.T) {
+	for value, expected := range map[string]float64{
+		"0":          0,
+		"1.5":        1.5,
+		"02:03":      123,
+		"1:02:03.25": 3723.25,
+	} {
+		v, err := ParseClockValue(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, v, value)
+	}
+
+	for _, value := range []string{"", "1:xx", "-1", "NaN", "Inf", "+Inf", "1:inf", "1e400", "1e308:1e308"} {
+		_, err := ParseClockValue(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestTemporalFragmentContains(t *testing.T) {
+	f := TemporalFragment{Start: 1, End: extensions.Pointer(2.0)}
+	assert.False(t, f.Contains(0.5))
+	assert.True(t, f.Contains(1))
+	assert.False(t, f.Contains(2))
+	assert.Equal(t, 1.0, *f.Duration())
+
+	f = TemporalFragment{Start: 1}
+	assert.True(t, f.Contains(1000))
+	assert.Nil(t, f.Duration())
+}
+
+func TestTemporalFragmentString(t *testing.T) {
+	assert.Equal(t, "t=1.5,3", TemporalFragment{Start: 1.5, End: extensions.Pointer(3.0)}.String())
+	assert.Equal(t, "t=0", TemporalFragment{}.String())
+}
+
+func TestParseSpatialFragment(t *testing.T) {
+	for fragment, expected := range map[string]*SpatialFragment{
+		"":                             nil,
+		"t=1":                          nil,
+		"xywh=160,120,320,240":         {Unit: SpatialUnitPixel, X: 160, Y: 120, Width: 320, Height: 240},
+		"xywh=pixel:160,120,320,240":   {Unit: SpatialUnitPixel, X: 160, Y: 120, Width: 320, Height: 240},
+		"xywh=percent:25.5,25,50,50":   {Unit: SpatialUnitPercent, X: 25.5, Y: 25, Width: 50, Height: 50},
+		"t=1&xywh=percent:0,0,100,100": {Unit: SpatialUnitPercent, Width: 100, Height: 100},
+	} {
+		f, err := ParseSpatialFragment(fragment)
+		assert.NoError(t, err, fragment)
+		assert.Equal(t, expected, f, fragment)
+	}
+
+	for _, fragment := range []string{"xywh=1,2,3", "xywh=em:1,2,3,4", "xywh=1,2,0,4", "xywh=a,2,3,4", "xywh=NaN,0,Inf,Inf", "xywh=percent:0,0,+Inf,10"} {
+		_, err := ParseSpatialFragment(fragment)
+		assert.Error(t, err, fragment)
+	}
+}
@@ -0,0 +1,137 @@
+package manifest
+
+// Key of the [Manifest] subcollection holding the synthetic spreads of a publication.
+const SpreadsSubcollectionKey = "spreads"
+
+// Resources of the reading order laid out together in a synthetic spread.
+//
+// A spread holds either a single resource displayed in the [Center], or a [Left] and/or [Right]
+// page. A page of a spread can be missing when its facing page is displayed alone, e.g. a cover.
+type SyntheticSpread struct {
+	Left   *Link
+	Right  *Link
+	Center *Link
+}
+
+// Links returns the resources of the spread in the reading order, with their [Properties.Page]
+// set to the side they are displayed on.
+func (s SyntheticSpread) Links(progression ReadingProgression) LinkList {
+	pages := []struct {
+		link *Link
+		page Page
+	}{{s.Left, PageLeft}, {s.Right, PageRight}}
+	if progression == RTL {
+		pages[0], pages[1] = pages[1], pages[0]
+	}
+
+	links := make(LinkList, 0, 2)
+	if s.Center != nil {
+		links = append(links, linkWithPage(*s.Center, PageCenter))
+	}
+	for _, p := range pages {
+		if p.link != nil {
+			links = append(links, linkWithPage(*p.link, p.page))
+		}
+	}
+	return links
+}
+
+func linkWithPage(link Link, page Page) Link {
+	properties := make(Properties, len(link.Properties)+1)
+	for k, v := range link.Properties {
+		properties[k] = v
+	}
+	properties["page"] = string(page)
+	link.Properties = properties
+	return link
+}
+
+// Spreads computes the synthetic spreads of the reading order, as displayed in a landscape
+// reading environment.
+//
+// Fixed-layout resources are paired according to their page-spread hints, inferred when missing
+// by alternating the pages following the reading progression, the first page being displayed on
+// the trailing side like a cover. Reflowable resources, center pages and resources which must
+// not be displayed in a spread are laid out alone in the center, and the next page starts a new
+// spread on the leading side.
+//
+// Returns nil when the publication has no fixed-layout resources.
+func (m Manifest) Spreads() []SyntheticSpread {
+	presentation := Presentation{}
+	if m.Metadata.Presentation != nil {
+		presentation = *m.Metadata.Presentation
+	}
+	spreadOf := func(link Link) Spread {
+		if s := link.Properties.Spread(); s != "" {
+			return s
+		}
+		if presentation.Spread != nil {
+			return *presentation.Spread
+		}
+		return SpreadAuto
+	}
+
+	leading, trailing := PageLeft, PageRight
+	if m.Metadata.EffectiveReadingProgression() == RTL {
+		leading, trailing = trailing, leading
+	}
+	onSide := func(link *Link, page Page) SyntheticSpread {
+		if page == PageLeft {
+			return SyntheticSpread{Left: link}
+		}
+		return SyntheticSpread{Right: link}
+	}
+
+	var spreads []SyntheticSpread
+	var pending *Link // Leading page waiting for its facing page.
+	hasFixed := false
+	next := trailing
+	for i := range m.ReadingOrder {
+		link := &m.ReadingOrder[i]
+		fixed := presentation.LayoutOf(*link) == EPUBLayoutFixed
+		hasFixed = hasFixed || fixed
+
+		page := link.Properties.Page()
+		if !fixed || page == PageCenter || spreadOf(*link) == SpreadNone {
+			if pending != nil {
+				spreads = append(spreads, onSide(pending, leading))
+				pending = nil
+			}
+			spreads = append(spreads, SyntheticSpread{Center: link})
+			next = leading
+			continue
+		}
+
+		if page != PageLeft && page != PageRight {
+			page = next
+		}
+		if page == leading {
+			if pending != nil {
+				spreads = append(spreads, onSide(pending, leading))
+			}
+			pending = link
+			next = trailing
+			continue
+		}
+
+		spread := onSide(link, trailing)
+		if pending != nil {
+			if leading == PageLeft {
+				spread.Left = pending
+			} else {
+				spread.Right = pending
+			}
+			pending = nil
+		}
+		spreads = append(spreads, spread)
+		next = leading
+	}
+	if pending != nil {
+		spreads = append(spreads, onSide(pending, leading))
+	}
+
+	if !hasFixed {
+		return nil
+	}
+	return spreads
+}
@@ -0,0 +1,121 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fixedLayoutManifest(progression ReadingProgression, spread Spread, pages ...Page) Manifest {
+	layout := EPUBLayoutFixed
+	m := Manifest{
+		Metadata: Metadata{
+			ReadingProgression: progression,
+			Presentation:       &Presentation{Layout: &layout, Spread: &spread},
+		},
+	}
+	for i, page := range pages {
+		link := Link{Href: MustNewHREFFromString(string(rune('a'+i))+".xhtml", false)}
+		if page != "" {
+			link.Properties = Properties{"page": string(page)}
+		}
+		m.ReadingOrder = append(m.ReadingOrder, link)
+	}
+	return m
+}
+
+// Describes the spreads with the hrefs of their left, right and center pages.
+func spreadsHrefs(spreads []SyntheticSpread) [][3]string {
+	href := func(l *Link) string {
+		if l == nil {
+			return ""
+		}
+		return l.Href.String()
+	}
+	res := make([][3]string, len(spreads))
+	for i, s := range spreads {
+		res[i] = [3]string{href(s.Left), href(s.Right), href(s.Center)}
+	}
+	return res
+}
+
+func TestSpreadsInferPagesLTR(t *testing.T) {
+	m := fixedLayoutManifest(LTR, SpreadAuto, "", "", "", "", "")
+	assert.Equal(t, [][3]string{
+		{"", "a.xhtml", ""},
+		{"b.xhtml", "c.xhtml", ""},
+		{"d.xhtml", "e.xhtml", ""},
+	}, spreadsHrefs(m.Spreads()))
+}
+
+func TestSpreadsInferPagesRTL(t *testing.T) {
+	m := fixedLayoutManifest(RTL, SpreadAuto, "", "", "", "")
+	assert.Equal(t, [][3]string{
+		{"a.xhtml", "", ""},
+		{"c.xhtml", "b.xhtml", ""},
+		{"", "d.xhtml", ""},
+	}, spreadsHrefs(m.Spreads()))
+}
+
+func TestSpreadsFollowPageHints(t *testing.T) {
+	m := fixedLayoutManifest(LTR, SpreadBoth, PageRight, PageRight, PageLeft, PageCenter, PageLeft, PageRight, PageLeft)
+	assert.Equal(t, [][3]string{
+		{"", "a.xhtml", ""},
+		{"", "b.xhtml", ""},
+		{"c.xhtml", "", ""},
+		{"", "", "d.xhtml"},
+		{"e.xhtml", "f.xhtml", ""},
+		{"g.xhtml", "", ""},
+	}, spreadsHrefs(m.Spreads()))
+}
+
+func TestSpreadsNone(t *testing.T) {
+	m := fixedLayoutManifest(LTR, SpreadNone, PageLeft, PageRight)
+	assert.Equal(t, [][3]string{
+		{"", "", "a.xhtml"},
+		{"", "", "b.xhtml"},
+	}, spreadsHrefs(m.Spreads()))
+
+	// Overridden by a resource
+	m = fixedLayoutManifest(LTR, SpreadAuto, "", "", "", "")
+	m.ReadingOrder[1].Properties = Properties{"spread": "none"}
+	assert.Equal(t, [][3]string{
+		{"", "a.xhtml", ""},
+		{"", "", "b.xhtml"},
+		{"c.xhtml", "d.xhtml", ""},
+	}, spreadsHrefs(m.Spreads()))
+}
+
+func TestSpreadsReflowable(t *testing.T) {
+	m := fixedLayoutManifest(LTR, SpreadAuto, "", "", "")
+	m.Metadata.Presentation.Layout = nil
+	assert.Nil(t, m.Spreads())
+
+	m.ReadingOrder[1].Properties = Properties{"layout": "fixed"}
+	assert.Equal(t, [][3]string{
+		{"", "", "a.xhtml"},
+		{"b.xhtml", "", ""},
+		{"", "", "c.xhtml"},
+	}, spreadsHrefs(m.Spreads()))
+}
+
+func TestSyntheticSpreadLinks(t *testing.T) {
+	left := Link{Href: MustNewHREFFromString("left.xhtml", false), Properties: Properties{"layout": "fixed"}}
+	right := Link{Href: MustNewHREFFromString("right.xhtml", false)}
+	spread := SyntheticSpread{Left: &left, Right: &right}
+
+	links := spread.Links(LTR)
+	if assert.Len(t, links, 2) {
+		assert.Equal(t, "left.xhtml", links[0].Href.String())
+		assert.Equal(t, PageLeft, links[0].Properties.Page())
+		assert.Equal(t, EPUBLayoutFixed, links[0].Properties.Layout())
+		assert.Equal(t, PageRight, links[1].Properties.Page())
+	}
+	assert.Equal(t, "right.xhtml", spread.Links(RTL)[0].Href.String())
+	assert.Nil(t, left.Properties.Get("page"), "the original link must not be modified")
+
+	center := SyntheticSpread{Center: &right}.Links(LTR)
+	if assert.Len(t, center, 1) {
+		assert.Equal(t, PageCenter, center[0].Properties.Page())
+	}
+}
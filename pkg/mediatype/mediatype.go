@@ -185,6 +185,12 @@ func (mt MediaType) buildQueryParams() string {
 }
 */
 
+// The default file extension for this media type, without the leading dot.
+// Empty when the media type was not created with a known extension.
+func (mt MediaType) FileExtension() string {
+	return mt.fileExtension
+}
+
 // The string representation of this media type.
 func (mt MediaType) String() string {
 	return mime.FormatMediaType(mt.Type+"/"+mt.SubType, mt.Parameters)
@@ -320,7 +326,7 @@ func (mt MediaType) IsRwpm() bool {
 // Returns whether this media type is of a publication file.
 func (mt MediaType) IsPublication() bool {
 	return mt.Matches(
-		&ReadiumAudiobook, &ReadiumAudiobookManifest, &CBZ, &ReadiumDivina, &ReadiumDivinaManifest, &EPUB, &LCPProtectedAudiobook,
-		&LCPProtectedPDF, &LPF, &PDF, &W3CWPUBManifest, &ReadiumWebpub, &ReadiumWebpubManifest, &ZAB,
+		&ReadiumAudiobook, &ReadiumAudiobookManifest, &AZW3, &CBZ, &ReadiumDivina, &ReadiumDivinaManifest, &EPUB, &FB2, &FB2ZIP, &LCPProtectedAudiobook,
+		&LCPProtectedPDF, &LPF, &MOBI, &PDF, &W3CWPUBManifest, &ReadiumWebpub, &ReadiumWebpubManifest, &ZAB,
 	)
 }
@@ -11,10 +11,13 @@ import (
 var Sniffers = []Sniffer{
 	SniffEPUB,
 	SniffLPF,
+	SniffFB2,
 	SniffArchive,
 	SniffPDF,
+	SniffMOBI,
 	SniffXHTML,
 	SniffHTML,
+	SniffText,
 	SniffBitmap,
 	SniffAudio,
 	SniffOPDS,
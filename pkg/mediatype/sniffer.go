@@ -1,6 +1,8 @@
 package mediatype
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"mime"
 	"path/filepath"
@@ -54,6 +56,19 @@ func SniffHTML(context SnifferContext) *MediaType {
 	return nil
 }
 
+// Sniffs a plain text or Markdown document.
+// There's no heavy sniffing, as any content could be plain text.
+func SniffText(context SnifferContext) *MediaType {
+	if context.HasFileExtension("md", "markdown") || context.HasMediaType("text/markdown", "text/x-markdown") {
+		return &Markdown
+	}
+	if context.HasFileExtension("txt", "text") || context.HasMediaType("text/plain") {
+		return &Text
+	}
+
+	return nil
+}
+
 // Sniffs an OPDS document.
 func SniffOPDS(context SnifferContext) *MediaType {
 	// OPDS 1 (Light)
@@ -278,6 +293,43 @@ func SniffLPF(context SnifferContext) *MediaType {
 	return nil
 }
 
+// Sniffs a FictionBook document, either as a bare XML file or zipped.
+// Reference: http://www.fictionbook.org/index.php/Eng:XML_Schema_Fictionbook_2.1
+func SniffFB2(context SnifferContext) *MediaType {
+	if context.HasFileExtension("fb2") || context.HasMediaType("application/x-fictionbook+xml", "application/x-fictionbook", "text/fb2+xml") {
+		return &FB2
+	}
+	if context.HasFileExtension("fbz") || context.HasMediaType("application/x-zip-compressed-fb2") {
+		return &FB2ZIP
+	}
+
+	// A zipped FictionBook is an archive containing a single FB2 file.
+	if archive, err := context.ContentAsArchive(); err == nil && archive != nil {
+		fb2s := 0
+		for _, zf := range archive.Entries() {
+			if extensions.IsHiddenOrThumbs(zf.Path()) {
+				continue
+			}
+			if !strings.EqualFold(filepath.Ext(zf.Path()), ".fb2") {
+				return nil
+			}
+			fb2s++
+		}
+		if fb2s == 1 {
+			return &FB2ZIP
+		}
+		return nil
+	}
+
+	// FB2 files are often encoded in a legacy charset, so the root element is looked up in the raw
+	// bytes instead of decoding the XML.
+	if bytes.Contains(context.Read(0, 1024), []byte("<FictionBook")) {
+		return &FB2
+	}
+
+	return nil
+}
+
 // Authorized extensions for resources in a CBZ archive.
 // Reference: https://wiki.mobileread.com/wiki/CBR_and_CBZ
 var cbz_extensions = map[string]struct{}{
@@ -348,6 +400,29 @@ func SniffPDF(context SnifferContext) *MediaType {
 	return nil
 }
 
+// Sniffs a Mobipocket or Kindle Format 8 book, stored in a Palm Database.
+// Reference: https://wiki.mobileread.com/wiki/MOBI
+func SniffMOBI(context SnifferContext) *MediaType {
+	if context.HasFileExtension("azw3") || context.HasMediaType("application/vnd.amazon.mobi8-ebook") {
+		return &AZW3
+	}
+	if context.HasFileExtension("mobi", "prc", "azw") || context.HasMediaType("application/x-mobipocket-ebook") {
+		return &MOBI
+	}
+
+	header := context.Read(0, 81)
+	if len(header) < 82 || string(header[60:68]) != "BOOKMOBI" {
+		return nil
+	}
+	// A KF8-only book has a MOBI header of version 8 in its first record, while a MOBI book might
+	// embed a KF8 version after its legacy records.
+	record0 := int64(binary.BigEndian.Uint32(header[78:82]))
+	if version := context.Read(record0+0x68, record0+0x6B); len(version) >= 4 && binary.BigEndian.Uint32(version) >= 8 {
+		return &AZW3
+	}
+	return &MOBI
+}
+
 func SniffKnown(context SnifferContext) *MediaType {
 	for k, v := range knownMatches {
 		if context.HasMediaType(k) {
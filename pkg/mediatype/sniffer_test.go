@@ -294,6 +294,49 @@ func TestSniffLPF(t *testing.T) {
 	assert.Equal(t, &LPF, OfFileOnly(testLPF2))
 }
 
+func TestSniffFB2(t *testing.T) {
+	assert.Equal(t, &FB2, OfExtension("fb2"))
+	assert.Equal(t, &FB2, OfString("application/x-fictionbook+xml"))
+	assert.Equal(t, &FB2ZIP, OfExtension("fbz"))
+	assert.Equal(t, &FB2ZIP, OfString("application/x-zip-compressed-fb2"))
+
+	testFB2, err := os.Open(filepath.Join("testdata", "fb2.unknown"))
+	assert.NoError(t, err)
+	defer testFB2.Close()
+	assert.Equal(t, &FB2, OfFileOnly(testFB2))
+
+	testFBZ, err := os.Open(filepath.Join("testdata", "fbz.unknown"))
+	assert.NoError(t, err)
+	defer testFBZ.Close()
+	assert.Equal(t, &FB2ZIP, OfFileOnly(testFBZ))
+}
+
+func TestSniffMOBI(t *testing.T) {
+	assert.Equal(t, &MOBI, OfExtension("mobi"))
+	assert.Equal(t, &MOBI, OfString("application/x-mobipocket-ebook"))
+	assert.Equal(t, &AZW3, OfExtension("azw3"))
+	assert.Equal(t, &AZW3, OfString("application/vnd.amazon.mobi8-ebook"))
+
+	testMOBI, err := os.Open(filepath.Join("testdata", "mobi.unknown"))
+	assert.NoError(t, err)
+	defer testMOBI.Close()
+	assert.Equal(t, &MOBI, OfFileOnly(testMOBI))
+
+	testAZW3, err := os.Open(filepath.Join("testdata", "azw3.unknown"))
+	assert.NoError(t, err)
+	defer testAZW3.Close()
+	assert.Equal(t, &AZW3, OfFileOnly(testAZW3))
+}
+
+func TestSniffText(t *testing.T) {
+	assert.Equal(t, &Text, OfExtension("txt"))
+	assert.Equal(t, &Text, OfString("text/plain"))
+	assert.Equal(t, &Markdown, OfExtension("md"))
+	assert.Equal(t, &Markdown, OfExtension("markdown"))
+	assert.Equal(t, &Markdown, OfString("text/markdown"))
+	assert.Equal(t, &Markdown, OfString("text/x-markdown"))
+}
+
 func TestSniffPDF(t *testing.T) {
 	assert.Equal(t, &PDF, OfExtension("pdf"))
 	assert.Equal(t, &PDF, OfString("application/pdf"))
@@ -5,12 +5,15 @@ var ACSM, _ = New("application/vnd.adobe.adept+xml", "Adobe Content Server Messa
 var AIFF, _ = New("audio/aiff", "", "aiff")
 var AVI, _ = New("video/x-msvideo", "", "avi")
 var AVIF, _ = New("image/avif", "", "avif")
+var AZW3, _ = New("application/vnd.amazon.mobi8-ebook", "Kindle Format 8", "azw3")
 var Binary, _ = New("application/octet-stream", "", "")
 var BMP, _ = New("image/bmp", "Bitmap Image File", "bmp")
 var CBZ, _ = New("application/vnd.comicbook+zip", "Comic Book ZIP Archive", "cbz")
 var CBR, _ = New("application/vnd.comicbook-rar", "Comic Book RAR Archive", "cbr")
 var CSS, _ = New("text/css", "Cascading Style Sheets", "css")
 var EPUB, _ = New("application/epub+zip", "EPUB", "epub")
+var FB2, _ = New("application/x-fictionbook+xml", "FictionBook", "fb2")
+var FB2ZIP, _ = New("application/x-zip-compressed-fb2", "Zipped FictionBook", "fbz")
 var GIF, _ = New("image/gif", "GIF Image", "gif")
 var GZ, _ = New("application/gzip", "GZipped content", "gz")
 var HTML, _ = New("text/html", "Hypertext Markup Language", "html")
@@ -23,6 +26,8 @@ var LCPProtectedAudiobook, _ = New("application/audiobook+lcp", "LCP Protected A
 var LCPProtectedPDF, _ = New("application/pdf+lcp", "LCP Protected PDF", "lcpdf")
 var LCPStatusDocument, _ = New("application/vnd.readium.license.status.v1.0+json", "LCP Status Document", "")
 var LPF, _ = New("application/lpf+zip", "Lightweight Packaging Format", "lpf")
+var Markdown, _ = New("text/markdown", "Markdown", "md")
+var MOBI, _ = New("application/x-mobipocket-ebook", "Mobipocket eBook", "mobi")
 var MP3, _ = New("audio/mpeg", "MP3 Audio", "mp3")
 var MPEG, _ = New("video/mpeg", "MPEG Video", "mpeg")
 var NCX, _ = New("application/x-dtbncx+xml", "Navigation Control File", "ncx")
@@ -48,6 +53,7 @@ var ReadiumPositionList, _ = New("application/vnd.readium.position-list+json", "
 var ReadiumWebpub, _ = New("application/webpub+zip", "Readium Web Publication", "webpub")
 var ReadiumWebpubManifest, _ = New("application/webpub+json", "Readium Web Publication", "json")
 var SMIL, _ = New("application/smil+xml", "Synchronized Multimedia Integration Language", "smil")
+var SubRip, _ = New("application/x-subrip", "SubRip Subtitle", "srt")
 var SVG, _ = New("image/svg+xml", "Scalable Vector Graphics", "svg")
 var Text, _ = New("text/plain", "Text", "txt")
 var TIFF, _ = New("image/tiff", "TIFF Image", "tiff")
@@ -56,6 +62,7 @@ var W3CWPUBManifest, _ = New("application/x.readium.w3c.wpub+json", "Web Publica
 var WAV, _ = New("audio/wav", "WAV Audio", "wav")
 var WEBMAudio, _ = New("audio/webm", "WEBM Audio", "webm")
 var WEBMVideo, _ = New("video/webm", "WEBM Video", "webm")
+var WebVTT, _ = New("text/vtt", "Web Video Text Tracks", "vtt")
 var WEBP, _ = New("image/webp", "WEBP Image", "webp")
 var WOFF, _ = New("font/woff", "WOFF Font", "woff")
 var WOFF2, _ = New("font/woff2", "WOFF2 Font", "woff2")
@@ -4,29 +4,34 @@ package mediatype
 // var (\w+)[^\n]+New\(("[^"]+"),[^\n]+
 // $2: &$1,
 var knownMatches = map[string]*MediaType{
-	"audio/aac":                       &AAC,
-	"application/vnd.adobe.adept+xml": &ACSM,
-	"audio/aiff":                      &AIFF,
-	"video/x-msvideo":                 &AVI,
-	"image/avif":                      &AVIF,
-	"application/octet-stream":        &Binary,
-	"image/bmp":                       &BMP,
-	"application/vnd.comicbook+zip":   &CBZ,
-	"application/vnd.comicbook-rar":   &CBR,
-	"text/css":                        &CSS,
-	"application/epub+zip":            &EPUB,
-	"image/gif":                       &GIF,
-	"application/gzip":                &GZ,
-	"text/html":                       &HTML,
-	"text/javascript":                 &JavaScript,
-	"image/jpeg":                      &JPEG,
-	"application/json":                &JSON,
-	"image/jxl":                       &JXL,
+	"audio/aac":                          &AAC,
+	"application/vnd.adobe.adept+xml":    &ACSM,
+	"audio/aiff":                         &AIFF,
+	"video/x-msvideo":                    &AVI,
+	"image/avif":                         &AVIF,
+	"application/vnd.amazon.mobi8-ebook": &AZW3,
+	"application/octet-stream":           &Binary,
+	"image/bmp":                          &BMP,
+	"application/vnd.comicbook+zip":      &CBZ,
+	"application/vnd.comicbook-rar":      &CBR,
+	"text/css":                           &CSS,
+	"application/epub+zip":               &EPUB,
+	"application/x-fictionbook+xml":      &FB2,
+	"application/x-zip-compressed-fb2":   &FB2ZIP,
+	"image/gif":                          &GIF,
+	"application/gzip":                   &GZ,
+	"text/html":                          &HTML,
+	"text/javascript":                    &JavaScript,
+	"image/jpeg":                         &JPEG,
+	"application/json":                   &JSON,
+	"image/jxl":                          &JXL,
 	"application/vnd.readium.lcp.license.v1.0+json":        &LCPLicenseDocument,
 	"application/audiobook+lcp":                            &LCPProtectedAudiobook,
 	"application/pdf+lcp":                                  &LCPProtectedPDF,
 	"application/vnd.readium.license.status.v1.0+json":     &LCPStatusDocument,
 	"application/lpf+zip":                                  &LPF,
+	"text/markdown":                                        &Markdown,
+	"application/x-mobipocket-ebook":                       &MOBI,
 	"audio/mpeg":                                           &MP3,
 	"video/mpeg":                                           &MPEG,
 	"application/x-dtbncx+xml":                             &NCX,
@@ -52,6 +57,7 @@ var knownMatches = map[string]*MediaType{
 	"application/webpub+zip":                               &ReadiumWebpub,
 	"application/webpub+json":                              &ReadiumWebpubManifest,
 	"application/smil+xml":                                 &SMIL,
+	"application/x-subrip":                                 &SubRip,
 	"image/svg+xml":                                        &SVG,
 	"text/plain":                                           &Text,
 	"image/tiff":                                           &TIFF,
@@ -60,6 +66,7 @@ var knownMatches = map[string]*MediaType{
 	"audio/wav":                                            &WAV,
 	"audio/webm":                                           &WEBMAudio,
 	"video/webm":                                           &WEBMVideo,
+	"text/vtt":                                             &WebVTT,
 	"image/webp":                                           &WEBP,
 	"font/woff":                                            &WOFF,
 	"font/woff2":                                           &WOFF2,
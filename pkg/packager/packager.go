@@ -0,0 +1,165 @@
+package packager
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/readium/go-toolkit/pkg/util/url"
+)
+
+// Path of the manifest in a Readium Web Publication package.
+const ManifestPath = "manifest.json"
+
+// Prefix of the HREFs served by publication services, which are not packaged.
+const serviceHrefPrefix = "~readium/"
+
+// Returns the package media type (.audiobook, .divina or .webpub) best suited to the given manifest.
+func MediaTypeOf(m manifest.Manifest) mediatype.MediaType {
+	for _, profile := range m.Metadata.ConformsTo {
+		switch profile {
+		case manifest.ProfileAudiobook:
+			return mediatype.ReadiumAudiobook
+		case manifest.ProfileDivina:
+			return mediatype.ReadiumDivina
+		}
+	}
+	if len(m.ReadingOrder) > 0 {
+		if m.ReadingOrder.AllAreAudio() {
+			return mediatype.ReadiumAudiobook
+		}
+		if m.ReadingOrder.AllAreBitmap() {
+			return mediatype.ReadiumDivina
+		}
+	}
+	return mediatype.ReadiumWebpub
+}
+
+// Packages the publication as a Readium Web Publication package of the type
+// returned by [MediaTypeOf], writing it to [w].
+func Pack(publication *pub.Publication, w io.Writer) error {
+	return PackAs(publication, MediaTypeOf(publication.Manifest), w)
+}
+
+// Packages the publication as a Readium Web Publication package with the
+// given media type, writing it to [w]. The package contains the manifest and
+// every local resource it references, read from the publication's fetcher.
+// Links to publication services are left out, as they are generated by the
+// reading system. The output is deterministic for a given publication.
+func PackAs(publication *pub.Publication, mediaType mediatype.MediaType, w io.Writer) error {
+	m := publication.Manifest
+	m.Links = withoutServiceLinks(m.Links)
+
+	manifestJSON, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed marshalling manifest")
+	}
+
+	aw, err := archive.NewWriter(w, mediaType.String())
+	if err != nil {
+		return err
+	}
+	if err := aw.WriteEntry(ManifestPath, manifestJSON, true); err != nil {
+		return errors.Wrap(err, "failed writing manifest")
+	}
+
+	for _, link := range packagedLinks(m) {
+		href := link.URL(nil, nil).Path()
+		if href == ManifestPath || href == "mimetype" {
+			continue
+		}
+
+		ew, err := aw.Create(href, shouldCompress(link.MediaType))
+		if err != nil {
+			return errors.Wrap(err, "failed creating entry "+href)
+		}
+		res := publication.Fetcher.Get(link)
+		_, rerr := res.Stream(ew, 0, 0)
+		res.Close()
+		if rerr != nil {
+			return errors.Wrap(rerr, "failed packaging resource "+href)
+		}
+	}
+
+	return aw.Close()
+}
+
+// Links of the local resources to package, in manifest order and without duplicates.
+func packagedLinks(m manifest.Manifest) manifest.LinkList {
+	var links manifest.LinkList
+	seen := make(map[string]struct{})
+	lists := []manifest.LinkList{m.ReadingOrder, m.Resources, m.Links, m.TableOfContents}
+	lists = append(lists, subcollectionLinks(m.Subcollections)...)
+	for _, ll := range lists {
+		for _, link := range ll.Flatten() {
+			if link.Href.IsTemplated() {
+				continue
+			}
+			u := link.URL(nil, nil)
+			if _, ok := u.(url.AbsoluteURL); ok {
+				continue // Remote resource
+			}
+			u = u.RemoveFragment().RemoveQuery()
+			href := u.Path()
+			if href == "" || strings.HasPrefix(href, serviceHrefPrefix) {
+				continue
+			}
+			if _, ok := seen[href]; ok {
+				continue
+			}
+			seen[href] = struct{}{}
+			link.Href = manifest.NewHREF(u)
+			link.Alternates = nil
+			link.Children = nil
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// Links of the given [collections] and of their own subcollections, sorted by role to keep the
+// package deterministic.
+func subcollectionLinks(collections manifest.PublicationCollectionMap) []manifest.LinkList {
+	roles := make([]string, 0, len(collections))
+	for role := range collections {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	var lists []manifest.LinkList
+	for _, role := range roles {
+		for _, collection := range collections[role] {
+			lists = append(lists, collection.Links)
+			lists = append(lists, subcollectionLinks(collection.Subcollections)...)
+		}
+	}
+	return lists
+}
+
+func withoutServiceLinks(links manifest.LinkList) manifest.LinkList {
+	filtered := make(manifest.LinkList, 0, len(links))
+	for _, link := range links {
+		if strings.HasPrefix(link.Href.String(), serviceHrefPrefix) {
+			continue
+		}
+		filtered = append(filtered, link)
+	}
+	return filtered
+}
+
+// Media which is already compressed is stored as is.
+func shouldCompress(mt *mediatype.MediaType) bool {
+	if mt == nil {
+		return true
+	}
+	if mt.IsBitmap() || mt.IsAudio() || mt.IsVideo() || mt.IsZIP() {
+		return false
+	}
+	return !mt.Matches(&mediatype.PDF, &mediatype.WOFF, &mediatype.WOFF2)
+}
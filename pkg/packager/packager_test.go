@@ -0,0 +1,161 @@
+package packager
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPublication() *pub.Publication {
+	return pub.New(manifest.Manifest{
+		Metadata: manifest.Metadata{
+			LocalizedTitle: manifest.NewLocalizedStringFromString("Comic"),
+		},
+		Links: manifest.LinkList{
+			{Href: manifest.MustNewHREFFromString("https://example.com/comic.json", false), Rels: manifest.Strings{"self"}},
+		},
+		ReadingOrder: manifest.LinkList{
+			{Href: manifest.MustNewHREFFromString("images/page1.jpg", false), MediaType: &mediatype.JPEG},
+			{Href: manifest.MustNewHREFFromString("images/page2.png", false), MediaType: &mediatype.PNG},
+		},
+		Resources: manifest.LinkList{
+			{Href: manifest.MustNewHREFFromString("credits.txt", false), MediaType: &mediatype.Text},
+		},
+		TableOfContents: manifest.LinkList{
+			{Href: manifest.MustNewHREFFromString("images/page2.png#t=1", false), Title: "Chapter 2"},
+		},
+	}, fetcher.NewFileFetcher("", "./testdata/divina"), pub.NewServicesBuilder(map[string]pub.ServiceFactory{
+		pub.PositionsService_Name: pub.PerResourcePositionsServiceFactory(mediatype.MustNewOfString("image/*")),
+	}))
+}
+
+func TestMediaTypeOf(t *testing.T) {
+	assert.Equal(t, mediatype.ReadiumDivina, MediaTypeOf(testPublication().Manifest))
+	assert.Equal(t, mediatype.ReadiumAudiobook, MediaTypeOf(manifest.Manifest{
+		ReadingOrder: manifest.LinkList{{Href: manifest.MustNewHREFFromString("a.mp3", false), MediaType: &mediatype.MP3}},
+	}))
+	assert.Equal(t, mediatype.ReadiumWebpub, MediaTypeOf(manifest.Manifest{
+		ReadingOrder: manifest.LinkList{{Href: manifest.MustNewHREFFromString("a.html", false), MediaType: &mediatype.HTML}},
+	}))
+}
+
+func TestPackWritesOCFStructure(t *testing.T) {
+	var buf bytes.Buffer
+	if !assert.NoError(t, Pack(testPublication(), &buf)) {
+		return
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if !assert.NoError(t, err) {
+		return
+	}
+	names := make([]string, len(r.File))
+	for i, f := range r.File {
+		names[i] = f.Name
+		assert.Equal(t, archive.DeterministicModTime, f.Modified.UTC())
+	}
+	assert.Equal(t, []string{"mimetype", "manifest.json", "images/page1.jpg", "images/page2.png", "credits.txt"}, names)
+
+	// The mimetype is the first entry, stored without extra field
+	assert.Equal(t, zip.Store, r.File[0].Method)
+	assert.Empty(t, r.File[0].Extra)
+	assert.Equal(t, "mimetypeapplication/divina+zip", string(buf.Bytes()[30:60]))
+
+	// Images are stored, text is compressed
+	assert.Equal(t, zip.Store, r.File[2].Method)
+	assert.Equal(t, zip.Deflate, r.File[4].Method)
+
+	f, err := r.Open("images/page2.png")
+	if assert.NoError(t, err) {
+		b, _ := io.ReadAll(f)
+		assert.Equal(t, "\x89PNG\r\n\x1a\nfakepng", string(b))
+	}
+}
+
+func TestPackResourcesOutsideReadingOrder(t *testing.T) {
+	publication := pub.New(manifest.Manifest{
+		ReadingOrder: manifest.LinkList{
+			{Href: manifest.MustNewHREFFromString("images/page1.jpg", false), MediaType: &mediatype.JPEG},
+		},
+		TableOfContents: manifest.LinkList{
+			{Href: manifest.MustNewHREFFromString("images/page1.jpg", false), Title: "Part 1", Children: manifest.LinkList{
+				{Href: manifest.MustNewHREFFromString("images/page2.png#xywh=0,0,10,10", false), Title: "Chapter 1"},
+			}},
+		},
+		Subcollections: manifest.PublicationCollectionMap{
+			"landmarks": {{Subcollections: manifest.PublicationCollectionMap{
+				"credits": {{Links: manifest.LinkList{
+					{Href: manifest.MustNewHREFFromString("images/page1.jpg", false), Alternates: manifest.LinkList{
+						{Href: manifest.MustNewHREFFromString("credits.txt", false), MediaType: &mediatype.Text},
+					}},
+				}}},
+			}}},
+		},
+	}, fetcher.NewFileFetcher("", "./testdata/divina"), nil)
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, Pack(publication, &buf)) {
+		return
+	}
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if !assert.NoError(t, err) {
+		return
+	}
+	names := make([]string, len(r.File))
+	for i, f := range r.File {
+		names[i] = f.Name
+	}
+	assert.Equal(t, []string{"mimetype", "manifest.json", "images/page1.jpg", "images/page2.png", "credits.txt"}, names)
+}
+
+func TestPackManifestHasNoServiceLinks(t *testing.T) {
+	publication := testPublication()
+	assert.NotNil(t, publication.Manifest.Links.FirstWithRel("self"))
+	assert.NotNil(t, publication.Manifest.Links.FirstWithHref(pub.PositionsLink.URL(nil, nil)))
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, Pack(publication, &buf)) {
+		return
+	}
+	a, err := archive.NewArchiveFactory().OpenBytes(buf.Bytes(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	entry, err := a.Entry(ManifestPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	data, err := entry.Read(0, 0)
+	assert.NoError(t, err)
+
+	var m manifest.Manifest
+	if assert.NoError(t, json.Unmarshal(data, &m)) {
+		assert.Equal(t, "Comic", m.Metadata.Title())
+		assert.Nil(t, m.Links.FirstWithHref(pub.PositionsLink.URL(nil, nil)))
+		assert.Len(t, m.ReadingOrder, 2)
+	}
+}
+
+func TestPackIsDeterministic(t *testing.T) {
+	var a, b bytes.Buffer
+	assert.NoError(t, Pack(testPublication(), &a))
+	assert.NoError(t, Pack(testPublication(), &b))
+	assert.Equal(t, a.Bytes(), b.Bytes())
+}
+
+func TestPackFailsOnMissingResource(t *testing.T) {
+	publication := testPublication()
+	publication.Manifest.Resources = append(publication.Manifest.Resources, manifest.Link{
+		Href: manifest.MustNewHREFFromString("missing.css", false), MediaType: &mediatype.CSS,
+	})
+	assert.Error(t, Pack(publication, io.Discard))
+}
@@ -0,0 +1,363 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// Maximum size of an ID3v2 tag, to avoid allocating huge buffers on corrupted files.
+const maxID3Size = 64 << 20
+
+// Frame IDs of ID3v2.2, mapped to their ID3v2.3 equivalent.
+var id3v22Frames = map[string]string{
+	"TT2": "TIT2", "TAL": "TALB", "TP1": "TPE1", "TP2": "TPE2", "TCM": "TCOM",
+	"TRK": "TRCK", "TPA": "TPOS", "TCO": "TCON", "TYE": "TYER", "TLE": "TLEN",
+	"TXX": "TXXX", "PIC": "APIC",
+}
+
+// Reads the ID3v2 tag of an MP3 file, and computes its duration from the MPEG frames.
+func readMP3(r io.ReadSeeker, options ReadOptions) (*Metadata, error) {
+	md := &Metadata{}
+	var lengthMs float64
+
+	header := make([]byte, 10)
+	if _, err := readAt(r, header, 0); err != nil {
+		return nil, errors.Wrap(err, "failed reading MP3 header")
+	}
+	audioStart := int64(0)
+	if bytes.HasPrefix(header, []byte("ID3")) {
+		tagSize := int64(syncsafe(header[6:10]))
+		if header[5]&0x10 != 0 {
+			tagSize += 10 // Footer
+		}
+		audioStart = 10 + tagSize
+		if tagSize > maxID3Size {
+			return nil, errors.New("ID3 tag is too large")
+		}
+		tag := make([]byte, tagSize)
+		if _, err := readAt(r, tag, 10); err != nil && err != io.ErrUnexpectedEOF {
+			return nil, errors.Wrap(err, "failed reading ID3 tag")
+		}
+		lengthMs = parseID3(md, header[3], header[5], tag)
+	}
+
+	if lengthMs > 0 {
+		md.Duration = lengthMs / 1000
+	} else {
+		md.Duration = mpegDuration(r, audioStart, options)
+	}
+	return md, nil
+}
+
+// Parses the frames of an ID3v2 [tag] in the given major [version].
+// Returns the length of the audio in milliseconds found in a TLEN frame.
+func parseID3(md *Metadata, version byte, flags byte, tag []byte) (lengthMs float64) {
+	if version < 2 || version > 4 {
+		return 0
+	}
+	if flags&0x80 != 0 && version < 4 {
+		tag = removeUnsynchronisation(tag)
+	}
+	if flags&0x40 != 0 && version >= 3 && len(tag) >= 4 {
+		// Extended header
+		var n int
+		if version == 3 {
+			n = int(binary.BigEndian.Uint32(tag)) + 4
+		} else {
+			n = int(syncsafe(tag[:4]))
+		}
+		if n > len(tag) {
+			return 0
+		}
+		tag = tag[n:]
+	}
+
+	for len(tag) > 0 {
+		var id string
+		var size, headerSize int
+		var frameFlags uint16
+		if version == 2 {
+			if len(tag) < 6 {
+				break
+			}
+			id = id3v22Frames[string(tag[:3])]
+			size = int(tag[3])<<16 | int(tag[4])<<8 | int(tag[5])
+			headerSize = 6
+		} else {
+			if len(tag) < 10 {
+				break
+			}
+			id = string(tag[:4])
+			if version == 4 {
+				size = int(syncsafe(tag[4:8]))
+			} else {
+				size = int(binary.BigEndian.Uint32(tag[4:8]))
+			}
+			frameFlags = binary.BigEndian.Uint16(tag[8:10])
+			headerSize = 10
+		}
+		if tag[0] == 0 || size <= 0 || headerSize+size > len(tag) {
+			break // Padding or corrupted frame
+		}
+		data := tag[headerSize : headerSize+size]
+		tag = tag[headerSize+size:]
+
+		if version == 4 {
+			if frameFlags&0x0001 != 0 && len(data) >= 4 {
+				data = data[4:] // Data length indicator
+			}
+			if frameFlags&0x0002 != 0 {
+				data = removeUnsynchronisation(data)
+			}
+			if frameFlags&0x000C != 0 {
+				continue // Compressed or encrypted
+			}
+		} else if version == 3 && frameFlags&0x00C0 != 0 {
+			continue // Compressed or encrypted
+		}
+
+		switch id {
+		case "TIT2":
+			md.Title = first(id3Text(data))
+		case "TALB":
+			md.Album = first(id3Text(data))
+		case "TPE1":
+			for _, v := range id3Text(data) {
+				md.Artists = appendUnique(md.Artists, v)
+			}
+		case "TPE2":
+			for _, v := range id3Text(data) {
+				md.AlbumArtists = appendUnique(md.AlbumArtists, v)
+			}
+		case "TCOM":
+			for _, v := range id3Text(data) {
+				md.Composers = appendUnique(md.Composers, v)
+			}
+		case "TCON":
+			for _, v := range id3Text(data) {
+				md.Genres = appendUnique(md.Genres, id3Genre(v))
+			}
+		case "TRCK":
+			md.Track = parseIndex(first(id3Text(data)))
+		case "TPOS":
+			md.Disc = parseIndex(first(id3Text(data)))
+		case "TYER", "TDRC":
+			md.Date = first(id3Text(data))
+		case "TLEN":
+			if v, err := strconv.ParseFloat(first(id3Text(data)), 64); err == nil && v > 0 {
+				lengthMs = v
+			}
+		case "TXXX":
+			values := id3Text(data)
+			if len(values) >= 2 {
+				setComment(md, values[0], values[1])
+			}
+		case "APIC":
+			if md.Cover == nil || isFrontCover(data, version) {
+				md.Cover = id3Picture(data, version)
+			}
+		case "CHAP":
+			if chapter := id3Chapter(data, version); chapter != nil {
+				md.Chapters = append(md.Chapters, *chapter)
+			}
+		}
+	}
+	return
+}
+
+func syncsafe(b []byte) uint32 {
+	return uint32(b[0]&0x7F)<<21 | uint32(b[1]&0x7F)<<14 | uint32(b[2]&0x7F)<<7 | uint32(b[3]&0x7F)
+}
+
+// Reverts the unsynchronisation scheme, which inserts a zero byte after each 0xFF.
+func removeUnsynchronisation(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte{0xFF, 0x00}, []byte{0xFF})
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Parses the values of a text frame, which are separated by null characters in ID3v2.4.
+func id3Text(data []byte) []string {
+	if len(data) < 1 {
+		return nil
+	}
+	text := decodeID3String(data[0], data[1:])
+	var values []string
+	for _, v := range strings.Split(text, "\x00") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func decodeID3String(encoding byte, b []byte) string {
+	switch encoding {
+	case 0: // ISO-8859-1
+		runes := make([]rune, len(b))
+		for i, c := range b {
+			runes[i] = rune(c)
+		}
+		return string(runes)
+	case 1: // UTF-16 with BOM
+		bigEndian := true
+		if len(b) >= 2 {
+			if b[0] == 0xFF && b[1] == 0xFE {
+				bigEndian = false
+				b = b[2:]
+			} else if b[0] == 0xFE && b[1] == 0xFF {
+				b = b[2:]
+			}
+		}
+		// Each value of a multi-valued frame can have its own BOM
+		text := decodeUTF16(b, bigEndian)
+		return strings.NewReplacer("\uFEFF", "", "\uFFFE", "").Replace(text)
+	case 2: // UTF-16BE
+		return decodeUTF16(b, true)
+	default: // UTF-8
+		return string(b)
+	}
+}
+
+func decodeUTF16(b []byte, bigEndian bool) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		if bigEndian {
+			u = append(u, binary.BigEndian.Uint16(b[i:]))
+		} else {
+			u = append(u, binary.LittleEndian.Uint16(b[i:]))
+		}
+	}
+	return string(utf16.Decode(u))
+}
+
+// Splits a null-terminated string in the given encoding from the rest of [b].
+func splitID3String(encoding byte, b []byte) (string, []byte) {
+	if encoding == 1 || encoding == 2 {
+		for i := 0; i+1 < len(b); i += 2 {
+			if b[i] == 0 && b[i+1] == 0 {
+				return decodeID3String(encoding, b[:i]), b[i+2:]
+			}
+		}
+		return decodeID3String(encoding, b), nil
+	}
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		return decodeID3String(encoding, b[:i]), b[i+1:]
+	}
+	return decodeID3String(encoding, b), nil
+}
+
+// Resolves the ID3v1 genre references, such as "(12)" or "12".
+func id3Genre(v string) string {
+	ref := strings.TrimSuffix(strings.TrimPrefix(v, "("), ")")
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n >= 0 && n < len(id3v1Genres) {
+			return id3v1Genres[n]
+		}
+		return ""
+	}
+	return v
+}
+
+// The first ID3v1 genres, which are the most commonly referenced.
+var id3v1Genres = []string{
+	"Blues", "Classic Rock", "Country", "Dance", "Disco", "Funk", "Grunge", "Hip-Hop", "Jazz", "Metal",
+	"New Age", "Oldies", "Other", "Pop", "R&B", "Rap", "Reggae", "Rock", "Techno", "Industrial",
+	"Alternative", "Ska", "Death Metal", "Pranks", "Soundtrack", "Euro-Techno", "Ambient", "Trip-Hop", "Vocal", "Jazz+Funk",
+	"Fusion", "Trance", "Classical", "Instrumental", "Acid", "House", "Game", "Sound Clip", "Gospel", "Noise",
+	"AlternRock", "Bass", "Soul", "Punk", "Space", "Meditative", "Instrumental Pop", "Instrumental Rock", "Ethnic", "Gothic",
+	"Darkwave", "Techno-Industrial", "Electronic", "Pop-Folk", "Eurodance", "Dream", "Southern Rock", "Comedy", "Cult", "Gangsta",
+	"Top 40", "Christian Rap", "Pop/Funk", "Jungle", "Native American", "Cabaret", "New Wave", "Psychadelic", "Rave", "Showtunes",
+	"Trailer", "Lo-Fi", "Tribal", "Acid Punk", "Acid Jazz", "Polka", "Retro", "Musical", "Rock & Roll", "Hard Rock",
+	"Folk", "Folk-Rock", "National Folk", "Swing", "Fast Fusion", "Bebob", "Latin", "Revival", "Celtic", "Bluegrass",
+	"Avantgarde", "Gothic Rock", "Progressive Rock", "Psychedelic Rock", "Symphonic Rock", "Slow Rock", "Big Band", "Chorus", "Easy Listening", "Acoustic",
+	"Humour", "Speech", "Chanson", "Opera", "Chamber Music", "Sonata", "Symphony", "Booty Bass", "Primus", "Porn Groove",
+	"Satire", "Slow Jam", "Club", "Tango", "Samba", "Folklore", "Ballad", "Power Ballad", "Rhythmic Soul", "Freestyle",
+	"Duet", "Punk Rock", "Drum Solo", "A capella", "Euro-House", "Dance Hall",
+}
+
+// Parses a track or disc number, such as "3" or "3/12".
+func parseIndex(v string) int {
+	v, _, _ = strings.Cut(strings.TrimSpace(v), "/")
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Returns whether the APIC frame [data] is a front cover (picture type 3).
+func isFrontCover(data []byte, version byte) bool {
+	if len(data) < 2 {
+		return false
+	}
+	if version == 2 {
+		return len(data) > 4 && data[4] == 3
+	}
+	i := bytes.IndexByte(data[1:], 0)
+	return i >= 0 && i+2 < len(data) && data[i+2] == 3
+}
+
+func id3Picture(data []byte, version byte) *Picture {
+	if len(data) < 2 {
+		return nil
+	}
+	encoding := data[0]
+	rest := data[1:]
+	var mime string
+	if version == 2 {
+		if len(rest) < 4 {
+			return nil
+		}
+		switch strings.ToUpper(string(rest[:3])) {
+		case "JPG":
+			mime = "image/jpeg"
+		case "PNG":
+			mime = "image/png"
+		}
+		rest = rest[3:]
+	} else {
+		mime, rest = splitID3String(0, rest)
+	}
+	if len(rest) < 1 {
+		return nil
+	}
+	_, rest = splitID3String(encoding, rest[1:]) // Picture type and description
+	if len(rest) == 0 {
+		return nil
+	}
+	if detected := pictureMediaType(rest); detected != "" {
+		mime = detected
+	}
+	if !strings.HasPrefix(mime, "image/") {
+		return nil
+	}
+	return &Picture{MediaType: mime, Data: rest}
+}
+
+// Parses a CHAP frame, from the ID3v2 Chapter Frame Addendum.
+func id3Chapter(data []byte, version byte) *Chapter {
+	_, rest := splitID3String(0, data) // Element ID
+	if len(rest) < 16 {
+		return nil
+	}
+	chapter := &Chapter{
+		Start: float64(binary.BigEndian.Uint32(rest)) / 1000,
+	}
+	// Embedded frames, such as the title of the chapter
+	sub := &Metadata{}
+	parseID3(sub, version, 0, rest[16:])
+	chapter.Title = sub.Title
+	return chapter
+}
@@ -0,0 +1,118 @@
+// Package audio reads the metadata embedded in audio files: ID3v2 tags (MP3), MP4 atoms (M4A, M4B)
+// and Vorbis comments (Ogg Vorbis, Opus, FLAC).
+package audio
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Metadata embedded in an audio file.
+type Metadata struct {
+	Title        string
+	Album        string
+	Artists      []string
+	AlbumArtists []string
+	Composers    []string
+	Narrators    []string
+	Genres       []string
+	Date         string
+	Track        int      // Track number, or 0 when unknown.
+	Disc         int      // Disc number, or 0 when unknown.
+	Duration     float64  // Duration in seconds, or 0 when unknown.
+	Cover        *Picture // Embedded cover art.
+	Chapters     []Chapter
+}
+
+// Picture embedded in an audio file.
+type Picture struct {
+	MediaType string
+	Data      []byte
+}
+
+// Chapter marker of an audio file.
+type Chapter struct {
+	Title string
+	Start float64 // Start time in seconds.
+}
+
+// Returned by [Read] when the format of the audio file is not supported.
+var ErrUnsupportedFormat = errors.New("unsupported audio format")
+
+// Options of [ReadWithOptions].
+type ReadOptions struct {
+	// Skips the durations which are computed from the end of the file, e.g. from the last page of
+	// an Ogg stream. Reading the end of a compressed or remote file can require reading all of it.
+	SkipTail bool
+}
+
+// Reads the metadata embedded in the audio file [r], whose format is sniffed from its content.
+func Read(r io.ReadSeeker) (*Metadata, error) {
+	return ReadWithOptions(r, ReadOptions{})
+}
+
+// Reads the metadata embedded in the audio file [r] with the given [options].
+func ReadWithOptions(r io.ReadSeeker, options ReadOptions) (*Metadata, error) {
+	header := make([]byte, 12)
+	n, err := readAt(r, header, 0)
+	if err != nil && n < 4 {
+		return nil, errors.Wrap(err, "failed reading audio file header")
+	}
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, []byte("ID3")) || isMPEGFrameHeader(header):
+		return readMP3(r, options)
+	case len(header) >= 8 && bytes.Equal(header[4:8], []byte("ftyp")):
+		return readMP4(r)
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return readOgg(r, options)
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return readFLAC(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Reads exactly len(p) bytes at [offset], or less at the end of the file.
+func readAt(r io.ReadSeeker, p []byte, offset int64) (int, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(r, p)
+}
+
+// Returns the size of [r].
+func size(r io.ReadSeeker) (int64, error) {
+	return r.Seek(0, io.SeekEnd)
+}
+
+// Guesses the media type of a picture from its signature.
+func pictureMediaType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return "image/jpeg"
+	case bytes.HasPrefix(data, []byte("\x89PNG")):
+		return "image/png"
+	case bytes.HasPrefix(data, []byte("GIF8")):
+		return "image/gif"
+	case len(data) >= 12 && bytes.HasPrefix(data, []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return "image/webp"
+	default:
+		return ""
+	}
+}
+
+func appendUnique(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,146 @@
+package audio
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readFile(t *testing.T, path string) *Metadata {
+	f, err := os.Open(path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer f.Close()
+	md, err := Read(f)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return md
+}
+
+func TestReadID3(t *testing.T) {
+	md := readFile(t, "testdata/tags.mp3")
+	assert.Equal(t, "Chapter Ünë", md.Title)
+	assert.Equal(t, "The Audio Book", md.Album)
+	assert.Equal(t, []string{"Ann Author"}, md.Artists)
+	assert.Equal(t, []string{"Nat Narrator"}, md.Narrators)
+	assert.Equal(t, []string{"Speech"}, md.Genres)
+	assert.Equal(t, "2020", md.Date)
+	assert.Equal(t, 2, md.Track)
+	assert.InDelta(t, 100*1152/44100.0, md.Duration, 0.0001) // From the Xing header
+	if assert.NotNil(t, md.Cover) {
+		assert.Equal(t, "image/jpeg", md.Cover.MediaType)
+	}
+	assert.Equal(t, []Chapter{{"Intro", 0}, {"Main", 1.5}}, md.Chapters)
+}
+
+func TestReadMP4(t *testing.T) {
+	md := readFile(t, "testdata/chapters.m4b")
+	assert.Equal(t, "Part One", md.Title)
+	assert.Equal(t, "Atoms", md.Album)
+	assert.Equal(t, []string{"Mia Writer"}, md.Artists)
+	assert.Equal(t, []string{"Ned Reader"}, md.Narrators)
+	assert.Equal(t, []string{"Fiction"}, md.Genres)
+	assert.Equal(t, "2019-05-04", md.Date)
+	assert.Equal(t, 1, md.Track)
+	assert.Equal(t, 65.0, md.Duration)
+	if assert.NotNil(t, md.Cover) {
+		assert.Equal(t, "image/png", md.Cover.MediaType)
+	}
+	assert.Equal(t, []Chapter{{"Opening", 0}, {"Ending", 30.5}}, md.Chapters)
+}
+
+func TestReadMP4ChapterTrack(t *testing.T) {
+	md := readFile(t, "testdata/chapter_track.m4a")
+	assert.Equal(t, 30.0, md.Duration)
+	assert.Equal(t, []Chapter{{"First", 0}, {"Second", 20}}, md.Chapters)
+}
+
+func TestReadOggVorbis(t *testing.T) {
+	md := readFile(t, "testdata/tags.ogg")
+	assert.Equal(t, "Vorbis Track", md.Title)
+	assert.Equal(t, "Ogg Book", md.Album)
+	assert.Equal(t, []string{"Olga Author"}, md.Artists)
+	assert.Equal(t, []string{"Pat Performer"}, md.Narrators)
+	assert.Equal(t, 3, md.Track)
+	assert.Equal(t, 3.0, md.Duration)
+	if assert.NotNil(t, md.Cover) {
+		assert.Equal(t, "image/jpeg", md.Cover.MediaType)
+	}
+	assert.Equal(t, []Chapter{{"First", 0}, {"Second", 10.5}}, md.Chapters)
+}
+
+// Records the furthest position read in a file.
+type tailRecorder struct {
+	*bytes.Reader
+	furthest int64
+}
+
+func (r *tailRecorder) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if pos := r.Reader.Size() - int64(r.Reader.Len()); pos > r.furthest {
+		r.furthest = pos
+	}
+	return n, err
+}
+
+func TestReadSkipTail(t *testing.T) {
+	for _, name := range []string{"testdata/tags.ogg", "testdata/tags.mp3"} {
+		data, err := os.ReadFile(name)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// Padding, standing for the audio data
+		data = append(data, make([]byte, 256*1024)...)
+		r := &tailRecorder{Reader: bytes.NewReader(data)}
+		md, err := ReadWithOptions(r, ReadOptions{SkipTail: true})
+		if assert.NoError(t, err, name) {
+			assert.NotEmpty(t, md.Title, name)
+			assert.Less(t, r.furthest, int64(len(data)-128*1024), name)
+		}
+	}
+
+	// Without the end of the file, the duration of an Ogg stream is unknown.
+	data, _ := os.ReadFile("testdata/tags.ogg")
+	md, err := ReadWithOptions(bytes.NewReader(data), ReadOptions{SkipTail: true})
+	if assert.NoError(t, err) {
+		assert.Zero(t, md.Duration)
+	}
+}
+
+func TestReadFLAC(t *testing.T) {
+	md := readFile(t, "testdata/tags.flac")
+	assert.Equal(t, "Flac Track", md.Title)
+	assert.Equal(t, []string{"Fay Author"}, md.AlbumArtists)
+	assert.Equal(t, []string{"Nia Narrator"}, md.Narrators)
+	assert.Equal(t, 1, md.Disc)
+	assert.Equal(t, 4, md.Track)
+	assert.Equal(t, 4.0, md.Duration)
+	assert.NotNil(t, md.Cover)
+}
+
+func TestReadUnsupportedFormat(t *testing.T) {
+	f, err := os.Open("metadata.go")
+	assert.NoError(t, err)
+	defer f.Close()
+	_, err = Read(f)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestParseTimestamp(t *testing.T) {
+	for in, expected := range map[string]float64{
+		"00:00:10.500": 10.5,
+		"01:02:03":     3723,
+		"2:30":         150,
+		"42.25":        42.25,
+	} {
+		v, ok := parseTimestamp(in)
+		assert.True(t, ok)
+		assert.Equal(t, expected, v, in)
+	}
+	_, ok := parseTimestamp("10:xx")
+	assert.False(t, ok)
+}
@@ -0,0 +1,420 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Maximum size of an MP4 atom loaded in memory, such as the metadata item list with its cover art.
+const maxMP4AtomSize = 32 << 20
+
+type mp4Atom struct {
+	typ    string
+	offset int64 // Offset of the content of the atom, after its header.
+	size   int64 // Size of the content of the atom.
+}
+
+type mp4Reader struct {
+	r io.ReadSeeker
+}
+
+// Lists the atoms contained in the range [offset, offset+length).
+func (m mp4Reader) children(offset, length int64) []mp4Atom {
+	var atoms []mp4Atom
+	end := offset + length
+	header := make([]byte, 16)
+	for offset+8 <= end {
+		if _, err := readAt(m.r, header[:8], offset); err != nil {
+			break
+		}
+		size := int64(binary.BigEndian.Uint32(header))
+		headerSize := int64(8)
+		switch size {
+		case 0: // Up to the end of the parent
+			size = end - offset
+		case 1: // 64-bit size
+			if _, err := readAt(m.r, header[8:16], offset+8); err != nil {
+				return atoms
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:]))
+			headerSize = 16
+		}
+		if size < headerSize || offset+size > end {
+			break
+		}
+		atoms = append(atoms, mp4Atom{
+			typ:    string(header[4:8]),
+			offset: offset + headerSize,
+			size:   size - headerSize,
+		})
+		offset += size
+	}
+	return atoms
+}
+
+func (m mp4Reader) child(parent mp4Atom, path ...string) *mp4Atom {
+	current := parent
+	for _, typ := range path {
+		found := false
+		offset, length := current.offset, current.size
+		if current.typ == "meta" {
+			// The meta atom is a full atom in MP4 files, but not in QuickTime files.
+			version := make([]byte, 8)
+			if _, err := readAt(m.r, version, offset); err == nil && !bytes.Equal(version[4:], []byte("hdlr")) {
+				offset, length = offset+4, length-4
+			}
+		}
+		for _, a := range m.children(offset, length) {
+			if a.typ == typ {
+				current, found = a, true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return &current
+}
+
+func (m mp4Reader) read(a mp4Atom) ([]byte, error) {
+	if a.size > maxMP4AtomSize {
+		return nil, errors.Errorf("MP4 atom %q is too large", a.typ)
+	}
+	b := make([]byte, a.size)
+	if _, err := readAt(m.r, b, a.offset); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Reads the metadata of an MP4 audio file (M4A, M4B), from its iTunes-style item list.
+// Chapters are read from a Nero chapter list (chpl atom) or a QuickTime chapter track.
+func readMP4(r io.ReadSeeker) (*Metadata, error) {
+	total, err := size(r)
+	if err != nil {
+		return nil, err
+	}
+	m := mp4Reader{r: r}
+	root := mp4Atom{offset: 0, size: total}
+	moov := m.child(root, "moov")
+	if moov == nil {
+		return nil, errors.New("missing MP4 moov atom")
+	}
+
+	md := &Metadata{}
+	if mvhd := m.child(*moov, "mvhd"); mvhd != nil {
+		if b, err := m.read(*mvhd); err == nil {
+			md.Duration = mp4Duration(b)
+		}
+	}
+
+	ilst := m.child(*moov, "udta", "meta", "ilst")
+	if ilst == nil {
+		ilst = m.child(*moov, "meta", "ilst")
+	}
+	if ilst != nil {
+		for _, item := range m.children(ilst.offset, ilst.size) {
+			if b, err := m.read(item); err == nil {
+				parseMP4Item(md, item.typ, b)
+			}
+		}
+	}
+
+	if chpl := m.child(*moov, "udta", "chpl"); chpl != nil {
+		if b, err := m.read(*chpl); err == nil {
+			md.Chapters = parseChpl(b)
+		}
+	}
+	if len(md.Chapters) == 0 {
+		md.Chapters = m.chapterTrack(*moov)
+	}
+	return md, nil
+}
+
+// Computes the duration in seconds from the content of a mvhd or mdhd atom.
+func mp4Duration(b []byte) float64 {
+	timescale, duration := mp4Timescale(b)
+	if timescale == 0 {
+		return 0
+	}
+	return float64(duration) / float64(timescale)
+}
+
+// Returns the timescale and duration of a mvhd or mdhd atom.
+func mp4Timescale(b []byte) (timescale uint32, duration uint64) {
+	if len(b) < 1 {
+		return 0, 0
+	}
+	if b[0] == 1 {
+		if len(b) < 32 {
+			return 0, 0
+		}
+		return binary.BigEndian.Uint32(b[20:]), binary.BigEndian.Uint64(b[24:])
+	}
+	if len(b) < 20 {
+		return 0, 0
+	}
+	return binary.BigEndian.Uint32(b[12:]), uint64(binary.BigEndian.Uint32(b[16:]))
+}
+
+// Parses an item of the ilst atom, which contains one or more data atoms.
+func parseMP4Item(md *Metadata, typ string, b []byte) {
+	var name string
+	var values [][]byte
+	var types []uint32
+	for len(b) >= 8 {
+		size := int(binary.BigEndian.Uint32(b))
+		if size < 8 || size > len(b) {
+			break
+		}
+		atom, content := string(b[4:8]), b[8:size]
+		b = b[size:]
+		switch atom {
+		case "name": // Freeform (----) items
+			if len(content) >= 4 {
+				name = string(content[4:])
+			}
+		case "data":
+			if len(content) >= 8 {
+				types = append(types, binary.BigEndian.Uint32(content)&0xFFFFFF)
+				values = append(values, content[8:])
+			}
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	text := strings.TrimSpace(string(values[0]))
+
+	switch typ {
+	case "\xa9nam":
+		md.Title = text
+	case "\xa9alb":
+		md.Album = text
+	case "\xa9ART":
+		md.Artists = appendUnique(md.Artists, text)
+	case "aART":
+		md.AlbumArtists = appendUnique(md.AlbumArtists, text)
+	case "\xa9wrt":
+		md.Composers = appendUnique(md.Composers, text)
+	case "\xa9nrt":
+		md.Narrators = appendUnique(md.Narrators, text)
+	case "\xa9gen":
+		md.Genres = appendUnique(md.Genres, text)
+	case "gnre":
+		if v := values[0]; len(v) >= 2 {
+			if n := int(binary.BigEndian.Uint16(v)); n > 0 && n <= len(id3v1Genres) {
+				md.Genres = appendUnique(md.Genres, id3v1Genres[n-1])
+			}
+		}
+	case "\xa9day":
+		md.Date = text
+	case "trkn", "disk":
+		if v := values[0]; len(v) >= 4 {
+			n := int(binary.BigEndian.Uint16(v[2:]))
+			if typ == "trkn" {
+				md.Track = n
+			} else {
+				md.Disc = n
+			}
+		}
+	case "covr":
+		for i, v := range values {
+			mime := pictureMediaType(v)
+			switch types[i] {
+			case 13:
+				mime = "image/jpeg"
+			case 14:
+				mime = "image/png"
+			}
+			if mime == "" || len(v) == 0 {
+				continue
+			}
+			md.Cover = &Picture{MediaType: mime, Data: v}
+			break
+		}
+	case "----":
+		if name != "" {
+			setComment(md, name, text)
+		}
+	}
+}
+
+// Parses a Nero chapter list, whose start times are expressed in 100 nanoseconds.
+func parseChpl(b []byte) []Chapter {
+	if len(b) < 5 {
+		return nil
+	}
+	offset := 4
+	if b[0] == 1 {
+		offset += 4
+	}
+	if len(b) < offset+1 {
+		return nil
+	}
+	count := int(b[offset])
+	b = b[offset+1:]
+
+	var chapters []Chapter
+	for i := 0; i < count && len(b) >= 9; i++ {
+		start := binary.BigEndian.Uint64(b)
+		n := int(b[8])
+		b = b[9:]
+		if n > len(b) {
+			break
+		}
+		chapters = append(chapters, Chapter{
+			Title: strings.TrimSpace(string(b[:n])),
+			Start: float64(start) / 10_000_000,
+		})
+		b = b[n:]
+	}
+	return chapters
+}
+
+// Reads the chapters from the QuickTime text track referenced by a chap track reference.
+func (m mp4Reader) chapterTrack(moov mp4Atom) []Chapter {
+	tracks := make(map[uint32]mp4Atom)
+	var chapterID uint32
+	for _, trak := range m.children(moov.offset, moov.size) {
+		if trak.typ != "trak" {
+			continue
+		}
+		if tkhd := m.child(trak, "tkhd"); tkhd != nil {
+			if b, err := m.read(*tkhd); err == nil && len(b) >= 24 {
+				id := binary.BigEndian.Uint32(b[12:])
+				if b[0] == 1 {
+					id = binary.BigEndian.Uint32(b[20:])
+				}
+				tracks[id] = trak
+			}
+		}
+		if chap := m.child(trak, "tref", "chap"); chap != nil && chapterID == 0 {
+			if b, err := m.read(*chap); err == nil && len(b) >= 4 {
+				chapterID = binary.BigEndian.Uint32(b)
+			}
+		}
+	}
+	trak, ok := tracks[chapterID]
+	if chapterID == 0 || !ok {
+		return nil
+	}
+
+	var timescale uint32
+	if mdhd := m.child(trak, "mdia", "mdhd"); mdhd != nil {
+		if b, err := m.read(*mdhd); err == nil {
+			timescale, _ = mp4Timescale(b)
+		}
+	}
+	stbl := m.child(trak, "mdia", "minf", "stbl")
+	if timescale == 0 || stbl == nil {
+		return nil
+	}
+	table := func(typ string) []byte {
+		if a := m.child(*stbl, typ); a != nil {
+			if b, err := m.read(*a); err == nil && len(b) >= 8 {
+				return b
+			}
+		}
+		return nil
+	}
+
+	// Start time of each sample
+	var starts []float64
+	if stts := table("stts"); stts != nil {
+		var t uint64
+		count := int(binary.BigEndian.Uint32(stts[4:]))
+		for i := 0; i < count && 8+i*8+8 <= len(stts); i++ {
+			n := binary.BigEndian.Uint32(stts[8+i*8:])
+			delta := binary.BigEndian.Uint32(stts[12+i*8:])
+			for j := uint32(0); j < n && len(starts) < 10000; j++ {
+				starts = append(starts, float64(t)/float64(timescale))
+				t += uint64(delta)
+			}
+		}
+	}
+
+	// Size of each sample
+	var sizes []uint32
+	if stsz := table("stsz"); stsz != nil && len(stsz) >= 12 {
+		sampleSize := binary.BigEndian.Uint32(stsz[4:])
+		count := int(binary.BigEndian.Uint32(stsz[8:]))
+		for i := 0; i < count && i < len(starts); i++ {
+			if sampleSize != 0 {
+				sizes = append(sizes, sampleSize)
+			} else if 12+i*4+4 <= len(stsz) {
+				sizes = append(sizes, binary.BigEndian.Uint32(stsz[12+i*4:]))
+			}
+		}
+	}
+
+	// Offset of each chunk
+	var chunks []int64
+	if stco := table("stco"); stco != nil {
+		count := int(binary.BigEndian.Uint32(stco[4:]))
+		for i := 0; i < count && 8+i*4+4 <= len(stco); i++ {
+			chunks = append(chunks, int64(binary.BigEndian.Uint32(stco[8+i*4:])))
+		}
+	} else if co64 := table("co64"); co64 != nil {
+		count := int(binary.BigEndian.Uint32(co64[4:]))
+		for i := 0; i < count && 8+i*8+8 <= len(co64); i++ {
+			chunks = append(chunks, int64(binary.BigEndian.Uint64(co64[8+i*8:])))
+		}
+	}
+
+	// Offset of each sample, from the sample-to-chunk table
+	var offsets []int64
+	if stsc := table("stsc"); stsc != nil {
+		count := int(binary.BigEndian.Uint32(stsc[4:]))
+		sample := 0
+		for i := 0; i < count && 8+i*12+12 <= len(stsc); i++ {
+			firstChunk := int(binary.BigEndian.Uint32(stsc[8+i*12:])) - 1
+			perChunk := int(binary.BigEndian.Uint32(stsc[12+i*12:]))
+			lastChunk := len(chunks) - 1
+			if i+1 < count && 8+(i+1)*12+4 <= len(stsc) {
+				lastChunk = int(binary.BigEndian.Uint32(stsc[8+(i+1)*12:])) - 2
+			}
+			for c := firstChunk; c <= lastChunk && c >= 0 && c < len(chunks); c++ {
+				offset := chunks[c]
+				for s := 0; s < perChunk && sample < len(sizes); s++ {
+					offsets = append(offsets, offset)
+					offset += int64(sizes[sample])
+					sample++
+				}
+			}
+		}
+	}
+
+	var chapters []Chapter
+	for i, offset := range offsets {
+		if i >= len(starts) || sizes[i] < 2 || sizes[i] > 4096 {
+			continue
+		}
+		b := make([]byte, sizes[i])
+		if _, err := readAt(m.r, b, offset); err != nil {
+			continue
+		}
+		n := int(binary.BigEndian.Uint16(b))
+		if n > len(b)-2 {
+			n = len(b) - 2
+		}
+		chapters = append(chapters, Chapter{
+			Title: strings.TrimSpace(decodeMP4Text(b[2 : 2+n])),
+			Start: starts[i],
+		})
+	}
+	return chapters
+}
+
+// Decodes the text of a QuickTime text sample, which is UTF-16 when it starts with a BOM.
+func decodeMP4Text(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+		return decodeUTF16(b[2:], true)
+	}
+	return string(b)
+}
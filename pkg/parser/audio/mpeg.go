@@ -0,0 +1,154 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// Bitrates in kbps, by MPEG version (1 or 2), layer and bitrate index.
+var mpegBitrates = [2][3][15]int{
+	{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	},
+	{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	},
+}
+
+// Sample rates in Hz, by MPEG version (1, 2 or 2.5) and sample rate index.
+var mpegSampleRates = [3][3]int{
+	{44100, 48000, 32000},
+	{22050, 24000, 16000},
+	{11025, 12000, 8000},
+}
+
+type mpegFrame struct {
+	version    int // 0 for MPEG 1, 1 for MPEG 2 and 2 for MPEG 2.5
+	layer      int // 0 for layer I, 1 for layer II and 2 for layer III
+	bitrate    int // kbps
+	sampleRate int
+	mono       bool
+}
+
+func (f mpegFrame) samplesPerFrame() int {
+	switch {
+	case f.layer == 0:
+		return 384
+	case f.layer == 2 && f.version > 0:
+		return 576
+	default:
+		return 1152
+	}
+}
+
+func isMPEGFrameHeader(b []byte) bool {
+	_, ok := parseMPEGFrameHeader(b)
+	return ok
+}
+
+func parseMPEGFrameHeader(b []byte) (mpegFrame, bool) {
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return mpegFrame{}, false
+	}
+	var f mpegFrame
+	switch (b[1] >> 3) & 0x03 {
+	case 0:
+		f.version = 2
+	case 2:
+		f.version = 1
+	case 3:
+		f.version = 0
+	default:
+		return f, false
+	}
+	layer := (b[1] >> 1) & 0x03
+	if layer == 0 {
+		return f, false
+	}
+	f.layer = 3 - int(layer)
+
+	bitrateIndex := b[2] >> 4
+	sampleRateIndex := (b[2] >> 2) & 0x03
+	if bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+		return f, false
+	}
+	f.bitrate = mpegBitrates[min(f.version, 1)][f.layer][bitrateIndex]
+	f.sampleRate = mpegSampleRates[f.version][sampleRateIndex]
+	f.mono = b[3]>>6 == 3
+	return f, true
+}
+
+// Computes the duration of MPEG audio starting at [offset], in seconds.
+// The number of frames is read from a Xing/Info or VBRI header when available,
+// otherwise the bitrate of the first frame is assumed to be constant.
+func mpegDuration(r io.ReadSeeker, offset int64, options ReadOptions) float64 {
+	buf := make([]byte, 64*1024)
+	n, _ := readAt(r, buf, offset)
+	buf = buf[:n]
+
+	// Looks for the first frame
+	start := -1
+	var frame mpegFrame
+	for i := 0; i+4 <= len(buf); i++ {
+		if buf[i] != 0xFF {
+			continue
+		}
+		if f, ok := parseMPEGFrameHeader(buf[i:]); ok {
+			start, frame = i, f
+			break
+		}
+	}
+	if start < 0 {
+		return 0
+	}
+	data := buf[start:]
+
+	// Xing or Info header of VBR files, after the side information
+	xing := 4 + 32
+	switch {
+	case frame.version == 0 && frame.mono:
+		xing = 4 + 17
+	case frame.version > 0 && !frame.mono:
+		xing = 4 + 17
+	case frame.version > 0 && frame.mono:
+		xing = 4 + 9
+	}
+	if len(data) >= xing+12 {
+		tag := data[xing : xing+4]
+		if bytes.Equal(tag, []byte("Xing")) || bytes.Equal(tag, []byte("Info")) {
+			flags := binary.BigEndian.Uint32(data[xing+4:])
+			if flags&0x01 != 0 {
+				frames := binary.BigEndian.Uint32(data[xing+8:])
+				return float64(frames) * float64(frame.samplesPerFrame()) / float64(frame.sampleRate)
+			}
+		}
+	}
+
+	// VBRI header, written by the Fraunhofer encoder
+	if len(data) >= 4+32+18 && bytes.Equal(data[36:40], []byte("VBRI")) {
+		frames := binary.BigEndian.Uint32(data[36+14:])
+		return float64(frames) * float64(frame.samplesPerFrame()) / float64(frame.sampleRate)
+	}
+
+	// Constant bitrate
+	total, err := size(r)
+	if err != nil {
+		return 0
+	}
+	audioSize := total - offset - int64(start)
+	trailer := make([]byte, 3)
+	if total >= 128 && !options.SkipTail {
+		if _, err := readAt(r, trailer, total-128); err == nil && bytes.Equal(trailer, []byte("TAG")) {
+			audioSize -= 128 // ID3v1 tag
+		}
+	}
+	if audioSize <= 0 {
+		return 0
+	}
+	return float64(audioSize) * 8 / float64(frame.bitrate*1000)
+}
@@ -0,0 +1,195 @@
+package audio
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/readium/xmlquery"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Track of a playlist.
+type PlaylistEntry struct {
+	Location string  // Path or URL of the track, as written in the playlist.
+	Title    string  // Title of the track, if any.
+	Duration float64 // Duration in seconds, or 0 when unknown.
+}
+
+// Returns whether the file [name] is a playlist supported by [ParsePlaylist], according to its extension.
+func IsPlaylist(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".m3u", ".m3u8", ".pls", ".xspf", ".wpl":
+		return true
+	default:
+		return false
+	}
+}
+
+// Parses the entries of an M3U/M3U8, PLS, XSPF or WPL playlist, whose format is determined from
+// the extension of the file [name].
+func ParsePlaylist(name string, data []byte) ([]PlaylistEntry, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".m3u", ".m3u8":
+		return parseM3U(data), nil
+	case ".pls":
+		return parsePLS(data), nil
+	case ".xspf":
+		return parseXSPF(data)
+	case ".wpl":
+		return parseWPL(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Decodes a text playlist, which is in Windows-1252 when it's not valid UTF-8 (legacy M3U and PLS).
+func playlistText(data []byte) string {
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	s, err := charmap.Windows1252.NewDecoder().Bytes(data)
+	if err != nil {
+		return string(data)
+	}
+	return string(s)
+}
+
+// Parses an M3U playlist, with the optional #EXTINF:<duration>,<title> directives.
+func parseM3U(data []byte) []PlaylistEntry {
+	var entries []PlaylistEntry
+	var current PlaylistEntry
+	scanner := bufio.NewScanner(strings.NewReader(playlistText(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if info, ok := strings.CutPrefix(line, "#EXTINF:"); ok {
+			duration, title, _ := strings.Cut(info, ",")
+			// The duration can be followed by attributes, e.g. #EXTINF:120 tvg-id="x",Title
+			duration, _, _ = strings.Cut(strings.TrimSpace(duration), " ")
+			current.Title = strings.TrimSpace(title)
+			if d, err := strconv.ParseFloat(duration, 64); err == nil && d > 0 {
+				current.Duration = d
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		current.Location = line
+		entries = append(entries, current)
+		current = PlaylistEntry{}
+	}
+	return entries
+}
+
+// Parses a PLS playlist, made of FileN, TitleN and LengthN keys.
+func parsePLS(data []byte) []PlaylistEntry {
+	entries := make(map[int]*PlaylistEntry)
+	scanner := bufio.NewScanner(strings.NewReader(playlistText(data)))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		var field string
+		for _, f := range []string{"file", "title", "length"} {
+			if strings.HasPrefix(key, f) {
+				field = f
+				break
+			}
+		}
+		if field == "" {
+			continue
+		}
+		n, err := strconv.Atoi(key[len(field):])
+		if err != nil {
+			continue
+		}
+		if entries[n] == nil {
+			entries[n] = &PlaylistEntry{}
+		}
+		switch field {
+		case "file":
+			entries[n].Location = value
+		case "title":
+			entries[n].Title = value
+		case "length":
+			if d, err := strconv.ParseFloat(value, 64); err == nil && d > 0 {
+				entries[n].Duration = d
+			}
+		}
+	}
+
+	numbers := make([]int, 0, len(entries))
+	for n, e := range entries {
+		if e.Location != "" {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+	res := make([]PlaylistEntry, 0, len(numbers))
+	for _, n := range numbers {
+		res = append(res, *entries[n])
+	}
+	return res
+}
+
+func localName(name string) string {
+	return "*[local-name()='" + name + "']"
+}
+
+// Parses an XSPF playlist, whose track durations are expressed in milliseconds.
+// https://www.xspf.org/spec
+func parseXSPF(data []byte) ([]PlaylistEntry, error) {
+	doc, err := xmlquery.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing XSPF playlist")
+	}
+	var entries []PlaylistEntry
+	for _, track := range doc.SelectElements("//" + localName("trackList") + "/" + localName("track")) {
+		location := track.SelectElement(localName("location"))
+		if location == nil || strings.TrimSpace(location.InnerText()) == "" {
+			continue
+		}
+		entry := PlaylistEntry{
+			Location: strings.TrimSpace(location.InnerText()),
+		}
+		if title := track.SelectElement(localName("title")); title != nil {
+			entry.Title = strings.TrimSpace(title.InnerText())
+		}
+		if duration := track.SelectElement(localName("duration")); duration != nil {
+			if ms, err := strconv.ParseFloat(strings.TrimSpace(duration.InnerText()), 64); err == nil && ms > 0 {
+				entry.Duration = ms / 1000
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Parses a Windows Media Player playlist, which is a SMIL document without titles nor durations.
+func parseWPL(data []byte) ([]PlaylistEntry, error) {
+	doc, err := xmlquery.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing WPL playlist")
+	}
+	var entries []PlaylistEntry
+	for _, media := range doc.SelectElements("//" + localName("seq") + "/" + localName("media")) {
+		if src := strings.TrimSpace(media.SelectAttr("src")); src != "" {
+			entries = append(entries, PlaylistEntry{Location: src})
+		}
+	}
+	return entries, nil
+}
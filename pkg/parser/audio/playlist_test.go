@@ -0,0 +1,63 @@
+package audio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaylistM3U(t *testing.T) {
+	entries, err := ParsePlaylist("book.m3u", []byte("#EXTM3U\r\n#EXTINF:123 tvg-id=\"x\",Chapter, One\r\n01.mp3\r\n\r\n# comment\r\nsub\\02.mp3\r\n#EXTINF:-1,\xC9t\xE9\r\n03.mp3\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []PlaylistEntry{
+		{Location: "01.mp3", Title: "Chapter, One", Duration: 123},
+		{Location: "sub\\02.mp3"},
+		{Location: "03.mp3", Title: "Été"},
+	}, entries)
+}
+
+func TestPlaylistPLS(t *testing.T) {
+	entries, err := ParsePlaylist("book.PLS", []byte("[playlist]\nFile2=b.mp3\nTitle2=B\nFile1=a.mp3\nTitle1=A\nLength1=61\nLength2=-1\nNumberOfEntries=2\nVersion=2\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []PlaylistEntry{
+		{Location: "a.mp3", Title: "A", Duration: 61},
+		{Location: "b.mp3", Title: "B"},
+	}, entries)
+}
+
+func TestPlaylistXSPF(t *testing.T) {
+	entries, err := ParsePlaylist("book.xspf", []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <trackList>
+    <track><location>Part%201.mp3</location><title>Part 1</title><duration>1500</duration></track>
+    <track><title>No location</title></track>
+    <track><location>file:///home/me/Part%202.mp3</location></track>
+  </trackList>
+</playlist>`))
+	assert.NoError(t, err)
+	assert.Equal(t, []PlaylistEntry{
+		{Location: "Part%201.mp3", Title: "Part 1", Duration: 1.5},
+		{Location: "file:///home/me/Part%202.mp3"},
+	}, entries)
+}
+
+func TestPlaylistWPL(t *testing.T) {
+	entries, err := ParsePlaylist("book.wpl", []byte(`<?wpl version="1.0"?>
+<smil>
+  <head><title>Book</title></head>
+  <body><seq>
+    <media src="..\Book\01.wma"/>
+    <media src="02.mp3"/>
+  </seq></body>
+</smil>`))
+	assert.NoError(t, err)
+	assert.Equal(t, []PlaylistEntry{
+		{Location: "..\\Book\\01.wma"},
+		{Location: "02.mp3"},
+	}, entries)
+}
+
+func TestPlaylistUnsupported(t *testing.T) {
+	_, err := ParsePlaylist("book.asx", nil)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
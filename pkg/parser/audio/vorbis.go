@@ -0,0 +1,353 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Maximum size of the header packets of an Ogg stream, which can embed a cover art.
+const maxOggHeaderSize = 16 << 20
+
+// Reads the Vorbis comments of an Ogg Vorbis or Opus file, and computes its duration
+// from the granule position of the last page, unless [ReadOptions.SkipTail] is set.
+func readOgg(r io.ReadSeeker, options ReadOptions) (*Metadata, error) {
+	packets, serial, err := oggHeaderPackets(r, 2)
+	if err != nil {
+		return nil, err
+	}
+	if len(packets) < 2 {
+		return nil, errors.New("missing Ogg header packets")
+	}
+	ident, comment := packets[0], packets[1]
+
+	md := &Metadata{}
+	var sampleRate, preSkip int64
+	switch {
+	case bytes.HasPrefix(ident, []byte("\x01vorbis")) && len(ident) >= 16:
+		sampleRate = int64(binary.LittleEndian.Uint32(ident[12:]))
+		if !bytes.HasPrefix(comment, []byte("\x03vorbis")) {
+			return nil, errors.New("missing Vorbis comment header")
+		}
+		parseVorbisComments(md, comment[7:])
+	case bytes.HasPrefix(ident, []byte("OpusHead")) && len(ident) >= 12:
+		// Opus granule positions are always expressed at 48 kHz
+		sampleRate = 48000
+		preSkip = int64(binary.LittleEndian.Uint16(ident[10:]))
+		if !bytes.HasPrefix(comment, []byte("OpusTags")) {
+			return nil, errors.New("missing Opus tags header")
+		}
+		parseVorbisComments(md, comment[8:])
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	if options.SkipTail {
+		return md, nil
+	}
+	if granule := lastOggGranule(r, serial); granule > preSkip && sampleRate > 0 {
+		md.Duration = float64(granule-preSkip) / float64(sampleRate)
+	}
+	return md, nil
+}
+
+// Reads the first [count] packets of the first logical stream of an Ogg file.
+func oggHeaderPackets(r io.ReadSeeker, count int) (packets [][]byte, serial uint32, err error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+	var current []byte
+	total := 0
+	header := make([]byte, 27)
+	first := true
+	for len(packets) < count {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return packets, serial, nil
+		}
+		if !bytes.HasPrefix(header, []byte("OggS")) {
+			return nil, 0, errors.New("invalid Ogg page")
+		}
+		pageSerial := binary.LittleEndian.Uint32(header[14:])
+		if first {
+			serial = pageSerial
+			first = false
+		}
+		lacing := make([]byte, header[26])
+		if _, err := io.ReadFull(r, lacing); err != nil {
+			return nil, 0, err
+		}
+		dataSize := 0
+		for _, l := range lacing {
+			dataSize += int(l)
+		}
+		if pageSerial != serial {
+			if _, err := r.Seek(int64(dataSize), io.SeekCurrent); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+		total += dataSize
+		if total > maxOggHeaderSize {
+			return nil, 0, errors.New("Ogg header packets are too large")
+		}
+		data := make([]byte, dataSize)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, 0, err
+		}
+		for _, l := range lacing {
+			current = append(current, data[:l]...)
+			data = data[l:]
+			if l < 255 {
+				packets = append(packets, current)
+				current = nil
+				if len(packets) == count {
+					break
+				}
+			}
+		}
+	}
+	return packets, serial, nil
+}
+
+// Returns the granule position of the last page of the logical stream [serial], or -1.
+func lastOggGranule(r io.ReadSeeker, serial uint32) int64 {
+	total, err := size(r)
+	if err != nil {
+		return -1
+	}
+	offset := max(total-64*1024, 0)
+	buf := make([]byte, total-offset)
+	n, _ := readAt(r, buf, offset)
+	buf = buf[:n]
+
+	granule := int64(-1)
+	for i := 0; ; {
+		j := bytes.Index(buf[i:], []byte("OggS"))
+		if j < 0 || i+j+27 > len(buf) {
+			break
+		}
+		page := buf[i+j:]
+		if page[4] == 0 && binary.LittleEndian.Uint32(page[14:]) == serial {
+			if g := int64(binary.LittleEndian.Uint64(page[6:])); g >= 0 {
+				granule = g
+			}
+		}
+		i += j + 4
+	}
+	return granule
+}
+
+// Reads the metadata blocks of a FLAC file.
+func readFLAC(r io.ReadSeeker) (*Metadata, error) {
+	md := &Metadata{}
+	offset := int64(4)
+	header := make([]byte, 4)
+	for {
+		if _, err := readAt(r, header, offset); err != nil {
+			return nil, errors.Wrap(err, "failed reading FLAC metadata block")
+		}
+		last := header[0]&0x80 != 0
+		typ := header[0] & 0x7F
+		length := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+		offset += 4
+
+		switch typ {
+		case 0, 4, 6: // STREAMINFO, VORBIS_COMMENT, PICTURE
+			if length > maxOggHeaderSize {
+				return nil, errors.New("FLAC metadata block is too large")
+			}
+			block := make([]byte, length)
+			if _, err := readAt(r, block, offset); err != nil {
+				return nil, errors.Wrap(err, "failed reading FLAC metadata block")
+			}
+			switch typ {
+			case 0:
+				if len(block) >= 18 {
+					sampleRate := int64(block[10])<<12 | int64(block[11])<<4 | int64(block[12])>>4
+					samples := int64(block[13]&0x0F)<<32 | int64(binary.BigEndian.Uint32(block[14:]))
+					if sampleRate > 0 {
+						md.Duration = float64(samples) / float64(sampleRate)
+					}
+				}
+			case 4:
+				parseVorbisComments(md, block)
+			case 6:
+				if picture := flacPicture(block); picture != nil && (md.Cover == nil || binary.BigEndian.Uint32(block) == 3) {
+					md.Cover = picture
+				}
+			}
+		}
+
+		offset += length
+		if last {
+			return md, nil
+		}
+	}
+}
+
+// Parses a FLAC PICTURE block, also used in the METADATA_BLOCK_PICTURE comments.
+func flacPicture(b []byte) *Picture {
+	readBytes := func() []byte {
+		if len(b) < 4 {
+			return nil
+		}
+		n := binary.BigEndian.Uint32(b)
+		b = b[4:]
+		if uint64(n) > uint64(len(b)) {
+			b = nil
+			return nil
+		}
+		v := b[:n]
+		b = b[n:]
+		return v
+	}
+	if len(b) < 4 {
+		return nil
+	}
+	b = b[4:] // Picture type
+	mime := string(readBytes())
+	readBytes() // Description
+	if len(b) < 16 {
+		return nil
+	}
+	b = b[16:] // Width, height, color depth and number of colors
+	data := readBytes()
+	if len(data) == 0 {
+		return nil
+	}
+	if detected := pictureMediaType(data); detected != "" {
+		mime = detected
+	}
+	if !strings.HasPrefix(mime, "image/") {
+		return nil
+	}
+	return &Picture{MediaType: mime, Data: data}
+}
+
+// Parses a Vorbis comment header, without its packet type prefix.
+// https://www.xiph.org/vorbis/doc/v-comment.html
+func parseVorbisComments(md *Metadata, b []byte) {
+	readString := func() (string, bool) {
+		if len(b) < 4 {
+			return "", false
+		}
+		n := binary.LittleEndian.Uint32(b)
+		b = b[4:]
+		if uint64(n) > uint64(len(b)) {
+			return "", false
+		}
+		s := string(b[:n])
+		b = b[n:]
+		return s, true
+	}
+	if _, ok := readString(); !ok { // Vendor
+		return
+	}
+	if len(b) < 4 {
+		return
+	}
+	count := binary.LittleEndian.Uint32(b)
+	b = b[4:]
+
+	// Chapters use the CHAPTERxxx=HH:MM:SS.sss and CHAPTERxxxNAME=title comments.
+	chapters := make(map[int]*Chapter)
+	for i := uint32(0); i < count; i++ {
+		comment, ok := readString()
+		if !ok {
+			break
+		}
+		key, value, ok := strings.Cut(comment, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToUpper(key)
+
+		if rest, ok := strings.CutPrefix(key, "CHAPTER"); ok && len(rest) >= 3 {
+			n, err := strconv.Atoi(rest[:3])
+			if err != nil {
+				continue
+			}
+			if chapters[n] == nil {
+				chapters[n] = &Chapter{Start: -1}
+			}
+			switch rest[3:] {
+			case "":
+				if start, ok := parseTimestamp(value); ok {
+					chapters[n].Start = start
+				}
+			case "NAME":
+				chapters[n].Title = strings.TrimSpace(value)
+			}
+			continue
+		}
+		if key == "METADATA_BLOCK_PICTURE" {
+			if data, err := base64.StdEncoding.DecodeString(value); err == nil {
+				if picture := flacPicture(data); picture != nil && (md.Cover == nil || binary.BigEndian.Uint32(data) == 3) {
+					md.Cover = picture
+				}
+			}
+			continue
+		}
+		setComment(md, key, value)
+	}
+
+	numbers := make([]int, 0, len(chapters))
+	for n, c := range chapters {
+		if c.Start >= 0 {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+	for _, n := range numbers {
+		md.Chapters = append(md.Chapters, *chapters[n])
+	}
+}
+
+// Sets the field of [md] matching a Vorbis comment [key], also used for ID3 TXXX frames.
+func setComment(md *Metadata, key string, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	switch strings.ToUpper(key) {
+	case "TITLE":
+		md.Title = value
+	case "ALBUM":
+		md.Album = value
+	case "ARTIST":
+		md.Artists = appendUnique(md.Artists, value)
+	case "ALBUMARTIST", "ALBUM ARTIST", "ALBUM_ARTIST":
+		md.AlbumArtists = appendUnique(md.AlbumArtists, value)
+	case "COMPOSER":
+		md.Composers = appendUnique(md.Composers, value)
+	case "NARRATOR", "NARRATEDBY", "READER", "PERFORMER":
+		md.Narrators = appendUnique(md.Narrators, value)
+	case "GENRE":
+		md.Genres = appendUnique(md.Genres, value)
+	case "DATE", "YEAR":
+		md.Date = value
+	case "TRACKNUMBER":
+		md.Track = parseIndex(value)
+	case "DISCNUMBER":
+		md.Disc = parseIndex(value)
+	}
+}
+
+// Parses a timestamp such as HH:MM:SS.sss, MM:SS or SS.sss, in seconds.
+func parseTimestamp(v string) (float64, bool) {
+	var seconds float64
+	for _, part := range strings.Split(strings.TrimSpace(v), ":") {
+		f, err := strconv.ParseFloat(part, 64)
+		if err != nil || f < 0 {
+			return 0, false
+		}
+		seconds = seconds*60 + f
+	}
+	return seconds, true
+}
@@ -0,0 +1,145 @@
+package daisy
+
+import (
+	"bytes"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/util/url"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type heading struct {
+	link  manifest.Link
+	level int
+}
+
+// Parses the Navigation Control Center of a DAISY 2.02 book.
+// https://daisy.org/z3986/specifications/daisy_202.html#ncc
+func parseNCC(f fetcher.Fetcher, link manifest.Link) (*book, error) {
+	data, rerr := f.Get(link).Read(0, 0)
+	if rerr != nil {
+		return nil, errors.Wrap(rerr.Cause, "failed reading NCC")
+	}
+	// The NCC is often invalid XHTML, so it's parsed as HTML.
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing NCC")
+	}
+
+	nccURL := link.URL(nil, nil)
+	b := &book{Entry: nccURL}
+	var headings []heading
+	smils := make(map[string]struct{})
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Meta:
+				b.Metadata.Set(htmlAttr(n, "name"), htmlAttr(n, "content"))
+			case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				if l := nccLink(n, nccURL); l != nil {
+					headings = append(headings, heading{link: *l, level: int(n.Data[1] - '0')})
+				}
+			case atom.Span, atom.Div:
+				if strings.HasPrefix(htmlAttr(n, "class"), "page-") {
+					if l := nccLink(n, nccURL); l != nil {
+						b.PageList = append(b.PageList, *l)
+					}
+				}
+			case atom.A:
+				// SMIL files are ordered by their first reference in the NCC.
+				if u, err := url.FromEPUBHref(htmlAttr(n, "href")); err == nil && htmlAttr(n, "href") != "" {
+					u = nccURL.Resolve(u).RemoveFragment()
+					if _, ok := smils[u.Path()]; !ok && strings.EqualFold(path.Ext(u.Path()), ".smil") {
+						smils[u.Path()] = struct{}{}
+						b.SMILs = append(b.SMILs, u)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	b.TOC = nestHeadings(headings)
+	return b, nil
+}
+
+// Creates a link from the first anchor of the NCC element [n].
+func nccLink(n *html.Node, nccURL url.URL) *manifest.Link {
+	var a *html.Node
+	var find func(n *html.Node)
+	find = func(n *html.Node) {
+		for c := n.FirstChild; c != nil && a == nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.DataAtom == atom.A {
+				a = c
+			} else {
+				find(c)
+			}
+		}
+	}
+	find(n)
+	if a == nil {
+		return nil
+	}
+	u, err := url.FromEPUBHref(htmlAttr(a, "href"))
+	if err != nil || htmlAttr(a, "href") == "" {
+		return nil
+	}
+	title := htmlText(n)
+	if title == "" {
+		return nil
+	}
+	return &manifest.Link{
+		Href:  manifest.NewHREF(nccURL.Resolve(u)),
+		Title: title,
+	}
+}
+
+// Nests the flat list of NCC headings according to their level.
+func nestHeadings(headings []heading) manifest.LinkList {
+	var links manifest.LinkList
+	for i := 0; i < len(headings); {
+		j := i + 1
+		for j < len(headings) && headings[j].level > headings[i].level {
+			j++
+		}
+		link := headings[i].link
+		link.Children = nestHeadings(headings[i+1 : j])
+		links = append(links, link)
+		i = j
+	}
+	return links
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if strings.EqualFold(attr.Key, key) {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+	return ""
+}
+
+func htmlText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
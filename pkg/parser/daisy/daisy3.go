@@ -0,0 +1,77 @@
+package daisy
+
+import (
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/parser/epub"
+	"github.com/readium/go-toolkit/pkg/util/url"
+)
+
+const (
+	mediaTypeSMIL = "application/smil"
+	mediaTypeNCX  = "application/x-dtbncx+xml"
+)
+
+// Parses the package document of a DAISY 3 book, returning nil if it's not a DAISY book.
+// https://daisy.org/z3986/2005/Z3986-2005.html#PackageDoc
+func parseDAISY3(f fetcher.Fetcher, link manifest.Link) (*book, error) {
+	opfURL := link.URL(nil, nil)
+	doc, rerr := f.Get(link).ReadAsXML(nil)
+	if rerr != nil {
+		return nil, errors.Wrap(rerr.Cause, "failed reading package document")
+	}
+	pkg := doc.SelectElement("/" + localName("package"))
+	if pkg == nil {
+		return nil, nil
+	}
+
+	type item struct {
+		href      url.URL
+		mediaType string
+	}
+	items := make(map[string]item)
+	var ncx url.URL
+	for _, el := range pkg.SelectElements(localName("manifest") + "/" + localName("item")) {
+		u, err := url.FromEPUBHref(el.SelectAttr("href"))
+		if err != nil || el.SelectAttr("href") == "" {
+			continue
+		}
+		it := item{href: opfURL.Resolve(u), mediaType: el.SelectAttr("media-type")}
+		items[el.SelectAttr("id")] = it
+		if it.mediaType == mediaTypeNCX {
+			ncx = it.href
+		}
+	}
+
+	// Unlike EPUB, the spine of a DAISY book is made of SMIL files.
+	b := &book{Entry: opfURL}
+	for _, el := range pkg.SelectElements(localName("spine") + "/" + localName("itemref")) {
+		if it, ok := items[el.SelectAttr("idref")]; ok && it.mediaType == mediaTypeSMIL {
+			b.SMILs = append(b.SMILs, it.href)
+		}
+	}
+	if len(b.SMILs) == 0 {
+		return nil, nil
+	}
+
+	for _, el := range pkg.SelectElements(".//" + localName("metadata") + "//*") {
+		if el.Data == "meta" {
+			b.Metadata.Set(el.SelectAttr("name"), el.SelectAttr("content"))
+		} else if el.NamespaceURI == epub.NamespaceDC {
+			b.Metadata.Set(el.Data, el.InnerText())
+		}
+	}
+
+	if ncx != nil {
+		n, rerr := f.Get(manifest.Link{Href: manifest.NewHREF(ncx)}).ReadAsXML(map[string]string{
+			epub.NamespaceNCX: "ncx",
+		})
+		if rerr == nil {
+			nav := epub.ParseNCX(n, ncx)
+			b.TOC = nav["toc"]
+			b.PageList = nav["page-list"]
+		}
+	}
+	return b, nil
+}
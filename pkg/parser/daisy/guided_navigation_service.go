@@ -0,0 +1,70 @@
+package daisy
+
+import (
+	"slices"
+
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/pub"
+)
+
+// GuidedNavigationService implements pub.GuidedNavigationService
+// Serves the guided navigation documents generated from the SMIL files of a DAISY book, for each
+// resource of the reading order.
+type GuidedNavigationService struct {
+	documents map[string]manifest.GuidedNavigationDocument
+	hrefs     []string // Resources having a document, in reading order
+}
+
+func (s *GuidedNavigationService) Close() {}
+
+func (s *GuidedNavigationService) Links() manifest.LinkList {
+	return manifest.LinkList{pub.GuidedNavigationLink}
+}
+
+func (s *GuidedNavigationService) HasGuideForResource(href string) bool {
+	_, ok := s.documents[href]
+	return ok
+}
+
+func (s *GuidedNavigationService) GuideForResource(href string) (*manifest.GuidedNavigationDocument, error) {
+	doc, ok := s.documents[href]
+	if !ok {
+		return nil, nil
+	}
+	doc.Links = nil
+
+	// Links to the previous and next guided navigation documents in the reading order
+	idx := slices.Index(s.hrefs, href)
+	if idx > 0 {
+		doc.Links = append(doc.Links, pub.GuidedNavigationRefLink(s.hrefs[idx-1], "prev"))
+	}
+	if idx < len(s.hrefs)-1 {
+		doc.Links = append(doc.Links, pub.GuidedNavigationRefLink(s.hrefs[idx+1], "next"))
+	}
+	return &doc, nil
+}
+
+func (s *GuidedNavigationService) Get(link manifest.Link) (fetcher.Resource, bool) {
+	return pub.GetForGuidedNavigationService(s, link)
+}
+
+// Creates a [GuidedNavigationService] serving the given [documents], indexed by the HREF of their
+// resource in the reading order.
+func GuidedNavigationServiceFactory(documents map[string]manifest.GuidedNavigationDocument) pub.ServiceFactory {
+	return pub.CachedGuidedNavigationServiceFactory(func(context pub.Context) pub.Service {
+		if len(documents) == 0 {
+			return nil
+		}
+		var hrefs []string
+		for _, link := range context.Manifest.ReadingOrder {
+			if _, ok := documents[link.Href.String()]; ok {
+				hrefs = append(hrefs, link.Href.String())
+			}
+		}
+		return &GuidedNavigationService{
+			documents: documents,
+			hrefs:     hrefs,
+		}
+	})
+}
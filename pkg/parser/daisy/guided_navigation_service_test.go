@@ -0,0 +1,31 @@
+package daisy
+
+import (
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuidedNavigationServiceFactoryAfterClose(t *testing.T) {
+	factory := GuidedNavigationServiceFactory(map[string]manifest.GuidedNavigationDocument{
+		"a.mp3": {Guided: []manifest.GuidedNavigationObject{{AudioRef: "a.mp3#t=0,1"}}},
+	})
+	context := pub.Context{Manifest: manifest.Manifest{
+		ReadingOrder: manifest.LinkList{{Href: manifest.MustNewHREFFromString("a.mp3", false)}},
+	}}
+
+	// Closing a service must not affect the ones built afterwards from the same factory.
+	factory(context).Close()
+	service, ok := factory(context).(pub.GuidedNavigationService)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.True(t, service.HasGuideForResource("a.mp3"))
+	doc, err := service.GuideForResource("a.mp3")
+	assert.NoError(t, err)
+	if assert.NotNil(t, doc) {
+		assert.Equal(t, []manifest.GuidedNavigationObject{{AudioRef: "a.mp3#t=0,1"}}, doc.Guided)
+	}
+}
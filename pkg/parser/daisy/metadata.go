@@ -0,0 +1,79 @@
+package daisy
+
+import (
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/parser/epub"
+)
+
+// Multimedia types of the books only made of audio, the text being limited to the navigation.
+// https://daisy.org/z3986/specifications/daisy_202.html#multimediatype
+// https://daisy.org/z3986/2005/Z3986-2005.html#Type
+var audioOnlyMultimediaTypes = []string{"audioonly", "audioncc", "audioncx"}
+
+// Metadata shared by DAISY 2.02 (NCC meta) and DAISY 3 (OPF dc-metadata and x-metadata).
+type metadata struct {
+	manifest.Metadata
+	MultimediaType string // Lowercased, e.g. audiofulltext
+}
+
+// Sets the metadata [name], with or without its dc:, ncc: or dtb: prefix.
+func (m *metadata) Set(name string, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	name = strings.ToLower(name)
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	switch name {
+	case "title":
+		if m.LocalizedTitle.Length() == 0 {
+			m.LocalizedTitle = manifest.NewLocalizedStringFromString(value)
+		}
+	case "creator":
+		m.Authors = append(m.Authors, contributor(value))
+	case "publisher":
+		m.Publishers = append(m.Publishers, contributor(value))
+	case "narrator":
+		m.Narrators = append(m.Narrators, contributor(value))
+	case "contributor":
+		m.Contributors = append(m.Contributors, contributor(value))
+	case "date":
+		if m.Published == nil {
+			m.Published = extensions.ParseDate(value)
+		}
+	case "language":
+		m.Languages = append(m.Languages, value)
+	case "identifier":
+		if m.Identifier == "" {
+			m.Identifier = value
+		}
+	case "subject":
+		m.Subjects = append(m.Subjects, manifest.Subject{
+			LocalizedName: manifest.NewLocalizedStringFromString(value),
+		})
+	case "description":
+		m.Description = value
+	case "totaltime":
+		if d := epub.ParseClockValue(value); d != nil && *d > 0 {
+			m.Duration = d
+		}
+	case "multimediatype":
+		m.MultimediaType = strings.ToLower(value)
+	}
+}
+
+func (m metadata) IsAudioOnly() bool {
+	return extensions.Contains(audioOnlyMultimediaTypes, m.MultimediaType)
+}
+
+func contributor(name string) manifest.Contributor {
+	return manifest.Contributor{
+		LocalizedName: manifest.NewLocalizedStringFromString(name),
+	}
+}
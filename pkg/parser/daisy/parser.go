@@ -0,0 +1,319 @@
+package daisy
+
+import (
+	"math"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/readium/go-toolkit/pkg/util/url"
+)
+
+// DAISY book, before its conversion into a publication.
+type book struct {
+	Entry    url.URL // NCC (DAISY 2.02) or package document (DAISY 3)
+	Metadata metadata
+	SMILs    []url.URL         // SMIL files in reading order
+	TOC      manifest.LinkList // Targeting SMIL fragments
+	PageList manifest.LinkList // Targeting SMIL fragments
+}
+
+// Handles parsing of DAISY 2.02 and DAISY 3 (ANSI/NISO Z39.86) talking books, into an audiobook
+// when the book is only made of audio, or into a publication of its text documents otherwise.
+// In both cases, the synchronization of the SMIL files is served as guided navigation documents.
+type Parser struct{}
+
+func NewParser() Parser {
+	return Parser{}
+}
+
+// Parse implements PublicationParser
+func (p Parser) Parse(asset asset.PublicationAsset, f fetcher.Fetcher) (*pub.Builder, error) {
+	// DAISY books don't have a media type of their own. Archives identified as another kind of
+	// publication, e.g. a CBZ with a stray package document, are left to their own parser.
+	if mt := asset.MediaType(); mt.IsPublication() || mt.Equal(&mediatype.CBR) {
+		return nil, nil
+	}
+
+	links, err := f.Links()
+	if err != nil {
+		return nil, err
+	}
+	b, err := readBook(f, links)
+	if err != nil || b == nil {
+		return nil, err
+	}
+
+	s := newSMIL()
+	for _, u := range b.SMILs {
+		n, rerr := f.Get(manifest.Link{Href: manifest.NewHREF(u)}).ReadAsXML(nil)
+		if rerr != nil {
+			return nil, errors.Wrap(rerr.Cause, "failed reading SMIL file "+u.String())
+		}
+		if err := s.Parse(n, u); err != nil {
+			return nil, errors.Wrap(err, "failed parsing SMIL file "+u.String())
+		}
+	}
+	if len(s.Pars) == 0 {
+		return nil, errors.New("no synchronization point found in the DAISY book")
+	}
+
+	c := converter{book: b, smil: s}
+	c.audiobook = c.isAudiobook()
+	m := c.manifest(links)
+	if m.Metadata.LocalizedTitle.Length() == 0 {
+		m.Metadata.LocalizedTitle = manifest.NewLocalizedStringFromString(asset.Name())
+	}
+
+	services := map[string]pub.ServiceFactory{
+		pub.GuidedNavigationService_Name: GuidedNavigationServiceFactory(c.guidedNavigationDocuments()),
+	}
+	if c.audiobook {
+		services[pub.PositionsService_Name] = pub.AudioPositionsServiceFactory(pub.DefaultAudioPositionDuration)
+	} else {
+		services[pub.PositionsService_Name] = pub.PerResourcePositionsServiceFactory(mediatype.XHTML)
+	}
+	return pub.NewBuilder(m, f, pub.NewServicesBuilder(services)), nil
+}
+
+// Reads the NCC of a DAISY 2.02 book, or the package document of a DAISY 3 book.
+// Returns nil if the [links] don't belong to a DAISY book.
+func readBook(f fetcher.Fetcher, links manifest.LinkList) (*book, error) {
+	var nccs, opfs manifest.LinkList
+	for _, link := range links {
+		p := link.URL(nil, nil).Path()
+		if extensions.IsHiddenOrThumbs(p) {
+			continue
+		}
+		switch name := strings.ToLower(path.Base(p)); {
+		case name == "ncc.html" || name == "ncc.htm":
+			nccs = append(nccs, link)
+		case path.Ext(name) == ".opf":
+			opfs = append(opfs, link)
+		}
+	}
+	shallowestFirst(nccs)
+	shallowestFirst(opfs)
+
+	if len(nccs) > 0 {
+		return parseNCC(f, nccs[0])
+	}
+	for _, opf := range opfs {
+		b, err := parseDAISY3(f, opf)
+		if err != nil || b != nil {
+			return b, err
+		}
+	}
+	return nil, nil
+}
+
+func shallowestFirst(links manifest.LinkList) {
+	sort.SliceStable(links, func(i, j int) bool {
+		return strings.Count(links[i].URL(nil, nil).Path(), "/") < strings.Count(links[j].URL(nil, nil).Path(), "/")
+	})
+}
+
+// Converts a DAISY [book] and its [smil] synchronization points into a publication.
+type converter struct {
+	book      *book
+	smil      *smil
+	audiobook bool
+}
+
+// A book is an audiobook when its multimedia type says so, or when its synchronization points
+// have audio but no text besides the navigation.
+func (c converter) isAudiobook() bool {
+	hasAudio, hasText := false, false
+	for _, p := range c.smil.Pars {
+		hasAudio = hasAudio || len(p.Audio) > 0
+		hasText = hasText || (p.Text != nil && p.Text.Path() != c.book.Entry.Path())
+	}
+	return hasAudio && (c.book.Metadata.IsAudioOnly() || !hasText)
+}
+
+// Returns the reading order resource synchronized by the par [p], or nil.
+func (c converter) resource(p par) url.URL {
+	if c.audiobook {
+		if len(p.Audio) == 0 {
+			return nil
+		}
+		return p.Audio[0].Src.RemoveFragment()
+	}
+	if p.Text == nil || p.Text.Path() == c.book.Entry.Path() {
+		return nil
+	}
+	return p.Text.RemoveFragment()
+}
+
+func (c converter) manifest(links manifest.LinkList) manifest.Manifest {
+	mediaTypes := make(map[string]*mediatype.MediaType)
+	for _, link := range links {
+		mediaTypes[link.URL(nil, nil).Path()] = link.MediaType
+	}
+
+	// Reading order, with the duration of the audio resources computed from their last clip.
+	var readingOrder manifest.LinkList
+	indexes := make(map[string]int)
+	for _, p := range c.smil.Pars {
+		u := c.resource(p)
+		if u == nil {
+			continue
+		}
+		i, ok := indexes[u.Path()]
+		if !ok {
+			i = len(readingOrder)
+			indexes[u.Path()] = i
+			mt := mediaTypes[u.Path()]
+			if mt == nil {
+				mt = mediatype.OfExtension(strings.TrimPrefix(path.Ext(u.Path()), "."))
+			}
+			if mt == nil {
+				mt = &mediatype.Binary
+			}
+			readingOrder = append(readingOrder, manifest.Link{
+				Href:      manifest.NewHREF(u),
+				MediaType: mt,
+			})
+		}
+		if c.audiobook {
+			for _, a := range p.Audio {
+				if a.Src.Path() == u.Path() {
+					readingOrder[i].Duration = math.Max(readingOrder[i].Duration, a.End)
+				}
+			}
+		}
+	}
+	for i := range readingOrder {
+		readingOrder[i].Alternates = manifest.LinkList{pub.GuidedNavigationRefLink(readingOrder[i].Href.String())}
+	}
+
+	var resources manifest.LinkList
+	for _, link := range links {
+		p := link.URL(nil, nil).Path()
+		if _, ok := indexes[p]; !ok && !extensions.IsHiddenOrThumbs(p) {
+			resources = append(resources, link)
+		}
+	}
+
+	metadata := c.book.Metadata.Metadata
+	if c.audiobook {
+		metadata.ConformsTo = manifest.Profiles{manifest.ProfileAudiobook}
+		if metadata.Duration == nil {
+			var total float64
+			for _, link := range readingOrder {
+				total += link.Duration
+			}
+			if total > 0 {
+				metadata.Duration = &total
+			}
+		}
+	}
+
+	m := manifest.Manifest{
+		Context:         manifest.Strings{manifest.WebpubManifestContext},
+		Metadata:        metadata,
+		ReadingOrder:    readingOrder,
+		Resources:       resources,
+		TableOfContents: c.resolveLinks(c.book.TOC),
+		Subcollections:  make(manifest.PublicationCollectionMap),
+	}
+	if pageList := c.resolveLinks(c.book.PageList); len(pageList) > 0 {
+		m.Subcollections["pageList"] = []manifest.PublicationCollection{{Links: pageList}}
+	}
+	return m
+}
+
+// Replaces the SMIL fragments targeted by the navigation [links] with the text fragment or audio
+// clip they synchronize.
+func (c converter) resolveLinks(links manifest.LinkList) manifest.LinkList {
+	res := make(manifest.LinkList, 0, len(links))
+	for _, link := range links {
+		link.Children = c.resolveLinks(link.Children)
+		u := link.URL(nil, nil)
+		if strings.EqualFold(path.Ext(u.Path()), ".smil") {
+			if target := c.target(u); target != nil {
+				link.Href = manifest.NewHREF(target)
+			} else if len(link.Children) > 0 {
+				link.Href = manifest.MustNewHREFFromString("#", false)
+			} else {
+				continue
+			}
+		}
+		res = append(res, link)
+	}
+	return res
+}
+
+func (c converter) target(u url.URL) url.URL {
+	i, ok := c.smil.Targets[smilTarget(u, u.Fragment())]
+	if !ok {
+		return nil
+	}
+	// The targeted par might not be synchronized with a resource, e.g. a heading of the NCC.
+	for ; i < len(c.smil.Pars); i++ {
+		p := c.smil.Pars[i]
+		if c.resource(p) == nil {
+			continue
+		}
+		if c.audiobook {
+			return clip{Src: p.Audio[0].Src, Begin: p.Audio[0].Begin}.URL()
+		}
+		return p.Text
+	}
+	return nil
+}
+
+// Generates the guided navigation documents of each resource of the reading order.
+func (c converter) guidedNavigationDocuments() map[string]manifest.GuidedNavigationDocument {
+	docs := make(map[string]manifest.GuidedNavigationDocument)
+	for _, p := range c.smil.Pars {
+		u := c.resource(p)
+		if u == nil {
+			continue
+		}
+		var o manifest.GuidedNavigationObject
+		if p.Text != nil && p.Text.Path() != c.book.Entry.Path() {
+			o.TextRef = p.Text.String()
+		}
+		clips := mergeClips(p.Audio)
+		if len(clips) == 1 {
+			o.AudioRef = clips[0].URL().String()
+		} else {
+			for _, a := range clips {
+				o.Children = append(o.Children, manifest.GuidedNavigationObject{
+					AudioRef: a.URL().String(),
+				})
+			}
+		}
+
+		href := manifest.NewHREF(u).String()
+		doc := docs[href]
+		doc.Guided = append(doc.Guided, o)
+		docs[href] = doc
+	}
+	return docs
+}
+
+// Merges the consecutive clips of the same audio file, as DAISY 2.02 books often split the
+// narration of a par into several clips.
+func mergeClips(clips []clip) []clip {
+	var merged []clip
+	for _, a := range clips {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if last.Src.Path() == a.Src.Path() && last.End > 0 && math.Abs(last.End-a.Begin) < 0.001 {
+				last.End = a.End
+				continue
+			}
+		}
+		merged = append(merged, a)
+	}
+	return merged
+}
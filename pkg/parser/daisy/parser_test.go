@@ -0,0 +1,160 @@
+package daisy
+
+import (
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/readium/go-toolkit/pkg/util/url"
+	"github.com/stretchr/testify/assert"
+)
+
+func withParser(t *testing.T, filepath string, f func(*pub.Publication)) {
+	a := asset.File(filepath)
+	fet, err := a.CreateFetcher(asset.Dependencies{
+		ArchiveFactory: archive.NewArchiveFactory(),
+	}, "")
+	assert.NoError(t, err)
+	p, err := NewParser().Parse(a, fet)
+	if assert.NoError(t, err) && assert.NotNil(t, p) {
+		f(p.Build())
+	}
+}
+
+func hrefs(links manifest.LinkList) []string {
+	res := make([]string, 0, len(links))
+	for _, l := range links {
+		res = append(res, l.Title+" "+l.Href.String())
+	}
+	return res
+}
+
+func TestDAISY202Metadata(t *testing.T) {
+	withParser(t, "./testdata/daisy202.zip", func(p *pub.Publication) {
+		m := p.Manifest.Metadata
+		assert.Equal(t, manifest.Profiles{manifest.ProfileAudiobook}, m.ConformsTo)
+		assert.Equal(t, "Sample Talking Book", m.Title())
+		assert.Equal(t, "daisy-202-sample", m.Identifier)
+		assert.Equal(t, "Jane Doe", m.Authors[0].Name())
+		assert.Equal(t, "John Reader", m.Narrators[0].Name())
+		assert.Equal(t, "Talking Books Inc.", m.Publishers[0].Name())
+		assert.Equal(t, manifest.Strings{"en"}, m.Languages)
+		assert.Equal(t, "2001-05-04", m.Published.Format("2006-01-02"))
+		if assert.NotNil(t, m.Duration) {
+			assert.Equal(t, 12.0, *m.Duration)
+		}
+	})
+}
+
+func TestDAISY202AudioReadingOrder(t *testing.T) {
+	withParser(t, "./testdata/daisy202.zip", func(p *pub.Publication) {
+		ro := p.Manifest.ReadingOrder
+		if assert.Len(t, ro, 2) {
+			assert.Equal(t, "book/a.mp3", ro[0].Href.String())
+			assert.Equal(t, 2.5, ro[0].Duration)
+			assert.True(t, ro[0].MediaType.IsAudio())
+			assert.Equal(t, "book/b.mp3", ro[1].Href.String())
+			assert.Equal(t, 10.0, ro[1].Duration)
+		}
+		assert.Len(t, p.Positions(), 2)
+	})
+}
+
+func TestDAISY202Navigation(t *testing.T) {
+	withParser(t, "./testdata/daisy202.zip", func(p *pub.Publication) {
+		toc := p.Manifest.TableOfContents
+		assert.Equal(t, []string{
+			"Sample Talking Book book/a.mp3#t=0",
+			"Chapter 1 book/b.mp3#t=0",
+		}, hrefs(toc))
+		if assert.Len(t, toc, 2) {
+			assert.Equal(t, []string{"Section 1.1 book/b.mp3#t=6"}, hrefs(toc[1].Children))
+		}
+		pageList := p.Manifest.Subcollections["pageList"]
+		if assert.Len(t, pageList, 1) {
+			assert.Equal(t, []string{"1 book/b.mp3#t=5.5"}, hrefs(pageList[0].Links))
+		}
+	})
+}
+
+func TestDAISY202GuidedNavigation(t *testing.T) {
+	withParser(t, "./testdata/daisy202.zip", func(p *pub.Publication) {
+		service, ok := p.FindService(pub.GuidedNavigationService_Name).(pub.GuidedNavigationService)
+		if !assert.True(t, ok) {
+			return
+		}
+		doc, err := service.GuideForResource("book/b.mp3")
+		assert.NoError(t, err)
+		if assert.NotNil(t, doc) {
+			assert.Equal(t, []manifest.GuidedNavigationObject{
+				{AudioRef: "book/b.mp3#t=0,5.5"},
+				{AudioRef: "book/b.mp3#t=5.5,6"},
+				{AudioRef: "book/b.mp3#t=6,10"},
+			}, doc.Guided)
+			if assert.Len(t, doc.Links, 1) {
+				assert.Equal(t, manifest.Strings{"prev"}, doc.Links[0].Rels)
+				assert.Equal(t, "~readium/guided-navigation.json?ref=book%2Fa.mp3", doc.Links[0].Href.String())
+			}
+		}
+		assert.False(t, service.HasGuideForResource("book/ncc.html"))
+	})
+}
+
+func TestDAISY3Text(t *testing.T) {
+	withParser(t, "./testdata/daisy3.zip", func(p *pub.Publication) {
+		m := p.Manifest.Metadata
+		assert.Empty(t, m.ConformsTo)
+		assert.Equal(t, "A DAISY 3 Book", m.Title())
+		assert.Equal(t, "daisy-3-sample", m.Identifier)
+		assert.Equal(t, "Richard Roe", m.Authors[0].Name())
+		assert.Equal(t, "Nina Narrator", m.Narrators[0].Name())
+		assert.Equal(t, manifest.Strings{"fr"}, m.Languages)
+
+		ro := p.Manifest.ReadingOrder
+		if assert.Len(t, ro, 1) {
+			assert.Equal(t, "content/book.xml", ro[0].Href.String())
+		}
+		assert.Equal(t, []string{"Chapter One content/book.xml#h1"}, hrefs(p.Manifest.TableOfContents))
+		pageList := p.Manifest.Subcollections["pageList"]
+		if assert.Len(t, pageList, 1) {
+			assert.Equal(t, []string{"1 content/book.xml#page1"}, hrefs(pageList[0].Links))
+		}
+	})
+}
+
+func TestDAISY3GuidedNavigation(t *testing.T) {
+	withParser(t, "./testdata/daisy3.zip", func(p *pub.Publication) {
+		service, ok := p.FindService(pub.GuidedNavigationService_Name).(pub.GuidedNavigationService)
+		if !assert.True(t, ok) {
+			return
+		}
+		doc, err := service.GuideForResource("content/book.xml")
+		assert.NoError(t, err)
+		if assert.NotNil(t, doc) {
+			assert.Equal(t, []manifest.GuidedNavigationObject{
+				{TextRef: "content/book.xml#h1", AudioRef: "audio/book.mp3#t=0,2.5"},
+				{TextRef: "content/book.xml#page1", AudioRef: "audio/book.mp3#t=2.5,4"},
+				{TextRef: "content/book.xml#p2", AudioRef: "audio/book.mp3#t=4,8"},
+			}, doc.Guided)
+			assert.Empty(t, doc.Links)
+		}
+	})
+}
+
+func TestDAISYUntypedReadingOrderResources(t *testing.T) {
+	s := newSMIL()
+	s.Pars = []par{
+		{Text: url.MustURLFromString("content/chapter#p1")},
+		{Text: url.MustURLFromString("content/chapter#p2")},
+	}
+	c := converter{book: &book{Entry: url.MustURLFromString("book.opf")}, smil: s}
+	m := c.manifest(nil)
+	if assert.Len(t, m.ReadingOrder, 1) {
+		assert.Equal(t, "content/chapter", m.ReadingOrder[0].Href.String())
+		assert.Equal(t, &mediatype.Binary, m.ReadingOrder[0].MediaType)
+	}
+	assert.False(t, m.ConformsTo(manifest.ProfileAudiobook))
+}
@@ -0,0 +1,160 @@
+package daisy
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/parser/epub"
+	"github.com/readium/go-toolkit/pkg/util/url"
+	"github.com/readium/xmlquery"
+)
+
+// Synchronization point of a SMIL file, pairing a text fragment with the audio clips narrating it.
+type par struct {
+	Text  url.URL // nil for audio-only synchronization points
+	Audio []clip
+}
+
+// Audio clip of a SMIL par.
+type clip struct {
+	Src   url.URL
+	Begin float64
+	End   float64 // 0 when unknown
+}
+
+// Fragment referencing the clip, e.g. audio.mp3#t=1.2,3.4
+func (c clip) URL() url.URL {
+	fragment := "t=" + strconv.FormatFloat(c.Begin, 'f', -1, 64)
+	if c.End > 0 {
+		fragment += "," + strconv.FormatFloat(c.End, 'f', -1, 64)
+	}
+	u, err := url.URLFromString(c.Src.RemoveFragment().String() + "#" + fragment)
+	if err != nil {
+		return c.Src
+	}
+	return u
+}
+
+// Parsed SMIL files of a DAISY book.
+type smil struct {
+	Pars []par
+	// Index of the par targeted by a SMIL fragment (e.g. chapter1.smil#par_1), which can be the
+	// ID of the par, of one of its children or of a parent seq.
+	Targets map[string]int
+}
+
+func newSMIL() *smil {
+	return &smil{Targets: make(map[string]int)}
+}
+
+// Appends the synchronization points of the SMIL [document] located at [filePath].
+// Both the SMIL 1.0 (DAISY 2.02) and SMIL 2.0 (DAISY 3) flavors are supported.
+func (s *smil) Parse(document *xmlquery.Node, filePath url.URL) error {
+	body := document.SelectElement("//" + localName("body"))
+	if body == nil {
+		return errors.New("SMIL body not found")
+	}
+	var pending []string
+	start := len(s.Pars)
+	s.walk(body, filePath, &pending)
+	if len(s.Pars) > start {
+		s.Targets[smilTarget(filePath, "")] = start
+	}
+	return nil
+}
+
+func (s *smil) walk(n *xmlquery.Node, filePath url.URL, pending *[]string) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != xmlquery.ElementNode {
+			continue
+		}
+		switch child.Data {
+		case "seq":
+			if id := child.SelectAttr("id"); id != "" {
+				*pending = append(*pending, id)
+			}
+			s.walk(child, filePath, pending)
+		case "par", "text", "audio":
+			// Standalone text and audio elements are synchronization points by themselves.
+			p := parseSMILPar(child, filePath)
+			if p.Text == nil && len(p.Audio) == 0 {
+				continue
+			}
+			index := len(s.Pars)
+			s.Pars = append(s.Pars, p)
+			ids := *pending
+			*pending = nil
+			if id := child.SelectAttr("id"); id != "" {
+				ids = append(ids, id)
+			}
+			for _, el := range child.SelectElements(".//*[@id]") {
+				ids = append(ids, el.SelectAttr("id"))
+			}
+			for _, id := range ids {
+				key := smilTarget(filePath, id)
+				if _, ok := s.Targets[key]; !ok {
+					s.Targets[key] = index
+				}
+			}
+		}
+	}
+}
+
+func parseSMILPar(el *xmlquery.Node, filePath url.URL) par {
+	var p par
+	var texts, audios []*xmlquery.Node
+	switch el.Data {
+	case "text":
+		texts = []*xmlquery.Node{el}
+	case "audio":
+		audios = []*xmlquery.Node{el}
+	default:
+		texts = el.SelectElements(".//" + localName("text"))
+		audios = el.SelectElements(".//" + localName("audio"))
+	}
+
+	if len(texts) > 0 {
+		if u, err := url.FromEPUBHref(texts[0].SelectAttr("src")); err == nil && texts[0].SelectAttr("src") != "" {
+			p.Text = filePath.Resolve(u)
+		}
+	}
+	for _, audio := range audios {
+		src := audio.SelectAttr("src")
+		if src == "" {
+			continue
+		}
+		u, err := url.FromEPUBHref(src)
+		if err != nil {
+			continue
+		}
+		c := clip{Src: filePath.Resolve(u)}
+		if begin := parseClipValue(audio, "clipBegin", "clip-begin"); begin != nil {
+			c.Begin = *begin
+		}
+		if end := parseClipValue(audio, "clipEnd", "clip-end"); end != nil {
+			c.End = *end
+		}
+		p.Audio = append(p.Audio, c)
+	}
+	return p
+}
+
+// Parses a clip attribute, named [name] in SMIL 2.0 and [legacyName] in SMIL 1.0 where the values
+// are prefixed with npt=, e.g. clip-begin="npt=1.2s".
+func parseClipValue(el *xmlquery.Node, name string, legacyName string) *float64 {
+	value := el.SelectAttr(name)
+	if value == "" {
+		value = el.SelectAttr(legacyName)
+	}
+	return epub.ParseClockValue(strings.TrimPrefix(strings.TrimSpace(value), "npt="))
+}
+
+// Key of [Targets] for the element [id] of the SMIL file [filePath].
+func smilTarget(filePath url.URL, id string) string {
+	return filePath.RemoveFragment().Path() + "#" + id
+}
+
+func localName(name string) string {
+	return "*[local-name()='" + name + "']"
+}
@@ -264,7 +264,7 @@ func parseItemrefProperties(properties []string) map[string]string {
 		case VocabularyItemref + "page-spread-right":
 			linkProperties["page"] = "right"
 		// Spread
-		case VocabularyRendition + "spread-node":
+		case VocabularyRendition + "spread-none":
 			linkProperties["spread"] = "none"
 		case VocabularyRendition + "spread-auto":
 			linkProperties["spread"] = "auto"
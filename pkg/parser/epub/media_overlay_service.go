@@ -9,8 +9,9 @@ import (
 	"github.com/readium/go-toolkit/pkg/pub"
 )
 
+// Creates a [MediaOverlayService], whose documents parsed from the SMIL files are cached.
 func MediaOverlayFactory() pub.ServiceFactory {
-	return func(context pub.Context) pub.Service {
+	return pub.CachedGuidedNavigationServiceFactory(func(context pub.Context) pub.Service {
 		// Process reading order to find and replace SMIL alternates
 		smilMap := make(map[string]manifest.Link)
 		var smilIndexes []string
@@ -49,14 +50,13 @@ func MediaOverlayFactory() pub.ServiceFactory {
 			originalSmilAlternates: smilMap,
 			originalSmilIndexes:    smilIndexes,
 		}
-	}
+	})
 }
 
 type MediaOverlayService struct {
 	fetcher                fetcher.Fetcher
 	originalSmilAlternates map[string]manifest.Link
 	originalSmilIndexes    []string
-	// TODO: smil parsing cache
 }
 
 func (s *MediaOverlayService) Close() {
@@ -70,7 +70,7 @@ func (p Parser) Parse(asset asset.PublicationAsset, f fetcher.Fetcher) (*pub.Bui
 	builder := pub.NewServicesBuilder(map[string]pub.ServiceFactory{
 		pub.PositionsService_Name: PositionsServiceFactory(p.reflowablePositionsStrategy),
 		pub.ContentService_Name: pub.DefaultContentServiceFactory([]iterator.ResourceContentIteratorFactory{
-			iterator.HTMLFactory(),
+			iterator.HTMLFactoryWithFetcher(ffetcher),
 		}),
 		pub.GuidedNavigationService_Name: MediaOverlayFactory(),
 	})
@@ -108,16 +108,14 @@ func parseNavigationData(packageDocument PackageDocument, fetcher fetcher.Fetche
 				}
 			}
 		}
-		if ncxItem == nil {
-			return
-		}
-		n, nerr := fetcher.Get(manifest.Link{Href: manifest.NewHREF(ncxItem.Href)}).ReadAsXML(map[string]string{
-			NamespaceNCX: "ncx",
-		})
-		if nerr != nil {
-			return
+		if ncxItem != nil {
+			n, nerr := fetcher.Get(manifest.Link{Href: manifest.NewHREF(ncxItem.Href)}).ReadAsXML(map[string]string{
+				NamespaceNCX: "ncx",
+			})
+			if nerr == nil {
+				ret = ParseNCX(n, ncxItem.Href)
+			}
 		}
-		ret = ParseNCX(n, ncxItem.Href)
 	} else {
 		var navItem *Item
 		for _, v := range packageDocument.Manifest {
@@ -131,17 +129,22 @@ func parseNavigationData(packageDocument PackageDocument, fetcher fetcher.Fetche
 				break
 			}
 		}
-		if navItem == nil {
-			return
+		if navItem != nil {
+			n, errx := fetcher.Get(manifest.Link{Href: manifest.NewHREF(navItem.Href)}).ReadAsXML(map[string]string{
+				NamespaceXHTML: "html",
+				NamespaceOPS:   "epub",
+			})
+			if errx == nil {
+				ret = ParseNavDoc(n, navItem.Href)
+			}
 		}
-		n, errx := fetcher.Get(manifest.Link{Href: manifest.NewHREF(navItem.Href)}).ReadAsXML(map[string]string{
-			NamespaceXHTML: "html",
-			NamespaceOPS:   "epub",
-		})
-		if errx != nil {
-			return
+	}
+
+	// The EPUB 2 guide is kept by some EPUB 3 publications for backward compatibility.
+	if _, ok := ret["landmarks"]; !ok {
+		if landmarks := guideLandmarks(packageDocument.Guide); len(landmarks) > 0 {
+			ret["landmarks"] = landmarks
 		}
-		ret = ParseNavDoc(n, navItem.Href)
 	}
 	return
 }
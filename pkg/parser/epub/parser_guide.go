@@ -0,0 +1,83 @@
+package epub
+
+import (
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/util/url"
+	"github.com/readium/xmlquery"
+)
+
+// Reference of the EPUB 2 <guide> element.
+// https://idpf.org/epub/20/spec/OPF_2.0.1_draft.htm#Section2.6
+type GuideReference struct {
+	Href  url.URL
+	Type  string
+	Title string
+}
+
+// Mapping of the EPUB 2 guide reference types to the EPUB 3 structural semantics vocabulary.
+// https://www.w3.org/TR/epub-ssv-11/
+var guideTypes = map[string]string{
+	"acknowledgements": "acknowledgments",
+	"bibliography":     "bibliography",
+	"colophon":         "colophon",
+	"copyright-page":   "copyright-page",
+	"cover":            "cover",
+	"dedication":       "dedication",
+	"epigraph":         "epigraph",
+	"foreword":         "foreword",
+	"glossary":         "glossary",
+	"index":            "index",
+	"loi":              "loi",
+	"lot":              "lot",
+	"notes":            "endnotes",
+	"preface":          "preface",
+	"text":             "bodymatter",
+	"title-page":       "titlepage",
+	"toc":              "toc",
+}
+
+func ParseGuide(element *xmlquery.Node, filePath url.URL) []GuideReference {
+	if element == nil {
+		return nil
+	}
+	selectedElements := element.SelectElements(NSSelect(NamespaceOPF, "reference"))
+	refs := make([]GuideReference, 0, len(selectedElements))
+	for _, el := range selectedElements {
+		rawHref := el.SelectAttr("href")
+		typ := strings.ToLower(strings.TrimSpace(el.SelectAttr("type")))
+		if rawHref == "" || typ == "" {
+			continue
+		}
+		u, err := url.FromEPUBHref(rawHref)
+		if err != nil {
+			continue
+		}
+		refs = append(refs, GuideReference{
+			Href:  filePath.Resolve(u),
+			Type:  typ,
+			Title: strings.TrimSpace(muchSpaceSuchWowMatcher.ReplaceAllString(el.SelectAttr("title"), " ")),
+		})
+	}
+	return refs
+}
+
+// Computes the landmarks of the publication from the guide [refs], typed with the same rels as the
+// landmarks of an EPUB 3 navigation document.
+func guideLandmarks(refs []GuideReference) manifest.LinkList {
+	links := make(manifest.LinkList, 0, len(refs))
+	for _, ref := range refs {
+		// Custom types are prefixed with "other.", e.g. other.afterword
+		typ := strings.TrimPrefix(ref.Type, "other.")
+		if t, ok := guideTypes[typ]; ok {
+			typ = t
+		}
+		links = append(links, manifest.Link{
+			Title: ref.Title,
+			Href:  manifest.NewHREF(ref.Href),
+			Rels:  manifest.Strings{VocabularyType + typ},
+		})
+	}
+	return links
+}
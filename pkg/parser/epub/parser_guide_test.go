@@ -0,0 +1,31 @@
+package epub
+
+import (
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/util/url"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuideMappedToLandmarks(t *testing.T) {
+	n, rerr := fetcher.NewFileResource(manifest.Link{}, "./testdata/package/guide-epub2.opf").ReadAsXML(map[string]string{
+		NamespaceOPF: "opf",
+		NamespaceDC:  "dc",
+	})
+	if !assert.Nil(t, rerr) {
+		return
+	}
+	d, err := ParsePackageDocument(n, url.MustURLFromString("OEBPS/content.opf"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	nav := parseNavigationData(*d, fetcher.EmptyFetcher{})
+	assert.Equal(t, manifest.LinkList{
+		{Title: "Cover", Href: manifest.MustNewHREFFromString("OEBPS/cover.xhtml", false), Rels: manifest.Strings{VocabularyType + "cover"}},
+		{Title: "Beginning", Href: manifest.MustNewHREFFromString("OEBPS/text/chapter01.xhtml#start", false), Rels: manifest.Strings{VocabularyType + "bodymatter"}},
+		{Href: manifest.MustNewHREFFromString("OEBPS/text/afterword.xhtml", false), Rels: manifest.Strings{VocabularyType + "afterword"}},
+	}, nav["landmarks"])
+}
@@ -3,6 +3,7 @@ package epub
 import (
 	"strings"
 
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
 	"github.com/readium/go-toolkit/pkg/manifest"
 	"github.com/readium/go-toolkit/pkg/util/url"
 	"github.com/readium/xmlquery"
@@ -53,21 +54,27 @@ func parseNavElement(nav *xmlquery.Node, filePath url.URL, prefixMap map[string]
 		types = append(types, resolveProperty(prop, prefixMap, DefaultVocabType))
 	}
 
-	links := parseOlElement(nav.SelectElement(NSSelect(NamespaceXHTML, "ol")), filePath)
+	// Landmarks are typed with the epub:type of their links.
+	var relsPrefixMap map[string]string
+	if extensions.Contains(types, VocabularyType+"landmarks") {
+		relsPrefixMap = prefixMap
+	}
+
+	links := parseOlElement(nav.SelectElement(NSSelect(NamespaceXHTML, "ol")), filePath, relsPrefixMap)
 	if len(links) > 0 && len(types) > 0 {
 		return types, links
 	}
 	return nil, nil
 }
 
-func parseOlElement(ol *xmlquery.Node, filePath url.URL) manifest.LinkList {
+func parseOlElement(ol *xmlquery.Node, filePath url.URL, relsPrefixMap map[string]string) manifest.LinkList {
 	if ol == nil {
 		return nil
 	}
 	ols := ol.SelectElements(NSSelect(NamespaceXHTML, "li"))
 	links := make(manifest.LinkList, 0, len(ols))
 	for _, li := range ol.SelectElements(NSSelect(NamespaceXHTML, "li")) {
-		l := parseLiElement(li, filePath)
+		l := parseLiElement(li, filePath, relsPrefixMap)
 		if l != nil {
 			links = append(links, *l)
 		}
@@ -75,7 +82,8 @@ func parseOlElement(ol *xmlquery.Node, filePath url.URL) manifest.LinkList {
 	return links
 }
 
-func parseLiElement(li *xmlquery.Node, filePath url.URL) (link *manifest.Link) {
+// The link rels are resolved from the epub:type of the entries when [relsPrefixMap] is not nil.
+func parseLiElement(li *xmlquery.Node, filePath url.URL, relsPrefixMap map[string]string) (link *manifest.Link) {
 	if li == nil {
 		return nil
 	}
@@ -96,13 +104,23 @@ func parseLiElement(li *xmlquery.Node, filePath url.URL) (link *manifest.Link) {
 		}
 	}
 
-	children := parseOlElement(li.SelectElement(NSSelect(NamespaceXHTML, "ol")), filePath)
+	children := parseOlElement(li.SelectElement(NSSelect(NamespaceXHTML, "ol")), filePath, relsPrefixMap)
 	if len(children) == 0 && (href.String() == "" || title == "") {
 		return nil
 	}
+	var rels manifest.Strings
+	if relsPrefixMap != nil {
+		for _, prop := range parseProperties(SelectNodeAttrNs(first, NamespaceOPS, "type")) {
+			if prop == "" {
+				continue
+			}
+			rels = append(rels, resolveProperty(prop, relsPrefixMap, DefaultVocabType))
+		}
+	}
 	return &manifest.Link{
 		Title:    title,
 		Href:     manifest.NewHREF(href),
+		Rels:     rels,
 		Children: children,
 	}
 }
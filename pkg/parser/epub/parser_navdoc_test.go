@@ -124,3 +124,20 @@ func TestNavDocParserPageList(t *testing.T) {
 		{Title: "2", Href: manifest.MustNewHREFFromString("OEBPS/xhtml/chapter1.xhtml#page2", false)},
 	}, n["page-list"])
 }
+
+func TestNavDocParserLandmarksTyped(t *testing.T) {
+	n, err := loadNavDoc("nav-complex")
+	assert.NoError(t, err)
+	assert.Equal(t, manifest.LinkList{
+		{Title: "Table of Contents", Href: manifest.MustNewHREFFromString("OEBPS/xhtml/nav.xhtml#toc", false), Rels: manifest.Strings{VocabularyType + "toc"}},
+		{Title: "Begin Reading", Href: manifest.MustNewHREFFromString("OEBPS/xhtml/chapter1.xhtml", false), Rels: manifest.Strings{VocabularyType + "bodymatter"}},
+	}, n["landmarks"])
+}
+
+func TestNavDocParserListOfTables(t *testing.T) {
+	n, err := loadNavDoc("nav-complex")
+	assert.NoError(t, err)
+	assert.Equal(t, manifest.LinkList{
+		{Title: "Table 1", Href: manifest.MustNewHREFFromString("OEBPS/xhtml/chapter1.xhtml#table1", false)},
+	}, n["lot"])
+}
@@ -11,6 +11,7 @@ import (
 func ParseNCX(document *xmlquery.Node, filePath url.URL) map[string]manifest.LinkList {
 	toc := document.SelectElement("//" + NSSelect(NamespaceNCX, "navMap"))
 	pageList := document.SelectElement("//" + NSSelect(NamespaceNCX, "pageList"))
+	navLists := document.SelectElements("//" + NSSelect(NamespaceNCX, "navList"))
 
 	ret := make(map[string]manifest.LinkList)
 	if toc != nil {
@@ -20,11 +21,21 @@ func ParseNCX(document *xmlquery.Node, filePath url.URL) map[string]manifest.Lin
 		}
 	}
 	if pageList != nil {
-		p := parsePageListElement(pageList, filePath)
+		p := parseTargets(pageList, "pageTarget", filePath)
 		if len(p) > 0 {
 			ret["page-list"] = p
 		}
 	}
+	for _, navList := range navLists {
+		role := navListRole(navList)
+		if _, ok := ret[role]; ok || role == "" {
+			continue
+		}
+		p := parseTargets(navList, "navTarget", filePath)
+		if len(p) > 0 {
+			ret[role] = p
+		}
+	}
 
 	return ret
 }
@@ -39,8 +50,37 @@ func parseNavMapElement(element *xmlquery.Node, filePath url.URL) manifest.LinkL
 	return links
 }
 
-func parsePageListElement(element *xmlquery.Node, filePath url.URL) manifest.LinkList {
-	selectedElements := element.SelectElements(NSSelect(NamespaceNCX, "pageTarget"))
+// Returns the role of an NCX navList, among the lists of the EPUB 3 navigation document
+// (lot, loi, loa, lov), or "" if unknown.
+// NCX doesn't type the navLists, so their class, id and label are used as hints.
+func navListRole(element *xmlquery.Node) string {
+	hints := []string{element.SelectAttr("class"), element.SelectAttr("id")}
+	for _, hint := range hints {
+		switch hint := strings.ToLower(hint); hint {
+		case "lot", "loi", "loa", "lov":
+			return hint
+		}
+	}
+	hints = append(hints, extractTitle(element))
+	for _, hint := range hints {
+		hint = strings.ToLower(hint)
+		switch {
+		case strings.Contains(hint, "illustration"), strings.Contains(hint, "figure"), strings.Contains(hint, "image"):
+			return "loi"
+		case strings.Contains(hint, "table"):
+			return "lot"
+		case strings.Contains(hint, "audio"):
+			return "loa"
+		case strings.Contains(hint, "video"):
+			return "lov"
+		}
+	}
+	return ""
+}
+
+// Parses the [target] children elements (pageTarget or navTarget) of a pageList or navList.
+func parseTargets(element *xmlquery.Node, target string, filePath url.URL) manifest.LinkList {
+	selectedElements := element.SelectElements(NSSelect(NamespaceNCX, target))
 	links := make([]manifest.Link, 0, len(selectedElements))
 	for _, el := range selectedElements {
 		href := extractHref(el, filePath)
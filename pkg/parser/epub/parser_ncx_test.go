@@ -103,3 +103,12 @@ func TestNCXParserPageList(t *testing.T) {
 		{Title: "2", Href: manifest.MustNewHREFFromString("OEBPS/xhtml/chapter1.xhtml#page2", false)},
 	}, n["page-list"])
 }
+
+func TestNCXParserNavListMappedToRole(t *testing.T) {
+	n, err := loadNcx("ncx-complex")
+	assert.NoError(t, err)
+	assert.Equal(t, manifest.LinkList{
+		{Title: "Portratit of Georg Gisze (Holbein)", Href: manifest.MustNewHREFFromString("OEBPS/content.html#ill1", false)},
+		{Title: "The adoration of the lamb (Van Eyck)", Href: manifest.MustNewHREFFromString("OEBPS/content.html#ill2", false)},
+	}, n["loi"])
+}
@@ -18,6 +18,7 @@ type PackageDocument struct {
 	metadata           EPUBMetadata
 	Manifest           []Item
 	Spine              Spine
+	Guide              []GuideReference
 }
 
 func ParsePackageDocument(document *xmlquery.Node, filePath url.URL) (*PackageDocument, error) {
@@ -76,6 +77,7 @@ func ParsePackageDocument(document *xmlquery.Node, filePath url.URL) (*PackageDo
 		metadata:           *metadata,
 		Manifest:           manifest,
 		Spine:              ParseSpine(spineElement, prefixMap, epubVersion),
+		Guide:              ParseGuide(pkg.SelectElement("/"+NSSelect(NamespaceOPF, "guide")), filePath),
 	}, nil
 
 }
@@ -108,6 +108,11 @@ func TestPackageDocLinkPropertiesPresentation(t *testing.T) {
 	assert.Equal(t, ro[3].Properties.Orientation(), manifest.Orientation(""))
 	assert.Equal(t, ro[3].Properties.Page(), manifest.Page(""))
 	assert.Equal(t, ro[3].Properties.Spread(), manifest.SpreadAuto)
+
+	assert.Equal(t, ro[4].Properties.Spread(), manifest.SpreadBoth)
+	assert.Equal(t, ro[5].Properties.Spread(), manifest.SpreadLandscape)
+	assert.Equal(t, ro[6].Properties.Spread(), manifest.SpreadNone)
+	assert.Equal(t, ro[7].Properties.Spread(), manifest.SpreadBoth)
 }
 
 func TestPackageDocLinkReadingOrder(t *testing.T) {
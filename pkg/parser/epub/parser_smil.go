@@ -62,12 +62,12 @@ func ParseSMILSeq(seq *xmlquery.Node, filePath url.URL) ([]manifest.GuidedNaviga
 			// epub:type
 			pp := parseProperties(SelectNodeAttrNs(el, NamespaceOPS, "type"))
 			if len(pp) > 0 {
-				o.Role = make([]string, 0, len(pp))
+				o.Role = make([]manifest.GuidedNavigationRole, 0, len(pp))
 				for _, prop := range pp {
 					if prop == "" {
 						continue
 					}
-					o.Role = append(o.Role, prop)
+					o.Role = append(o.Role, manifest.GuidedNavigationRole(prop))
 				}
 			}
 
@@ -128,12 +128,12 @@ func ParseSMILPar(par *xmlquery.Node, filePath url.URL) (*manifest.GuidedNavigat
 	// epub:type
 	pp := parseProperties(SelectNodeAttrNs(par, NamespaceOPS, "type"))
 	if len(pp) > 0 {
-		o.Role = make([]string, 0, len(pp))
+		o.Role = make([]manifest.GuidedNavigationRole, 0, len(pp))
 		for _, prop := range pp {
 			if prop == "" {
 				continue
 			}
-			o.Role = append(o.Role, prop)
+			o.Role = append(o.Role, manifest.GuidedNavigationRole(prop))
 		}
 	}
 
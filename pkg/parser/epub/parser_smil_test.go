@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
 	"github.com/readium/go-toolkit/pkg/manifest"
 	"github.com/readium/go-toolkit/pkg/util/url"
 	"github.com/stretchr/testify/assert"
@@ -54,3 +55,27 @@ func TestSMILClipBoundaries(t *testing.T) {
 	assert.Equal(t, "OEBPS/audio/page1.m4a#t=0.84", doc.Guided[1].AudioRef)
 	assert.Equal(t, "OEBPS/audio/page1.m4a", doc.Guided[2].AudioRef)
 }
+
+func TestSMILTypedObjects(t *testing.T) {
+	doc, err := loadSmil("w3-10")
+	if !assert.NoError(t, err) || !assert.Len(t, doc.Guided, 3) {
+		return
+	}
+	table := doc.Guided[1]
+	assert.True(t, table.HasRole(manifest.GuidedNavigationRoleTable))
+	if assert.NotEmpty(t, table.Children) {
+		assert.Equal(t, []manifest.GuidedNavigationRole{manifest.GuidedNavigationRoleTableRow}, table.Children[0].Role)
+	}
+
+	doc, err = loadSmil("audio1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	o := doc.Guided[0]
+	assert.Equal(t, "OEBPS/page1.xhtml", o.TextFile().String())
+	assert.Equal(t, "word0", o.TextFragmentID())
+	assert.Equal(t, "OEBPS/audio/page1.m4a", o.AudioFile().String())
+	clip, err := o.AudioClip()
+	assert.NoError(t, err)
+	assert.Equal(t, &manifest.TemporalFragment{Start: 0, End: extensions.Pointer(0.84)}, clip)
+}
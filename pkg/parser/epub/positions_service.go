@@ -2,7 +2,10 @@ package epub
 
 import (
 	"math"
+	"unicode/utf8"
 
+	"github.com/readium/go-toolkit/pkg/content/element"
+	"github.com/readium/go-toolkit/pkg/content/iterator"
 	"github.com/readium/go-toolkit/pkg/fetcher"
 	"github.com/readium/go-toolkit/pkg/internal/extensions"
 	"github.com/readium/go-toolkit/pkg/manifest"
@@ -132,9 +135,14 @@ func PositionsServiceFactory(reflowableStrategy ReflowableStrategy) pub.ServiceF
 	}
 
 	return func(context pub.Context) pub.Service {
+		// Publications other than EPUB, e.g. Readium Web Publications, might lack presentation hints.
+		presentation := context.Manifest.Metadata.Presentation
+		if presentation == nil {
+			presentation = &manifest.Presentation{}
+		}
 		return &PositionsService{
 			readingOrder:       context.Manifest.ReadingOrder,
-			presentation:       context.Manifest.Metadata.Presentation,
+			presentation:       presentation,
 			fetcher:            context.Fetcher,
 			reflowableStrategy: reflowableStrategy,
 		}
@@ -163,7 +171,7 @@ func (l OriginalLength) PositionCount(resource fetcher.Resource) uint {
 		length, _ = resource.Length()
 	}
 
-	return uint(math.Min(math.Ceil(float64(length)/float64(l.PageLength)), 1))
+	return uint(math.Max(math.Ceil(float64(length)/float64(l.PageLength)), 1))
 }
 
 // Use the archive entry length (whether it is compressed or stored) and split it by the given [PageLength].
@@ -186,6 +194,45 @@ func (l ArchiveEntryLength) PositionCount(resource fetcher.Resource) uint {
 	return uint(math.Max(math.Ceil(float64(length)/float64(l.PageLength)), 1))
 }
 
+// Use the number of visible characters of each resource, as extracted by the HTML content
+// iterator, and split it by the given [PageLength].
+//
+// Unlike the length strategies, the positions don't depend on the size of the markup or on the
+// compression of the resources. Resources which are not HTML fall back on the
+// [RecommendedReflowableStrategy].
+type VisibleTextLength struct {
+	PageLength int
+}
+
+// PositionCount implements ReflowableStrategy
+func (l VisibleTextLength) PositionCount(resource fetcher.Resource) uint {
+	link := resource.Link()
+	mt := link.MediaType
+	if mt == nil {
+		mt = &mediatype.HTML
+	}
+	if !mt.Matches(&mediatype.HTML, &mediatype.XHTML) {
+		return RecommendedReflowableStrategy.PositionCount(resource)
+	}
+
+	it := iterator.NewHTML(resource, manifest.Locator{
+		Href:      link.URL(nil, nil),
+		MediaType: *mt,
+	})
+	var length int
+	for {
+		hasNext, err := it.HasNext()
+		if err != nil || !hasNext {
+			break
+		}
+		if text, ok := it.Next().(element.TextElement); ok {
+			length += utf8.RuneCountInString(text.Text())
+		}
+	}
+
+	return uint(math.Max(math.Ceil(float64(length)/float64(l.PageLength)), 1))
+}
+
 // Recommended historical strategy: archive entry length split by 1024 bytes pages.
 //
 // This strategy is used by Adobe RMSDK as well.
@@ -1,8 +1,12 @@
 package epub
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -11,6 +15,43 @@ func TestEPUBPositionsServiceEmptyReadingOrder(t *testing.T) {
 	assert.Equal(t, 0, len(service.Positions()))
 }
 
+func TestOriginalLengthPositionCount(t *testing.T) {
+	strategy := OriginalLength{PageLength: 50}
+	resource := func(data string) fetcher.Resource {
+		return fetcher.NewBytesResource(manifest.Link{Href: manifest.MustNewHREFFromString("res.html", false)}, func() []byte {
+			return []byte(data)
+		})
+	}
+	assert.Equal(t, uint(1), strategy.PositionCount(resource("")))
+	assert.Equal(t, uint(1), strategy.PositionCount(resource(strings.Repeat("a", 50))))
+	assert.Equal(t, uint(3), strategy.PositionCount(resource(strings.Repeat("a", 101))))
+}
+
+func TestVisibleTextLengthPositionCount(t *testing.T) {
+	strategy := VisibleTextLength{PageLength: 10}
+	resource := func(mt mediatype.MediaType, data string) fetcher.Resource {
+		return fetcher.NewBytesResource(manifest.Link{
+			Href:      manifest.MustNewHREFFromString("res.html", false),
+			MediaType: &mt,
+		}, func() []byte {
+			return []byte(data)
+		})
+	}
+
+	// Only the text content is counted, regardless of the markup.
+	html := `<html><head><title>Ignored title</title><style>p { color: red; }</style></head><body>` +
+		`<p class="a-very-long-class-name">Héllo wörld</p><img src="a.png" alt="ignored"/><p>0123456789</p></body></html>`
+	assert.Equal(t, uint(3), strategy.PositionCount(resource(mediatype.HTML, html)))
+	assert.Equal(t, uint(1), strategy.PositionCount(resource(mediatype.HTML, "<html><body></body></html>")))
+
+	// Resources without a media type are handled as HTML.
+	assert.Equal(t, uint(3), strategy.PositionCount(fetcher.NewBytesResource(manifest.Link{
+		Href: manifest.MustNewHREFFromString("res", false),
+	}, func() []byte {
+		return []byte(html)
+	})))
+}
+
 // TODO replicate `createService` tester from Kotlin
 
 /*func TestEPUBPositionsServiceSingleReadingOrder(t *testing.T) {
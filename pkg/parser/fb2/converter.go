@@ -0,0 +1,260 @@
+package fb2
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/util/url"
+	"github.com/readium/xmlquery"
+)
+
+// Generated XHTML document of the reading order, rendering a part of a FictionBook <body>.
+type document struct {
+	Href  string           // e.g. text/section-001.xhtml
+	Body  *xmlquery.Node   // Body containing the rendered elements
+	Nodes []*xmlquery.Node // Rendered elements
+	Notes bool             // Whether the document renders a whole body of notes
+}
+
+// Image embedded in a FictionBook as a base64 <binary> element.
+type binary struct {
+	Href      string // e.g. images/cover.jpg
+	MediaType *mediatype.MediaType
+	Data      string
+}
+
+// Converts a FictionBook into XHTML documents and images.
+type converter struct {
+	documents []*document
+	binaries  map[string]*binary // Indexed by ID
+	order     []string           // Binary IDs in document order
+	ids       map[string]*document
+	anchors   map[*xmlquery.Node]string // Generated IDs of the sections lacking one
+	lang      string
+}
+
+func newConverter(root *xmlquery.Node, lang string) *converter {
+	c := &converter{
+		binaries: make(map[string]*binary),
+		ids:      make(map[string]*document),
+		anchors:  make(map[*xmlquery.Node]string),
+		lang:     lang,
+	}
+	bodies := 0
+	for _, el := range children(root) {
+		switch el.Data {
+		case "body":
+			// The first body holds the main text, the following ones usually the notes.
+			c.addBody(el, bodies == 0 || el.SelectAttr("name") == "")
+			bodies++
+		case "binary":
+			c.addBinary(el)
+		}
+	}
+	c.indexIDs()
+	return c
+}
+
+// Adds the [body] to the reading order, split into one document per top-level section when [split]
+// is true.
+func (c *converter) addBody(body *xmlquery.Node, split bool) {
+	if !split {
+		c.addDocument(&document{Body: body, Nodes: children(body), Notes: true})
+		return
+	}
+	var nodes []*xmlquery.Node
+	for _, el := range children(body) {
+		if el.Data != "section" {
+			nodes = append(nodes, el)
+			continue
+		}
+		if len(nodes) > 0 {
+			c.addDocument(&document{Body: body, Nodes: nodes})
+			nodes = nil
+		}
+		c.addDocument(&document{Body: body, Nodes: []*xmlquery.Node{el}})
+	}
+	if len(nodes) > 0 {
+		c.addDocument(&document{Body: body, Nodes: nodes})
+	}
+}
+
+func (c *converter) addDocument(doc *document) {
+	doc.Href = fmt.Sprintf("text/section-%03d.xhtml", len(c.documents)+1)
+	c.documents = append(c.documents, doc)
+}
+
+func (c *converter) addBinary(el *xmlquery.Node) {
+	id := strings.TrimSpace(el.SelectAttr("id"))
+	if id == "" {
+		return
+	}
+	mt := mediatype.OfString(el.SelectAttr("content-type"))
+	if mt == nil {
+		mt = mediatype.OfExtension(strings.TrimPrefix(path.Ext(id), "."))
+	}
+	if mt == nil {
+		mt = &mediatype.Binary
+	}
+	if _, ok := c.binaries[id]; !ok {
+		c.order = append(c.order, id)
+	}
+	c.binaries[id] = &binary{
+		Href:      "images/" + id,
+		MediaType: mt,
+		Data:      el.InnerText(),
+	}
+}
+
+// Indexes the document of each element ID, to resolve the internal links, and generates an ID for
+// the sections lacking one.
+func (c *converter) indexIDs() {
+	var sections []*xmlquery.Node
+	for _, doc := range c.documents {
+		for _, n := range doc.Nodes {
+			for _, el := range append([]*xmlquery.Node{n}, n.SelectElements(".//*")...) {
+				if id := el.SelectAttr("id"); id != "" {
+					if _, ok := c.ids[id]; !ok {
+						c.ids[id] = doc
+					}
+				} else if el.Data == "section" {
+					sections = append(sections, el)
+				}
+			}
+		}
+	}
+	i := 0
+	for _, section := range sections {
+		var id string
+		for {
+			i++
+			id = fmt.Sprintf("section-%d", i)
+			if _, ok := c.ids[id]; !ok {
+				break
+			}
+		}
+		c.anchors[section] = id
+	}
+}
+
+// ID of the element [el] in its generated document.
+func (c *converter) id(el *xmlquery.Node) string {
+	if id := el.SelectAttr("id"); id != "" {
+		return id
+	}
+	return c.anchors[el]
+}
+
+// Resolves an internal FB2 link (#id) relatively to the document [from].
+func (c *converter) resolve(from *document, href string) string {
+	if !strings.HasPrefix(href, "#") {
+		return href
+	}
+	id := href[1:]
+	if doc, ok := c.ids[id]; ok && doc != from {
+		return relativeHref(path.Base(doc.Href), id)
+	}
+	return relativeHref("", id)
+}
+
+func (c *converter) readingOrder() manifest.LinkList {
+	links := make(manifest.LinkList, 0, len(c.documents))
+	for _, doc := range c.documents {
+		links = append(links, manifest.Link{
+			Href:      manifest.NewHREF(pathURL(doc.Href)),
+			MediaType: &mediatype.XHTML,
+		})
+	}
+	return links
+}
+
+func (c *converter) resources(coverID string) manifest.LinkList {
+	links := make(manifest.LinkList, 0, len(c.order))
+	for _, id := range c.order {
+		b := c.binaries[id]
+		link := manifest.Link{
+			Href:      manifest.NewHREF(pathURL(b.Href)),
+			MediaType: b.MediaType,
+		}
+		if id == coverID {
+			link.Rels = manifest.Strings{"cover"}
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// Builds the table of contents from the titles of the sections.
+func (c *converter) tableOfContents() manifest.LinkList {
+	var toc manifest.LinkList
+	for _, doc := range c.documents {
+		if !doc.Notes && len(doc.Nodes) == 1 && doc.Nodes[0].Data == "section" {
+			toc = append(toc, c.sectionLinks(doc, doc.Nodes[0], true)...)
+			continue
+		}
+		if title := c.title(doc); title != "" {
+			toc = append(toc, manifest.Link{
+				Href:  manifest.NewHREF(pathURL(doc.Href)),
+				Title: title,
+			})
+		}
+	}
+	return toc
+}
+
+// Links of the titled [section], or of its titled subsections otherwise.
+func (c *converter) sectionLinks(doc *document, section *xmlquery.Node, top bool) manifest.LinkList {
+	var subsections manifest.LinkList
+	for _, el := range children(section) {
+		if el.Data == "section" {
+			subsections = append(subsections, c.sectionLinks(doc, el, false)...)
+		}
+	}
+	title := titleText(child(section, "title"))
+	if title == "" {
+		return subsections
+	}
+	href := doc.Href
+	if !top {
+		href += relativeHref("", c.id(section))
+	}
+	u, err := url.URLFromString(href)
+	if err != nil {
+		return subsections
+	}
+	return manifest.LinkList{{
+		Href:     manifest.NewHREF(u),
+		Title:    title,
+		Children: subsections,
+	}}
+}
+
+// Title of a document which is not a single section, taken from its body.
+func (c *converter) title(doc *document) string {
+	for _, n := range doc.Nodes {
+		if n.Data == "title" {
+			return titleText(n)
+		}
+	}
+	if doc.Notes {
+		return strings.TrimSpace(doc.Body.SelectAttr("name"))
+	}
+	return ""
+}
+
+// Text of a <title>, made of several paragraphs.
+func titleText(title *xmlquery.Node) string {
+	if title == nil {
+		return ""
+	}
+	var lines []string
+	for _, p := range children(title) {
+		if t := text(p); t != "" {
+			lines = append(lines, t)
+		}
+	}
+	return strings.Join(lines, " ")
+}
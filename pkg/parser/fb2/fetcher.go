@@ -0,0 +1,31 @@
+package fb2
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/fetcher"
+)
+
+// Creates a fetcher serving the XHTML documents and images generated from a FictionBook, in place
+// of the original file.
+func newBookFetcher(c *converter) *fetcher.BytesFetcher {
+	f := fetcher.NewBytesFetcher()
+	for i, link := range c.readingOrder() {
+		doc := c.documents[i]
+		f.Add(link, func() []byte {
+			return c.render(doc)
+		})
+	}
+	for i, link := range c.resources("") {
+		b := c.binaries[c.order[i]]
+		f.Add(link, func() []byte {
+			data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(b.Data), ""))
+			if err != nil {
+				return nil
+			}
+			return data
+		})
+	}
+	return f
+}
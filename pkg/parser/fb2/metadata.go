@@ -0,0 +1,159 @@
+package fb2
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/xmlquery"
+)
+
+// Parses the <description> of a FictionBook into the publication metadata.
+// Returns the ID of the cover <binary> as well, if any.
+// http://www.fictionbook.org/index.php/Eng:Element_description
+func parseDescription(description *xmlquery.Node) (metadata manifest.Metadata, coverID string) {
+	if description == nil {
+		return
+	}
+
+	if info := child(description, "title-info"); info != nil {
+		for _, el := range children(info) {
+			switch el.Data {
+			case "book-title":
+				if title := text(el); title != "" {
+					metadata.LocalizedTitle = manifest.NewLocalizedStringFromString(title)
+				}
+			case "author":
+				if c := parsePerson(el); c != nil {
+					metadata.Authors = append(metadata.Authors, *c)
+				}
+			case "translator":
+				if c := parsePerson(el); c != nil {
+					metadata.Translators = append(metadata.Translators, *c)
+				}
+			case "genre":
+				metadata.Subjects = appendSubject(metadata.Subjects, text(el))
+			case "keywords":
+				for _, keyword := range strings.Split(text(el), ",") {
+					metadata.Subjects = appendSubject(metadata.Subjects, keyword)
+				}
+			case "lang":
+				if lang := text(el); lang != "" {
+					metadata.Languages = manifest.Strings{lang}
+				}
+			case "date":
+				metadata.Published = parseDate(el)
+			case "annotation":
+				var paragraphs []string
+				for _, p := range children(el) {
+					if t := text(p); t != "" {
+						paragraphs = append(paragraphs, t)
+					}
+				}
+				metadata.Description = strings.Join(paragraphs, "\n")
+			case "sequence":
+				addSeries(&metadata, el)
+			case "coverpage":
+				if image := child(el, "image"); image != nil {
+					coverID = strings.TrimPrefix(xlinkHref(image), "#")
+				}
+			}
+		}
+	}
+
+	if info := child(description, "publish-info"); info != nil {
+		if publisher := text(child(info, "publisher")); publisher != "" {
+			metadata.Publishers = manifest.Contributors{{
+				LocalizedName: manifest.NewLocalizedStringFromString(publisher),
+			}}
+		}
+		if metadata.Published == nil {
+			metadata.Published = extensions.ParseDate(text(child(info, "year")))
+		}
+		if isbn := strings.ReplaceAll(text(child(info, "isbn")), "-", ""); isbn != "" {
+			metadata.Identifier = "urn:isbn:" + strings.ReplaceAll(isbn, " ", "")
+		}
+		if len(metadata.BelongsToSeries()) == 0 {
+			for _, el := range children(info) {
+				if el.Data == "sequence" {
+					addSeries(&metadata, el)
+				}
+			}
+		}
+	}
+
+	if info := child(description, "document-info"); info != nil && metadata.Identifier == "" {
+		metadata.Identifier = text(child(info, "id"))
+	}
+	return
+}
+
+// Parses an author or translator of the book.
+func parsePerson(el *xmlquery.Node) *manifest.Contributor {
+	var names []string
+	for _, part := range []string{"first-name", "middle-name", "last-name"} {
+		if name := text(child(el, part)); name != "" {
+			names = append(names, name)
+		}
+	}
+	name := strings.Join(names, " ")
+	if name == "" {
+		name = text(child(el, "nickname"))
+	}
+	if name == "" {
+		return nil
+	}
+
+	c := &manifest.Contributor{
+		LocalizedName: manifest.NewLocalizedStringFromString(name),
+		Identifier:    text(child(el, "id")),
+	}
+	if last := text(child(el, "last-name")); last != "" && len(names) > 1 {
+		sortAs := manifest.NewLocalizedStringFromString(last + ", " + strings.Join(names[:len(names)-1], " "))
+		c.LocalizedSortAs = &sortAs
+	}
+	return c
+}
+
+func appendSubject(subjects []manifest.Subject, name string) []manifest.Subject {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return subjects
+	}
+	return append(subjects, manifest.Subject{
+		LocalizedName: manifest.NewLocalizedStringFromString(name),
+	})
+}
+
+// Adds a <sequence name="" number=""> element to the series the book belongs to, along with its
+// nested sequences.
+func addSeries(metadata *manifest.Metadata, el *xmlquery.Node) {
+	name := strings.TrimSpace(el.SelectAttr("name"))
+	if name != "" {
+		series := manifest.Collection{
+			LocalizedName: manifest.NewLocalizedStringFromString(name),
+		}
+		if number, err := strconv.ParseFloat(strings.TrimSpace(el.SelectAttr("number")), 64); err == nil {
+			series.Position = &number
+		}
+		if metadata.BelongsTo == nil {
+			metadata.BelongsTo = make(map[string]manifest.Collections)
+		}
+		metadata.BelongsTo["series"] = append(metadata.BelongsTo["series"], series)
+	}
+	for _, nested := range children(el) {
+		if nested.Data == "sequence" {
+			addSeries(metadata, nested)
+		}
+	}
+}
+
+// The machine-readable value of a <date> is optional, the content being free text.
+func parseDate(el *xmlquery.Node) *time.Time {
+	if date := extensions.ParseDate(strings.TrimSpace(el.SelectAttr("value"))); date != nil {
+		return date
+	}
+	return extensions.ParseDate(text(el))
+}
@@ -0,0 +1,98 @@
+package fb2
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/parser/epub"
+	"github.com/readium/go-toolkit/pkg/pub"
+)
+
+// Handles parsing of FictionBook (FB2) books, bare or zipped.
+// The body of the book is split into XHTML documents, one per top-level section, which are served
+// along with the embedded images by a fetcher replacing the original one.
+type Parser struct{}
+
+func NewParser() Parser {
+	return Parser{}
+}
+
+// Parse implements PublicationParser
+func (p Parser) Parse(asset asset.PublicationAsset, f fetcher.Fetcher) (*pub.Builder, error) {
+	mt := asset.MediaType()
+	if !mt.Matches(&mediatype.FB2, &mediatype.FB2ZIP) {
+		return nil, nil
+	}
+
+	links, err := f.Links()
+	if err != nil {
+		return nil, err
+	}
+	link := findFB2(links)
+	if link == nil {
+		return nil, errors.New("no FictionBook file found in the publication")
+	}
+	doc, rerr := f.Get(*link).ReadAsXML(nil)
+	if rerr != nil {
+		return nil, errors.Wrap(rerr.Cause, "failed reading FictionBook")
+	}
+	root := child(doc, "FictionBook")
+	if root == nil {
+		return nil, errors.New("FictionBook root element not found")
+	}
+
+	metadata, coverID := parseDescription(child(root, "description"))
+	if metadata.LocalizedTitle.Length() == 0 {
+		metadata.LocalizedTitle = manifest.NewLocalizedStringFromString(strings.TrimSuffix(asset.Name(), path.Ext(asset.Name())))
+	}
+	layout := manifest.EPUBLayoutReflowable
+	metadata.Presentation = &manifest.Presentation{Layout: &layout}
+	var lang string
+	if len(metadata.Languages) > 0 {
+		lang = metadata.Languages[0]
+	}
+
+	c := newConverter(root, lang)
+	if len(c.documents) == 0 {
+		return nil, errors.New("FictionBook has no body")
+	}
+	m := manifest.Manifest{
+		Context:         manifest.Strings{manifest.WebpubManifestContext},
+		Metadata:        metadata,
+		ReadingOrder:    c.readingOrder(),
+		Resources:       c.resources(coverID),
+		TableOfContents: c.tableOfContents(),
+	}
+
+	builder := pub.NewServicesBuilder(map[string]pub.ServiceFactory{
+		pub.PositionsService_Name: epub.PositionsServiceFactory(epub.OriginalLength{PageLength: 1024}),
+	})
+	bf := newBookFetcher(c)
+	f.Close() // The whole book is in memory from now on.
+	return pub.NewBuilder(m, bf, builder), nil
+}
+
+// Finds the FB2 file of the publication, which might be the only file of a ZIP archive.
+func findFB2(links manifest.LinkList) *manifest.Link {
+	var files manifest.LinkList
+	for _, link := range links {
+		p := link.URL(nil, nil).Path()
+		if extensions.IsHiddenOrThumbs(p) {
+			continue
+		}
+		if strings.EqualFold(path.Ext(p), ".fb2") {
+			return &link
+		}
+		files = append(files, link)
+	}
+	if len(files) == 1 {
+		return &files[0]
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package fb2
+
+import (
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/stretchr/testify/assert"
+)
+
+func withParser(t *testing.T, filepath string, f func(*pub.Publication)) {
+	a := asset.File(filepath)
+	fet, err := a.CreateFetcher(asset.Dependencies{
+		ArchiveFactory: archive.NewArchiveFactory(),
+	}, "")
+	assert.NoError(t, err)
+	p, err := NewParser().Parse(a, fet)
+	if assert.NoError(t, err) && assert.NotNil(t, p) {
+		f(p.Build())
+	}
+}
+
+func hrefs(links manifest.LinkList) []string {
+	res := make([]string, 0, len(links))
+	for _, l := range links {
+		res = append(res, l.Title+" "+l.Href.String())
+	}
+	return res
+}
+
+func readString(t *testing.T, p *pub.Publication, href string) string {
+	data, err := p.Get(manifest.Link{Href: manifest.MustNewHREFFromString(href, false)}).Read(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestFB2Metadata(t *testing.T) {
+	withParser(t, "./testdata/book.fb2", func(p *pub.Publication) {
+		m := p.Manifest.Metadata
+		assert.Equal(t, "The Test Book", m.Title())
+		assert.Equal(t, "urn:isbn:9783161484100", m.Identifier)
+		if assert.Len(t, m.Authors, 2) {
+			assert.Equal(t, "Ivan S. Petrov", m.Authors[0].Name())
+			assert.Equal(t, "Petrov, Ivan S.", m.Authors[0].SortAs())
+			assert.Equal(t, "author-1", m.Authors[0].Identifier)
+			assert.Equal(t, "Anonymous", m.Authors[1].Name())
+		}
+		assert.Equal(t, "Anna Smith", m.Translators[0].Name())
+		assert.Equal(t, "Test Publisher", m.Publishers[0].Name())
+		assert.Equal(t, manifest.Strings{"en"}, m.Languages)
+		assert.Equal(t, "2001-05-12", m.Published.Format("2006-01-02"))
+		assert.Equal(t, "First paragraph.\nSecond paragraph.", m.Description)
+		var subjects []string
+		for _, s := range m.Subjects {
+			subjects = append(subjects, s.Name())
+		}
+		assert.Equal(t, []string{"sf", "adventure", "space", "travel"}, subjects)
+		if series := m.BelongsToSeries(); assert.Len(t, series, 1) {
+			assert.Equal(t, "Test Series", series[0].Name())
+			assert.Equal(t, 2.0, *series[0].Position)
+		}
+	})
+}
+
+func TestFB2ReadingOrderSplitBySection(t *testing.T) {
+	withParser(t, "./testdata/book.fb2", func(p *pub.Publication) {
+		assert.Equal(t, []string{
+			" text/section-001.xhtml",
+			" text/section-002.xhtml",
+			" text/section-003.xhtml",
+			" text/section-004.xhtml",
+		}, hrefs(p.Manifest.ReadingOrder))
+		for _, link := range p.Manifest.ReadingOrder {
+			assert.Equal(t, &mediatype.XHTML, link.MediaType)
+			_, err := p.Get(link).ReadAsXML(nil)
+			assert.Nil(t, err, "%s is not well-formed", link.Href)
+		}
+
+		intro := readString(t, p, "text/section-001.xhtml")
+		assert.Contains(t, intro, `<html xmlns="http://www.w3.org/1999/xhtml" lang="en" xml:lang="en">`)
+		assert.Contains(t, intro, "<h1>The Test Book<br/>A Novel</h1>")
+		assert.Contains(t, intro, `<blockquote class="epigraph">`)
+		assert.Contains(t, intro, "An epigraph &amp; more.")
+
+		chapter := readString(t, p, "text/section-002.xhtml")
+		assert.Contains(t, chapter, "<title>Chapter 1</title>")
+		assert.Contains(t, chapter, `<section id="ch1">`)
+		assert.Contains(t, chapter, "<h1>Chapter 1</h1>")
+		assert.Contains(t, chapter, `<section id="section-1">`)
+		assert.Contains(t, chapter, "<h2>Part 1.1</h2>")
+		assert.Contains(t, chapter, "<em>time</em>")
+		assert.Contains(t, chapter, `<a href="section-004.xhtml#n1" role="doc-noteref">1</a>`)
+		assert.Contains(t, chapter, `<a href="section-003.xhtml#ch2">chapter 2</a>`)
+		assert.Contains(t, chapter, `<img src="../images/pic.png" alt=""/>`)
+		assert.Contains(t, chapter, `<p class="v">Line one</p>`)
+
+		// The notes body is kept in a single document.
+		notes := readString(t, p, "text/section-004.xhtml")
+		assert.Contains(t, notes, `<section id="n1">`)
+		assert.Contains(t, notes, "A note.")
+	})
+}
+
+func TestFB2BinariesAsResources(t *testing.T) {
+	withParser(t, "./testdata/book.fb2", func(p *pub.Publication) {
+		res := p.Manifest.Resources
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, "images/cover.png", res[0].Href.String())
+			assert.Equal(t, manifest.Strings{"cover"}, res[0].Rels)
+			assert.Equal(t, &mediatype.PNG, res[0].MediaType)
+			assert.Equal(t, "images/pic.png", res[1].Href.String())
+		}
+
+		// Line breaks in the base64 content are ignored.
+		cover := readString(t, p, "images/cover.png")
+		pic := readString(t, p, "images/pic.png")
+		assert.Equal(t, "\x89PNG", cover[:4])
+		assert.Equal(t, cover, pic)
+	})
+}
+
+func TestFB2TableOfContents(t *testing.T) {
+	withParser(t, "./testdata/book.fb2", func(p *pub.Publication) {
+		toc := p.Manifest.TableOfContents
+		assert.Equal(t, []string{
+			"The Test Book A Novel text/section-001.xhtml",
+			"Chapter 1 text/section-002.xhtml",
+			"Chapter 2 text/section-003.xhtml",
+			"Notes text/section-004.xhtml",
+		}, hrefs(toc))
+		assert.Equal(t, []string{"Part 1.1 text/section-002.xhtml#section-1"}, hrefs(toc[1].Children))
+	})
+}
+
+func TestFB2Zipped(t *testing.T) {
+	withParser(t, "./testdata/book.fb2.zip", func(p *pub.Publication) {
+		assert.Equal(t, "The Test Book", p.Manifest.Metadata.Title())
+		assert.Len(t, p.Manifest.ReadingOrder, 4)
+		assert.Len(t, p.Positions(), 4)
+	})
+}
+
+func TestFB2LegacyEncoding(t *testing.T) {
+	withParser(t, "./testdata/cp1251.fb2", func(p *pub.Publication) {
+		assert.Equal(t, "Война и мир", p.Manifest.Metadata.Title())
+		assert.Equal(t, "Лев Толстой", p.Manifest.Metadata.Authors[0].Name())
+		assert.Contains(t, readString(t, p, "text/section-001.xhtml"), "<p>Текст.</p>")
+	})
+}
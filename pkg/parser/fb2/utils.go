@@ -0,0 +1,66 @@
+package fb2
+
+import (
+	gurl "net/url"
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/util/url"
+	"github.com/readium/xmlquery"
+)
+
+// Returns the first child element of [n] with the given local [name], or nil.
+func child(n *xmlquery.Node, name string) *xmlquery.Node {
+	if n == nil {
+		return nil
+	}
+	for _, el := range children(n) {
+		if el.Data == name {
+			return el
+		}
+	}
+	return nil
+}
+
+// Returns the child elements of [n].
+func children(n *xmlquery.Node) []*xmlquery.Node {
+	var els []*xmlquery.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == xmlquery.ElementNode {
+			els = append(els, c)
+		}
+	}
+	return els
+}
+
+// Returns the text of [n] with collapsed whitespace.
+func text(n *xmlquery.Node) string {
+	if n == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(n.InnerText()), " ")
+}
+
+// The XLink namespace prefix varies between FB2 files, e.g. l:href or xlink:href.
+func xlinkHref(n *xmlquery.Node) string {
+	for _, attr := range n.Attr {
+		if attr.Name.Local == "href" {
+			return strings.TrimSpace(attr.Value)
+		}
+	}
+	return ""
+}
+
+// Returns the URL of the decoded [path].
+func pathURL(path string) url.URL {
+	u, err := url.URLFromString((&gurl.URL{Path: path}).String())
+	if err != nil {
+		panic(err) // Escaped paths are always valid
+	}
+	return u
+}
+
+// Returns the escaped HREF of the element [id] in the document [file], or in the current document
+// when [file] is empty.
+func relativeHref(file string, id string) string {
+	return (&gurl.URL{Path: file, Fragment: id}).String()
+}
@@ -0,0 +1,222 @@
+package fb2
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/readium/xmlquery"
+)
+
+const stylesheet = `
+.subtitle, .image { text-align: center; }
+.epigraph { margin-left: 30%; font-style: italic; }
+.cite { margin: 1em 2em; }
+.poem { margin: 1em 2em; }
+.stanza { margin-bottom: 1em; }
+.v { margin: 0; text-indent: 0; }
+.text-author { text-align: right; font-style: italic; }
+img { max-width: 100%; }
+`
+
+// Renders the generated XHTML [doc].
+func (c *converter) render(doc *document) []byte {
+	w := &xhtmlWriter{converter: c, doc: doc}
+	w.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	w.WriteString("<!DOCTYPE html>\n")
+	w.WriteString(`<html xmlns="http://www.w3.org/1999/xhtml"`)
+	if c.lang != "" {
+		w.WriteString(` lang="` + escape(c.lang) + `" xml:lang="` + escape(c.lang) + `"`)
+	}
+	w.WriteString(">\n<head>\n<title>" + escape(c.documentTitle(doc)) + "</title>\n")
+	w.WriteString("<style>" + stylesheet + "</style>\n</head>\n<body>\n")
+	for _, n := range doc.Nodes {
+		w.block(n, 1)
+	}
+	w.WriteString("</body>\n</html>\n")
+	return []byte(w.String())
+}
+
+func (c *converter) documentTitle(doc *document) string {
+	if len(doc.Nodes) == 1 && doc.Nodes[0].Data == "section" {
+		return titleText(child(doc.Nodes[0], "title"))
+	}
+	return c.title(doc)
+}
+
+// Classes of the FB2 elements rendered as a paragraph or a division.
+var blockClasses = map[string]string{
+	"subtitle":    "subtitle",
+	"v":           "v",
+	"text-author": "text-author",
+	"date":        "date",
+	"epigraph":    "epigraph",
+	"cite":        "cite",
+	"annotation":  "annotation",
+	"poem":        "poem",
+	"stanza":      "stanza",
+}
+
+// HTML equivalents of the FB2 inline elements.
+var inlineTags = map[string]string{
+	"emphasis":      "em",
+	"strong":        "strong",
+	"strikethrough": "del",
+	"sub":           "sub",
+	"sup":           "sup",
+	"code":          "code",
+	"style":         "span",
+}
+
+type xhtmlWriter struct {
+	strings.Builder
+	converter *converter
+	doc       *document
+}
+
+// Writes a block element, whose titles are headings of the given [level].
+func (w *xhtmlWriter) block(el *xmlquery.Node, level int) {
+	switch el.Data {
+	case "section":
+		w.start("section", el, "")
+		for _, ch := range children(el) {
+			if ch.Data == "section" {
+				w.block(ch, level+1)
+			} else {
+				w.block(ch, level)
+			}
+		}
+		w.end("section")
+	case "title":
+		tag := "h" + strconv.Itoa(min(level, 6))
+		w.start(tag, el, "")
+		first := true
+		for _, p := range children(el) {
+			if p.Data != "p" {
+				continue
+			}
+			if !first {
+				w.WriteString("<br/>")
+			}
+			first = false
+			w.inlines(p)
+		}
+		w.end(tag)
+	case "p", "subtitle", "v", "text-author", "date":
+		w.start("p", el, blockClasses[el.Data])
+		w.inlines(el)
+		w.end("p")
+	case "empty-line":
+		w.WriteString("<br/>\n")
+	case "epigraph", "cite":
+		w.start("blockquote", el, blockClasses[el.Data])
+		w.blocks(el, level+1)
+		w.end("blockquote")
+	case "annotation", "poem", "stanza":
+		w.start("div", el, blockClasses[el.Data])
+		w.blocks(el, level+1)
+		w.end("div")
+	case "image":
+		w.start("div", el, "image")
+		w.image(el)
+		w.end("div")
+	case "table":
+		w.table(el)
+	default:
+		w.blocks(el, level)
+	}
+}
+
+func (w *xhtmlWriter) blocks(parent *xmlquery.Node, level int) {
+	for _, el := range children(parent) {
+		w.block(el, level)
+	}
+}
+
+// Writes the text and inline elements of [parent].
+func (w *xhtmlWriter) inlines(parent *xmlquery.Node) {
+	for n := parent.FirstChild; n != nil; n = n.NextSibling {
+		switch n.Type {
+		case xmlquery.TextNode, xmlquery.CharDataNode:
+			w.WriteString(escape(n.Data))
+		case xmlquery.ElementNode:
+			if tag, ok := inlineTags[n.Data]; ok {
+				w.WriteString("<" + tag + ">")
+				w.inlines(n)
+				w.WriteString("</" + tag + ">")
+				continue
+			}
+			switch n.Data {
+			case "a":
+				w.WriteString(`<a href="` + escape(w.converter.resolve(w.doc, xlinkHref(n))) + `"`)
+				if n.SelectAttr("type") == "note" {
+					w.WriteString(` role="doc-noteref"`)
+				}
+				w.WriteString(">")
+				w.inlines(n)
+				w.WriteString("</a>")
+			case "image":
+				w.image(n)
+			default:
+				w.inlines(n)
+			}
+		}
+	}
+}
+
+func (w *xhtmlWriter) image(el *xmlquery.Node) {
+	b, ok := w.converter.binaries[strings.TrimPrefix(xlinkHref(el), "#")]
+	if !ok {
+		return
+	}
+	w.WriteString(`<img src="` + escape(pathURL("../"+b.Href).String()) + `" alt="` + escape(el.SelectAttr("alt")) + `"`)
+	if title := el.SelectAttr("title"); title != "" {
+		w.WriteString(` title="` + escape(title) + `"`)
+	}
+	w.WriteString("/>")
+}
+
+func (w *xhtmlWriter) table(el *xmlquery.Node) {
+	w.start("table", el, "")
+	for _, tr := range children(el) {
+		if tr.Data != "tr" {
+			continue
+		}
+		w.WriteString("<tr>")
+		for _, cell := range children(tr) {
+			if cell.Data != "th" && cell.Data != "td" {
+				continue
+			}
+			w.WriteString("<" + cell.Data)
+			for _, attr := range []string{"colspan", "rowspan"} {
+				if v := cell.SelectAttr(attr); v != "" {
+					w.WriteString(" " + attr + `="` + escape(v) + `"`)
+				}
+			}
+			w.WriteString(">")
+			w.inlines(cell)
+			w.WriteString("</" + cell.Data + ">")
+		}
+		w.WriteString("</tr>\n")
+	}
+	w.end("table")
+}
+
+func (w *xhtmlWriter) start(tag string, el *xmlquery.Node, class string) {
+	w.WriteString("<" + tag)
+	if id := w.converter.id(el); id != "" {
+		w.WriteString(` id="` + escape(id) + `"`)
+	}
+	if class != "" {
+		w.WriteString(` class="` + class + `"`)
+	}
+	w.WriteString(">")
+}
+
+func (w *xhtmlWriter) end(tag string) {
+	w.WriteString("</" + tag + ">\n")
+}
+
+func escape(s string) string {
+	return html.EscapeString(s)
+}
@@ -0,0 +1,90 @@
+package mobi
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/manifest"
+)
+
+// Publication converted from a MOBI book.
+type book struct {
+	Metadata  manifest.Metadata
+	Documents []resource // XHTML documents of the reading order
+	Resources []resource // Images and style sheets
+	Cover     string     // HREF of the cover image, if any
+	TOC       manifest.LinkList
+}
+
+// Converts the MOBI book stored in the Palm Database [records]. The [title] is used when the
+// book has none.
+func readBook(records [][]byte, title string) (*book, error) {
+	if len(records) == 0 {
+		return nil, errors.New("MOBI book has no records")
+	}
+	h, err := parseHeader(records[0])
+	if err != nil {
+		return nil, err
+	}
+	if h.Encryption != 0 {
+		return nil, errors.New("encrypted MOBI books are not supported")
+	}
+	// The images are shared by both versions of a combined book.
+	images := readImages(records, h.FirstImage)
+
+	// A combined book holds a KF8 version after the records of the MOBI 6 one, which is preferred.
+	if boundary, ok := h.EXTH.Int(exthKF8Boundary); ok && h.Version < 8 && boundary > 0 && boundary < len(records) && bytes.HasPrefix(records[boundary-1], []byte("BOUNDARY")) {
+		if kh, err := parseHeader(records[boundary]); err == nil && kh.Version >= 8 && kh.Encryption == 0 {
+			records, h = records[boundary:], kh
+		}
+	}
+
+	text, err := readText(records, h)
+	if err != nil {
+		return nil, err
+	}
+	b := &book{Metadata: parseMetadata(h)}
+	if b.Metadata.LocalizedTitle.Length() == 0 {
+		b.Metadata.LocalizedTitle = manifest.NewLocalizedStringFromString(title)
+	}
+	var lang string
+	if len(b.Metadata.Languages) > 0 {
+		lang = b.Metadata.Languages[0]
+	}
+
+	ncx := readNCX(records, h)
+	if h.Version >= 8 {
+		k, err := readKF8(records, h, text, images)
+		if err != nil {
+			return nil, err
+		}
+		parts := k.rewriteLinks()
+		b.TOC = tableOfContents(ncx, func(e ncxEntry) string {
+			return k.resolve(e.FID, e.Offset)
+		})
+		b.Documents = k.documents(parts)
+		b.Resources = k.flowResources()
+	} else {
+		m := readMOBI6(text, ncx)
+		b.TOC = tableOfContents(ncx, func(e ncxEntry) string {
+			return m.resolve(e.Pos)
+		})
+		b.Documents = m.documents(h.decode, images, b.Metadata.Title(), lang)
+	}
+
+	indexes := make([]int, 0, len(images))
+	for i := range images {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		b.Resources = append(b.Resources, images[i])
+	}
+	if offset, ok := h.EXTH.Int(exthCoverOffset); ok {
+		if img, ok := images[offset+1]; ok {
+			b.Cover = img.Href
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,24 @@
+package mobi
+
+import (
+	"os"
+	"testing"
+)
+
+func FuzzReadBook(f *testing.F) {
+	for _, name := range []string{"./testdata/book.mobi", "./testdata/book.azw3"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		records, err := readRecords(data)
+		if err != nil {
+			return
+		}
+		// Malformed books must fail with an error, not a panic.
+		readBook(records, "fuzz")
+	})
+}
@@ -0,0 +1,211 @@
+package mobi
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// Decompresses a record compressed with the PalmDOC flavor of LZ77.
+// Reference: https://wiki.mobileread.com/wiki/PalmDOC
+func decompressPalmDOC(data []byte) []byte {
+	out := make([]byte, 0, len(data)*2)
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c >= 0x01 && c <= 0x08:
+			// Literal run
+			end := min(i+1+int(c), len(data))
+			out = append(out, data[i+1:end]...)
+			i = end - 1
+		case c < 0x80:
+			out = append(out, c)
+		case c >= 0xC0:
+			out = append(out, ' ', c^0x80)
+		default:
+			// Back-reference of 3 to 10 bytes, up to 2047 bytes back
+			if i+1 >= len(data) {
+				return out
+			}
+			i++
+			pair := int(c)<<8 | int(data[i])
+			distance := (pair >> 3) & 0x7FF
+			length := pair&0x7 + 3
+			if distance == 0 || distance > len(out) {
+				continue
+			}
+			// The copied bytes may overlap the ones being written.
+			start := len(out) - distance
+			for j := 0; j < length; j++ {
+				out = append(out, out[start+j])
+			}
+		}
+	}
+	return out
+}
+
+// Decompressor of records compressed with the Huffman coding of Mobipocket, using the code
+// tables of a HUFF record and the phrases of the following CDIC records.
+// Reference: https://wiki.mobileread.com/wiki/MOBI#HUFF_and_CDIC
+type huffCDIC struct {
+	dict1      [256]huffCode
+	minCode    [33]uint64
+	maxCode    [33]uint64
+	dictionary []huffPhrase
+}
+
+type huffCode struct {
+	length   int
+	terminal bool
+	maxCode  uint64
+}
+
+type huffPhrase struct {
+	data         []byte
+	decompressed bool
+}
+
+func newHuffCDIC(records [][]byte) (*huffCDIC, error) {
+	if len(records) == 0 || len(records[0]) < 24 || string(records[0][:8]) != "HUFF\x00\x00\x00\x18" {
+		return nil, errors.New("invalid HUFF record")
+	}
+	huff := records[0]
+	off1 := int(binary.BigEndian.Uint32(huff[8:]))
+	off2 := int(binary.BigEndian.Uint32(huff[12:]))
+	if off1+256*4 > len(huff) || off2+64*4 > len(huff) {
+		return nil, errors.New("truncated HUFF record")
+	}
+
+	h := &huffCDIC{}
+	for i := range h.dict1 {
+		v := binary.BigEndian.Uint32(huff[off1+i*4:])
+		length := int(v & 0x1F)
+		if length == 0 {
+			return nil, errors.New("invalid HUFF code length")
+		}
+		h.dict1[i] = huffCode{
+			length:   length,
+			terminal: v&0x80 != 0,
+			maxCode:  ((uint64(v>>8) + 1) << (32 - length)) - 1,
+		}
+	}
+	for length := 1; length <= 32; length++ {
+		min := uint64(binary.BigEndian.Uint32(huff[off2+(length-1)*8:]))
+		max := uint64(binary.BigEndian.Uint32(huff[off2+(length-1)*8+4:]))
+		h.minCode[length] = min << (32 - length)
+		h.maxCode[length] = ((max + 1) << (32 - length)) - 1
+	}
+
+	for _, cdic := range records[1:] {
+		if len(cdic) < 16 || string(cdic[:8]) != "CDIC\x00\x00\x00\x10" {
+			return nil, errors.New("invalid CDIC record")
+		}
+		phrases := int(binary.BigEndian.Uint32(cdic[8:]))
+		bits := int(binary.BigEndian.Uint32(cdic[12:]))
+		n := min(1<<bits, phrases-len(h.dictionary))
+		for i := 0; i < n; i++ {
+			off := int(readUint(cdic, 16+i*2, 2))
+			blen := int(readUint(cdic, 16+off, 2))
+			start := 18 + off
+			end := start + blen&0x7FFF
+			if end > len(cdic) {
+				return nil, errors.New("truncated CDIC record")
+			}
+			h.dictionary = append(h.dictionary, huffPhrase{
+				data:         cdic[start:end],
+				decompressed: blen&0x8000 != 0,
+			})
+		}
+	}
+	return h, nil
+}
+
+func (h *huffCDIC) Decompress(data []byte) ([]byte, error) {
+	return h.unpack(data, 0)
+}
+
+func (h *huffCDIC) unpack(data []byte, depth int) ([]byte, error) {
+	if depth > 32 {
+		return nil, errors.New("too many nested HUFF/CDIC phrases")
+	}
+	bitsLeft := len(data) * 8
+	padded := make([]byte, len(data)+8)
+	copy(padded, data)
+
+	var out []byte
+	pos := 0
+	x := binary.BigEndian.Uint64(padded)
+	n := 32
+	for {
+		if n <= 0 {
+			pos += 4
+			if pos+8 > len(padded) {
+				break
+			}
+			x = binary.BigEndian.Uint64(padded[pos:])
+			n += 32
+		}
+		code := (x >> n) & 0xFFFFFFFF
+		c := h.dict1[code>>24]
+		length, maxCode := c.length, c.maxCode
+		if !c.terminal {
+			for length < 32 && code < h.minCode[length] {
+				length++
+			}
+			maxCode = h.maxCode[length]
+		}
+		n -= length
+		bitsLeft -= length
+		if bitsLeft < 0 {
+			break
+		}
+
+		r := int((maxCode - code) >> (32 - length))
+		if r < 0 || r >= len(h.dictionary) {
+			return nil, errors.New("invalid HUFF/CDIC phrase index")
+		}
+		phrase := h.dictionary[r]
+		if !phrase.decompressed {
+			// Phrases are themselves compressed until first used.
+			data, err := h.unpack(phrase.data, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			phrase = huffPhrase{data: data, decompressed: true}
+			h.dictionary[r] = phrase
+		}
+		out = append(out, phrase.data...)
+	}
+	return out, nil
+}
+
+// Size of the trailing entries appended to a text record, described by the extra flags of the
+// MOBI header.
+// Reference: https://wiki.mobileread.com/wiki/MOBI#Variable-width_integers
+func trailingEntriesSize(data []byte, flags int) int {
+	size := 0
+	for f := flags >> 1; f != 0; f >>= 1 {
+		if f&1 == 0 {
+			continue
+		}
+		// Each entry ends with its own size, as a backward-encoded variable-width integer.
+		value, shift := 0, 0
+		for i := len(data) - size - 1; i >= 0; i-- {
+			b := data[i]
+			value |= int(b&0x7F) << shift
+			shift += 7
+			if b&0x80 != 0 || shift >= 28 {
+				break
+			}
+		}
+		size += value
+		if size > len(data) {
+			return len(data)
+		}
+	}
+	if flags&1 != 0 && size < len(data) {
+		// Multibyte character overlap
+		size += int(data[len(data)-size-1]&0x3) + 1
+	}
+	return min(size, len(data))
+}
@@ -0,0 +1,82 @@
+package mobi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecompressPalmDOC(t *testing.T) {
+	// Literals, a back-reference of 3 bytes at a distance of 3, a space followed by "a" and a
+	// literal run of 2 bytes.
+	assert.Equal(t, []byte("abcabc a\xff\xfe"), decompressPalmDOC([]byte{'a', 'b', 'c', 0x80, 0x18, 0xE1, 0x02, 0xFF, 0xFE}))
+	// Overlapping back-reference
+	assert.Equal(t, []byte("aaaaaa"), decompressPalmDOC([]byte{'a', 0x80, 0x0A}))
+}
+
+// Builds HUFF/CDIC records where each byte is an 8-bit code of the phrase at index 255 - byte.
+func huffCDICRecords(phrases map[byte][]byte, compressed map[byte]bool) [][]byte {
+	huff := make([]byte, 24+256*4+64*4)
+	copy(huff, "HUFF\x00\x00\x00\x18")
+	binary.BigEndian.PutUint32(huff[8:], 24)
+	binary.BigEndian.PutUint32(huff[12:], 24+256*4)
+	for i := 0; i < 256; i++ {
+		binary.BigEndian.PutUint32(huff[24+i*4:], 255<<8|0x80|8)
+	}
+
+	cdic := make([]byte, 16+256*2)
+	copy(cdic, "CDIC\x00\x00\x00\x10")
+	binary.BigEndian.PutUint32(cdic[8:], 256)
+	binary.BigEndian.PutUint32(cdic[12:], 8)
+	for i := 0; i < 256; i++ {
+		b := byte(255 - i)
+		phrase, ok := phrases[b]
+		if !ok {
+			phrase = []byte{b}
+		}
+		flag := 0x8000
+		if compressed[b] {
+			flag = 0
+		}
+		binary.BigEndian.PutUint16(cdic[16+i*2:], uint16(len(cdic)-16))
+		cdic = binary.BigEndian.AppendUint16(cdic, uint16(len(phrase)|flag))
+		cdic = append(cdic, phrase...)
+	}
+	return [][]byte{huff, cdic}
+}
+
+func TestDecompressHuffCDIC(t *testing.T) {
+	h, err := newHuffCDIC(huffCDICRecords(
+		map[byte][]byte{0x01: []byte("Hello, "), 0x02: {0x01, 'W'}},
+		map[byte]bool{0x02: true},
+	))
+	if !assert.NoError(t, err) {
+		return
+	}
+	data, err := h.Decompress([]byte{0x01, 'W', 'o', 'r', 'l', 'd', 0x02})
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello, WorldHello, W", string(data))
+
+	_, err = newHuffCDIC([][]byte{[]byte("HUFF")})
+	assert.Error(t, err)
+}
+
+func TestTrailingEntriesSize(t *testing.T) {
+	record := []byte("abc\x00\xaa\xbb\x83")
+	assert.Equal(t, 4, trailingEntriesSize(record, 0x3))
+	assert.Equal(t, 3, trailingEntriesSize(record, 0x2))
+	assert.Equal(t, 0, trailingEntriesSize(record, 0))
+}
+
+func TestReadVarint(t *testing.T) {
+	value, size := readVarint([]byte{0x01, 0x82, 0xFF})
+	assert.Equal(t, 130, value)
+	assert.Equal(t, 2, size)
+
+	// Long runs of bytes without the high bit can't overflow.
+	value, size = readVarint(bytes.Repeat([]byte{0x7F}, 20))
+	assert.Equal(t, maxVarintSize, size)
+	assert.Equal(t, 1<<28-1, value)
+}
@@ -0,0 +1,163 @@
+package mobi
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Index of the header fields referencing no record.
+const nullIndex = -1
+
+// Compression methods of the text records
+const (
+	compressionNone     = 1
+	compressionPalmDOC  = 2
+	compressionHuffCDIC = 17480
+)
+
+// MOBI header of a book, found in its first record.
+// The record indexes are relative to the first record of the book, which is not the first record
+// of the database for the KF8 part of a combined MOBI file.
+// Reference: https://wiki.mobileread.com/wiki/MOBI#MOBI_Header
+type header struct {
+	Compression   int
+	TextLength    int
+	TextRecords   int
+	Encryption    int
+	UTF8          bool
+	Version       int
+	Title         string
+	FirstImage    int
+	HuffRecord    int
+	HuffCount     int
+	ExtraFlags    int
+	FDSTIndex     int
+	NCXIndex      int
+	FragmentIndex int
+	SkeletonIndex int
+	EXTH          exth
+}
+
+func parseHeader(record []byte) (*header, error) {
+	if len(record) < 16 {
+		return nil, errors.New("truncated MOBI header")
+	}
+	h := &header{
+		Compression:   int(readUint(record, 0, 2)),
+		TextLength:    int(readUint(record, 4, 4)),
+		TextRecords:   int(readUint(record, 8, 2)),
+		Encryption:    int(readUint(record, 12, 2)),
+		FirstImage:    nullIndex,
+		HuffRecord:    nullIndex,
+		FDSTIndex:     nullIndex,
+		NCXIndex:      nullIndex,
+		FragmentIndex: nullIndex,
+		SkeletonIndex: nullIndex,
+	}
+	if len(record) < 0x84 || string(record[16:20]) != "MOBI" {
+		// Plain PalmDOC book
+		return h, nil
+	}
+
+	length := int(readUint(record, 20, 4))
+	h.UTF8 = readUint(record, 28, 4) == 65001
+	h.Version = int(readUint(record, 0x68, 4))
+	h.FirstImage = recordIndex(record, 0x6C)
+	h.HuffRecord = recordIndex(record, 0x70)
+	h.HuffCount = int(readUint(record, 0x74, 4))
+	if h.Version >= 5 && length >= 0xE4 {
+		h.ExtraFlags = int(readUint(record, 0xF2, 2))
+	}
+	if length >= 0xE4 {
+		h.NCXIndex = recordIndex(record, 0xF4)
+	}
+	if h.Version >= 8 {
+		h.FDSTIndex = recordIndex(record, 0xC0)
+		h.FragmentIndex = recordIndex(record, 0xF8)
+		h.SkeletonIndex = recordIndex(record, 0xFC)
+	}
+
+	if offset, size := int(readUint(record, 0x54, 4)), int(readUint(record, 0x58, 4)); offset+size <= len(record) {
+		h.Title = h.decode(record[offset : offset+size])
+	}
+	if readUint(record, 0x80, 4)&0x40 != 0 && 16+length <= len(record) {
+		h.EXTH = parseEXTH(record[16+length:])
+	}
+	return h, nil
+}
+
+// Reads the index of a record at [offset] in the header.
+func recordIndex(record []byte, offset int) int {
+	if v := readUint(record, offset, 4); v != 0xFFFFFFFF {
+		return int(v)
+	}
+	return nullIndex
+}
+
+// Decodes a string of the book, which is either UTF-8 or Windows-1252.
+func (h *header) decode(data []byte) string {
+	if h.UTF8 {
+		return string(data)
+	}
+	s, err := charmap.Windows1252.NewDecoder().Bytes(data)
+	if err != nil {
+		return string(data)
+	}
+	return string(s)
+}
+
+// EXTH record types
+// Reference: https://wiki.mobileread.com/wiki/MOBI#EXTH_Header
+const (
+	exthAuthor      = 100
+	exthPublisher   = 101
+	exthDescription = 103
+	exthISBN        = 104
+	exthSubject     = 105
+	exthPublished   = 106
+	exthContributor = 108
+	exthRights      = 109
+	exthASIN        = 113
+	exthKF8Boundary = 121
+	exthFixedLayout = 122
+	exthCoverOffset = 201
+	exthTitle       = 503
+	exthLanguage    = 524
+)
+
+// Extended metadata of a MOBI header, grouped by record type.
+type exth map[int][][]byte
+
+func parseEXTH(data []byte) exth {
+	if len(data) < 12 || string(data[:4]) != "EXTH" {
+		return nil
+	}
+	e := make(exth)
+	count := int(binary.BigEndian.Uint32(data[8:]))
+	pos := 12
+	for i := 0; i < count && pos+8 <= len(data); i++ {
+		typ := int(binary.BigEndian.Uint32(data[pos:]))
+		size := int(binary.BigEndian.Uint32(data[pos+4:]))
+		if size < 8 || pos+size > len(data) {
+			break
+		}
+		e[typ] = append(e[typ], data[pos+8:pos+size])
+		pos += size
+	}
+	return e
+}
+
+// Returns the integer value of the first record of type [typ].
+func (e exth) Int(typ int) (int, bool) {
+	values := e[typ]
+	if len(values) == 0 || len(values[0]) == 0 || len(values[0]) > 4 {
+		return 0, false
+	}
+	var n int
+	for _, b := range values[0] {
+		n = n<<8 | int(b)
+	}
+	return n, true
+}
@@ -0,0 +1,57 @@
+package mobi
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/readium/go-toolkit/pkg/mediatype"
+)
+
+// Resource generated from a MOBI book.
+type resource struct {
+	Href      string // Relative to the root of the publication
+	MediaType *mediatype.MediaType
+	Data      []byte
+}
+
+// Collects the image records following the text of the book, by their index starting at 1, which
+// is how the text references them.
+func readImages(records [][]byte, first int) map[int]resource {
+	images := make(map[int]resource)
+	if first == nullIndex {
+		return images
+	}
+	for i := first; i < len(records); i++ {
+		data := records[i]
+		if bytes.HasPrefix(data, []byte("BOUNDARY")) {
+			// Start of the KF8 part of a combined book
+			break
+		}
+		mt, ext := imageType(data)
+		if mt == nil {
+			continue
+		}
+		index := i - first + 1
+		images[index] = resource{
+			Href:      fmt.Sprintf("images/image-%04d.%s", index, ext),
+			MediaType: mt,
+			Data:      data,
+		}
+	}
+	return images
+}
+
+// Sniffs the media type of an image record from its magic number.
+func imageType(data []byte) (*mediatype.MediaType, string) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return &mediatype.JPEG, "jpg"
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return &mediatype.PNG, "png"
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return &mediatype.GIF, "gif"
+	case bytes.HasPrefix(data, []byte("BM")) && len(data) > 14:
+		return &mediatype.BMP, "bmp"
+	}
+	return nil, ""
+}
@@ -0,0 +1,197 @@
+package mobi
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Entry of an INDX index, identified by a label and holding lists of integer values by tag.
+type indexEntry struct {
+	Label string
+	Tags  map[int][]int
+}
+
+// Returns the value at position [i] of the given [tag], or [def] when missing.
+func (e indexEntry) Value(tag int, i int, def int) int {
+	if values := e.Tags[tag]; i < len(values) {
+		return values[i]
+	}
+	return def
+}
+
+type tagDescriptor struct {
+	Tag       int
+	Values    int
+	Mask      byte
+	EndOfMask bool
+}
+
+// Reads the index starting at the header record [start] of [records], followed by its data
+// records and its CNCX records holding the strings referenced by the entries.
+// Reference: https://wiki.mobileread.com/wiki/MOBI#Index_Records
+func readIndex(records [][]byte, start int) ([]indexEntry, cncx, error) {
+	if start < 0 || start >= len(records) {
+		return nil, nil, errors.Errorf("index record %d out of bounds", start)
+	}
+	header := records[start]
+	if len(header) < 56 || string(header[:4]) != "INDX" {
+		return nil, nil, errors.New("invalid INDX header record")
+	}
+	dataCount := int(readUint(header, 24, 4))
+	cncxCount := int(readUint(header, 52, 4))
+
+	tagx := header[min(int(readUint(header, 4, 4)), len(header)):]
+	if len(tagx) < 12 || string(tagx[:4]) != "TAGX" {
+		return nil, nil, errors.New("missing TAGX section in index")
+	}
+	controlBytes := int(readUint(tagx, 8, 4))
+	var tags []tagDescriptor
+	for i := 12; i+4 <= min(int(readUint(tagx, 4, 4)), len(tagx)); i += 4 {
+		tags = append(tags, tagDescriptor{
+			Tag:       int(tagx[i]),
+			Values:    int(tagx[i+1]),
+			Mask:      tagx[i+2],
+			EndOfMask: tagx[i+3] == 1,
+		})
+	}
+
+	var labels cncx
+	if cncxStart := start + 1 + dataCount; cncxCount > 0 && cncxStart+cncxCount <= len(records) {
+		labels = readCNCX(records[cncxStart : cncxStart+cncxCount])
+	}
+
+	var entries []indexEntry
+	for r := start + 1; r <= start+dataCount && r < len(records); r++ {
+		data := records[r]
+		if len(data) < 28 || string(data[:4]) != "INDX" {
+			return nil, nil, errors.New("invalid INDX data record")
+		}
+		idxt := int(readUint(data, 20, 4))
+		count := int(readUint(data, 24, 4))
+		if idxt+4+count*2 > len(data) || string(data[idxt:idxt+4]) != "IDXT" {
+			return nil, nil, errors.New("invalid IDXT section in index")
+		}
+		for i := 0; i < count; i++ {
+			from := int(readUint(data, idxt+4+i*2, 2))
+			to := idxt
+			if i+1 < count {
+				to = int(readUint(data, idxt+4+(i+1)*2, 2))
+			}
+			if from >= to || to > len(data) {
+				return nil, nil, errors.New("invalid index entry offset")
+			}
+			entries = append(entries, parseIndexEntry(data[from:to], controlBytes, tags))
+		}
+	}
+	return entries, labels, nil
+}
+
+func parseIndexEntry(data []byte, controlBytes int, tags []tagDescriptor) indexEntry {
+	length := min(int(data[0]), len(data)-1)
+	entry := indexEntry{
+		Label: string(data[1 : 1+length]),
+		Tags:  make(map[int][]int),
+	}
+	data = data[1+length:]
+	if len(data) < controlBytes {
+		return entry
+	}
+	control := data[:controlBytes]
+	data = data[controlBytes:]
+
+	// The control bytes tell how many values, or bytes of values, each tag has.
+	type tagCount struct {
+		tag       tagDescriptor
+		count     int
+		byteCount int
+	}
+	var counts []tagCount
+	for _, t := range tags {
+		if t.EndOfMask {
+			if len(control) > 0 {
+				control = control[1:]
+			}
+			continue
+		}
+		if len(control) == 0 {
+			break
+		}
+		value := control[0] & t.Mask
+		if value == 0 {
+			continue
+		}
+		if value == t.Mask && bitCount(t.Mask) > 1 {
+			n, size := readVarint(data)
+			data = data[size:]
+			counts = append(counts, tagCount{tag: t, byteCount: n})
+			continue
+		}
+		for mask := t.Mask; mask&1 == 0; mask >>= 1 {
+			value >>= 1
+		}
+		counts = append(counts, tagCount{tag: t, count: int(value)})
+	}
+
+	for _, c := range counts {
+		var values []int
+		if c.byteCount > 0 {
+			for consumed := 0; consumed < c.byteCount && len(data) > 0; {
+				v, size := readVarint(data)
+				data = data[size:]
+				consumed += size
+				values = append(values, v)
+			}
+		} else {
+			for i := 0; i < c.count*c.tag.Values && len(data) > 0; i++ {
+				v, size := readVarint(data)
+				data = data[size:]
+				values = append(values, v)
+			}
+		}
+		entry.Tags[c.tag.Tag] = values
+	}
+	return entry
+}
+
+// Maximum size of a variable-width integer, which can't overflow an int.
+const maxVarintSize = 4
+
+// Reads a forward-encoded variable-width integer, whose last byte has its high bit set.
+// Integers longer than [maxVarintSize] bytes are truncated.
+func readVarint(data []byte) (value int, size int) {
+	for _, b := range data {
+		value = value<<7 | int(b&0x7F)
+		size++
+		if b&0x80 != 0 || size == maxVarintSize {
+			break
+		}
+	}
+	return
+}
+
+func bitCount(b byte) int {
+	n := 0
+	for ; b != 0; b >>= 1 {
+		n += int(b & 1)
+	}
+	return n
+}
+
+// Strings of an index, by offset.
+type cncx map[int]string
+
+func readCNCX(records [][]byte) cncx {
+	c := make(cncx)
+	for i, record := range records {
+		for pos := 0; pos < len(record); {
+			length, size := readVarint(record[pos:])
+			if size == 0 || length < 0 || pos+size+length > len(record) {
+				break
+			}
+			if length > 0 {
+				c[i*0x10000+pos] = string(record[pos+size : pos+size+length])
+			}
+			pos += size + length
+		}
+	}
+	return c
+}
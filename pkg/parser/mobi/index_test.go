@@ -0,0 +1,13 @@
+package mobi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCNCXIgnoresTruncatedStrings(t *testing.T) {
+	c := readCNCX([][]byte{append([]byte{0x83, 'a', 'b', 'c'}, bytes.Repeat([]byte{0x7F}, 20)...)})
+	assert.Equal(t, cncx{0: "abc"}, c)
+}
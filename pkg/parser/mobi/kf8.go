@@ -0,0 +1,273 @@
+package mobi
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	gurl "net/url"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+)
+
+var (
+	posFidPattern    = regexp.MustCompile(`kindle:pos:fid:([0-9A-V]{4}):off:([0-9A-V]{10})`)
+	kindleURLPattern = regexp.MustCompile(`kindle:(embed|flow):([0-9A-V]{4})(?:\?mime=[^'"\s)]*)?`)
+	idAttrPattern    = regexp.MustCompile(`\sid\s*=\s*['"]([^'"]*)['"]`)
+	aidAttrPattern   = regexp.MustCompile(`\said\s*=\s*['"]([^'"]*)['"]`)
+)
+
+// Book in the Kindle Format 8, whose text is made of skeletons of XHTML documents in which
+// fragments of content are inserted.
+// Reference: https://wiki.mobileread.com/wiki/KF8
+type kf8 struct {
+	flows     [][]byte // Sections of the text, the first one holding the XHTML documents
+	flowHrefs map[int]string
+	parts     []kf8Part
+	fragments []kf8Fragment
+	images    map[int]resource
+	anchors   []map[string]bool // Values of the aid attributes turned into IDs, by part
+}
+
+// XHTML document reconstructed from a skeleton and its fragments.
+type kf8Part struct {
+	Start int // Range of the skeleton and its fragments in the text
+	End   int
+	Data  []byte
+}
+
+type kf8Fragment struct {
+	InsertPos int // Position in the text where the fragment is inserted
+	Length    int
+}
+
+func partHref(index int) string {
+	return fmt.Sprintf("text/part%04d.xhtml", index)
+}
+
+func readKF8(records [][]byte, h *header, text []byte, images map[int]resource) (*kf8, error) {
+	k := &kf8{
+		flowHrefs: make(map[int]string),
+		images:    images,
+	}
+
+	// The FDST record splits the text into flows, e.g. style sheets and SVG images.
+	if h.FDSTIndex != nullIndex && h.FDSTIndex < len(records) {
+		fdst := records[h.FDSTIndex]
+		if len(fdst) >= 12 && string(fdst[:4]) == "FDST" {
+			count := int(readUint(fdst, 8, 4))
+			if count > (len(fdst)-12)/8 {
+				return nil, errors.Errorf("FDST record is too short for %d flows", count)
+			}
+			for i := 0; i < count; i++ {
+				start, end := int(readUint(fdst, 12+i*8, 4)), int(readUint(fdst, 16+i*8, 4))
+				if start > end || end > len(text) {
+					return nil, errors.Errorf("invalid range of flow %d", i)
+				}
+				k.flows = append(k.flows, text[start:end])
+			}
+		}
+	}
+	if len(k.flows) == 0 {
+		k.flows = [][]byte{text}
+	}
+	for i, flow := range k.flows[1:] {
+		if bytes.Contains(flow, []byte("<svg")) {
+			k.flowHrefs[i+1] = fmt.Sprintf("images/flow%04d.svg", i+1)
+		} else {
+			k.flowHrefs[i+1] = fmt.Sprintf("styles/flow%04d.css", i+1)
+		}
+	}
+
+	xhtml := k.flows[0]
+	if h.SkeletonIndex == nullIndex {
+		k.parts = []kf8Part{{Start: 0, End: len(xhtml), Data: xhtml}}
+		k.anchors = make([]map[string]bool, 1)
+		return k, nil
+	}
+	skeletons, _, err := readIndex(records, h.SkeletonIndex)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading skeleton index")
+	}
+	if h.FragmentIndex != nullIndex {
+		fragments, _, err := readIndex(records, h.FragmentIndex)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed reading fragment index")
+		}
+		for _, f := range fragments {
+			pos, err := strconv.Atoi(f.Label)
+			if err != nil {
+				return nil, errors.Errorf("invalid fragment position %q", f.Label)
+			}
+			k.fragments = append(k.fragments, kf8Fragment{InsertPos: pos, Length: f.Value(6, 1, 0)})
+		}
+	}
+
+	next := 0
+	for _, s := range skeletons {
+		start, length := s.Value(6, 0, 0), s.Value(6, 1, 0)
+		if start < 0 || length < 0 || start+length > len(xhtml) {
+			return nil, errors.Errorf("skeleton %s out of the text", s.Label)
+		}
+		data := slices.Clone(xhtml[start : start+length])
+		end := start + length
+		for i := 0; i < s.Value(1, 0, 0) && next < len(k.fragments); i++ {
+			f := k.fragments[next]
+			next++
+			insert := f.InsertPos - start
+			if insert < 0 || insert > len(data) || f.Length < 0 || end+f.Length > len(xhtml) {
+				return nil, errors.Errorf("fragment %d out of skeleton %s", next-1, s.Label)
+			}
+			data = slices.Insert(data, insert, xhtml[end:end+f.Length]...)
+			end += f.Length
+		}
+		k.parts = append(k.parts, kf8Part{Start: start, End: end, Data: data})
+	}
+	k.anchors = make([]map[string]bool, len(k.parts))
+	return k, nil
+}
+
+// Resolves the position at [offset] from the start of the fragment [fid] to the HREF of the
+// closest element having an ID, relative to the root of the publication.
+func (k *kf8) resolve(fid int, offset int) string {
+	if fid < 0 || fid >= len(k.fragments) {
+		return ""
+	}
+	pos := k.fragments[fid].InsertPos + offset
+	for i, p := range k.parts {
+		if pos < p.Start || pos >= p.End {
+			continue
+		}
+		u := gurl.URL{Path: partHref(i), Fragment: k.anchor(i, pos-p.Start)}
+		return u.String()
+	}
+	return ""
+}
+
+// Finds the ID of the element starting before [pos] in the part [index]. Elements lacking an ID
+// are identified by their aid attribute, which is turned into an ID when rendering the part.
+func (k *kf8) anchor(index int, pos int) string {
+	data := k.parts[index].Data
+	pos = min(pos, len(data))
+	// Includes the tag at the position.
+	lt := bytes.IndexByte(data[pos:], '<')
+	gt := bytes.IndexByte(data[pos:], '>')
+	if gt >= 0 && (lt == 0 || lt < 0 || gt < lt) {
+		pos += gt + 1
+	}
+
+	block := data[:pos]
+	for {
+		i := bytes.LastIndexByte(block, '<')
+		if i < 0 {
+			return ""
+		}
+		tag := block[i:]
+		if end := bytes.IndexByte(tag, '>'); end >= 0 {
+			tag = tag[:end+1]
+		}
+		if m := idAttrPattern.FindSubmatch(tag); m != nil {
+			return string(m[1])
+		}
+		if m := aidAttrPattern.FindSubmatch(tag); m != nil {
+			if k.anchors[index] == nil {
+				k.anchors[index] = make(map[string]bool)
+			}
+			k.anchors[index][string(m[1])] = true
+			return "aid-" + string(m[1])
+		}
+		block = block[:i]
+	}
+}
+
+// Renders the parts as XHTML documents, rewriting the kindle: URLs of links and resources.
+// The table of contents must be resolved beforehand, as it might add anchors to the parts.
+func (k *kf8) documents(parts [][]byte) []resource {
+	var docs []resource
+	for i, data := range parts {
+		for aid := range k.anchors[i] {
+			pattern := regexp.MustCompile(`\said\s*=\s*['"]` + regexp.QuoteMeta(aid) + `['"]`)
+			done := false
+			data = pattern.ReplaceAllFunc(data, func(m []byte) []byte {
+				if done {
+					return m
+				}
+				done = true
+				return append([]byte(` id="aid-`+aid+`"`), m...)
+			})
+		}
+		docs = append(docs, resource{
+			Href:      partHref(i),
+			MediaType: &mediatype.XHTML,
+			Data:      k.rewriteResources(data, "../"),
+		})
+	}
+	return docs
+}
+
+// Rewrites the links between the parts, which must happen before rendering the documents, as it
+// resolves the anchors of the targets.
+func (k *kf8) rewriteLinks() [][]byte {
+	res := make([][]byte, len(k.parts))
+	for i, p := range k.parts {
+		res[i] = posFidPattern.ReplaceAllFunc(p.Data, func(m []byte) []byte {
+			sm := posFidPattern.FindSubmatch(m)
+			href := k.resolve(base32(sm[1]), base32(sm[2]))
+			if href == "" {
+				return m
+			}
+			// Parts are siblings in the same directory.
+			return []byte(strings.TrimPrefix(href, "text/"))
+		})
+	}
+	return res
+}
+
+// Returns the style sheets and SVG images stored in flows.
+func (k *kf8) flowResources() []resource {
+	var res []resource
+	for i := 1; i < len(k.flows); i++ {
+		href := k.flowHrefs[i]
+		mt := &mediatype.CSS
+		if path.Ext(href) == ".svg" {
+			mt = &mediatype.SVG
+		}
+		res = append(res, resource{
+			Href:      href,
+			MediaType: mt,
+			Data:      k.rewriteResources(k.flows[i], "../"),
+		})
+	}
+	return res
+}
+
+// Replaces the kindle:embed and kindle:flow URLs in [data] with the HREFs of the resources,
+// relative to a document in a sub-directory of the root.
+func (k *kf8) rewriteResources(data []byte, prefix string) []byte {
+	return kindleURLPattern.ReplaceAllFunc(data, func(m []byte) []byte {
+		sm := kindleURLPattern.FindSubmatch(m)
+		index := base32(sm[2])
+		if string(sm[1]) == "embed" {
+			if img, ok := k.images[index]; ok {
+				return []byte(prefix + img.Href)
+			}
+		} else if href, ok := k.flowHrefs[index]; ok {
+			return []byte(prefix + href)
+		}
+		return m
+	})
+}
+
+// Parses a base 32 number of the kindle: URLs, using the digits 0-9 and A-V.
+func base32(s []byte) int {
+	n, err := strconv.ParseInt(string(s), 32, 64)
+	if err != nil {
+		return -1
+	}
+	return int(n)
+}
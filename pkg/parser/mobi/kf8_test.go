@@ -0,0 +1,13 @@
+package mobi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadKF8TruncatedFDST(t *testing.T) {
+	fdst := []byte("FDST\x00\x00\x00\x0c\xff\xff\xff\xff\x00\x00\x00\x00\x00\x00\x00\x01")
+	_, err := readKF8([][]byte{fdst}, &header{FDSTIndex: 0, SkeletonIndex: nullIndex}, []byte("text"), nil)
+	assert.Error(t, err)
+}
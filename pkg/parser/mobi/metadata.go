@@ -0,0 +1,69 @@
+package mobi
+
+import (
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+)
+
+// Maps the EXTH records of the MOBI header to the publication metadata.
+func parseMetadata(h *header) manifest.Metadata {
+	var metadata manifest.Metadata
+	values := func(typ int) []string {
+		var res []string
+		for _, v := range h.EXTH[typ] {
+			if s := strings.TrimSpace(h.decode(v)); s != "" {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+	first := func(typ int) string {
+		if v := values(typ); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	title := first(exthTitle)
+	if title == "" {
+		title = strings.TrimSpace(h.Title)
+	}
+	if title != "" {
+		metadata.LocalizedTitle = manifest.NewLocalizedStringFromString(title)
+	}
+	for _, author := range values(exthAuthor) {
+		metadata.Authors = append(metadata.Authors, manifest.Contributor{
+			LocalizedName: manifest.NewLocalizedStringFromString(author),
+		})
+	}
+	for _, publisher := range values(exthPublisher) {
+		metadata.Publishers = append(metadata.Publishers, manifest.Contributor{
+			LocalizedName: manifest.NewLocalizedStringFromString(publisher),
+		})
+	}
+	for _, subject := range values(exthSubject) {
+		metadata.Subjects = append(metadata.Subjects, manifest.Subject{
+			LocalizedName: manifest.NewLocalizedStringFromString(subject),
+		})
+	}
+	metadata.Description = first(exthDescription)
+	metadata.Published = extensions.ParseDate(first(exthPublished))
+	if lang := first(exthLanguage); lang != "" {
+		metadata.Languages = manifest.Strings{lang}
+	}
+
+	if isbn := strings.NewReplacer("-", "", " ", "").Replace(first(exthISBN)); isbn != "" {
+		metadata.Identifier = "urn:isbn:" + isbn
+	} else if asin := first(exthASIN); asin != "" {
+		metadata.Identifier = "urn:asin:" + asin
+	}
+
+	layout := manifest.EPUBLayoutReflowable
+	if first(exthFixedLayout) == "true" {
+		layout = manifest.EPUBLayoutFixed
+	}
+	metadata.Presentation = &manifest.Presentation{Layout: &layout}
+	return metadata
+}
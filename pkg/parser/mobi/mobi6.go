@@ -0,0 +1,137 @@
+package mobi
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	gurl "net/url"
+
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	nhtml "golang.org/x/net/html"
+)
+
+var (
+	fileposPattern   = regexp.MustCompile(`(?i)filepos\s*=\s*['"]?0*(\d+)`)
+	anchorPattern    = regexp.MustCompile(`<a id="filepos(\d+)"></a>`)
+	pagebreakPattern = regexp.MustCompile(`(?i)<mbp:pagebreak[^>]*>`)
+	tagPattern       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Book in the legacy MOBI format, whose text is a single HTML document referencing positions in
+// the text with filepos attributes.
+type mobi6 struct {
+	parts   [][]byte
+	targets map[int]int // Part holding the anchor of each position
+}
+
+// Splits the text of a MOBI 6 book at its page breaks, after inserting anchors at the positions
+// targeted by the links and the table of contents.
+func readMOBI6(text []byte, ncx []ncxEntry) *mobi6 {
+	var positions []int
+	for _, m := range fileposPattern.FindAllSubmatch(text, -1) {
+		if pos, err := strconv.Atoi(string(m[1])); err == nil && pos <= len(text) {
+			positions = append(positions, pos)
+		}
+	}
+	for _, e := range ncx {
+		if e.Pos >= 0 && e.Pos <= len(text) {
+			positions = append(positions, e.Pos)
+		}
+	}
+	slices.Sort(positions)
+	positions = slices.Compact(positions)
+
+	var anchored bytes.Buffer
+	cursor := 0
+	for _, pos := range positions {
+		// An anchor can't be inserted inside a tag.
+		at := pos
+		if lt := bytes.LastIndexByte(text[:pos], '<'); lt > bytes.LastIndexByte(text[:pos], '>') {
+			at = lt
+		}
+		at = max(at, cursor)
+		anchored.Write(text[cursor:at])
+		fmt.Fprintf(&anchored, `<a id="filepos%d"></a>`, pos)
+		cursor = at
+	}
+	anchored.Write(text[cursor:])
+
+	// Chunks without any content are merged with the following one, to keep their anchors.
+	b := &mobi6{targets: make(map[int]int)}
+	var pending []byte
+	for _, chunk := range pagebreakPattern.Split(anchored.String(), -1) {
+		pending = append(pending, chunk...)
+		content := strings.TrimSpace(tagPattern.ReplaceAllString(string(pending), ""))
+		if content != "" || strings.Contains(strings.ToLower(string(pending)), "<img") {
+			b.parts = append(b.parts, pending)
+			pending = nil
+		}
+	}
+	if len(pending) > 0 {
+		if len(b.parts) == 0 {
+			b.parts = append(b.parts, pending)
+		} else {
+			b.parts[len(b.parts)-1] = append(b.parts[len(b.parts)-1], pending...)
+		}
+	}
+
+	for i, part := range b.parts {
+		for _, m := range anchorPattern.FindAllSubmatch(part, -1) {
+			if pos, err := strconv.Atoi(string(m[1])); err == nil {
+				b.targets[pos] = i
+			}
+		}
+	}
+	return b
+}
+
+// Returns the HREF of the anchor inserted at the position [pos], relative to the root of the
+// publication.
+func (b *mobi6) resolve(pos int) string {
+	part, ok := b.targets[pos]
+	if !ok {
+		return ""
+	}
+	u := gurl.URL{Path: partHref(part), Fragment: "filepos" + strconv.Itoa(pos)}
+	return u.String()
+}
+
+// Renders the parts as XHTML documents, decoding their text with [decode].
+func (b *mobi6) documents(decode func([]byte) string, images map[int]resource, title string, lang string) []resource {
+	rewrite := func(n *nhtml.Node) {
+		if pos, ok := takeAttr(n, "filepos"); ok && n.Data == "a" {
+			if p, err := strconv.Atoi(strings.TrimSpace(pos)); err == nil {
+				if href := b.resolve(p); href != "" {
+					n.Attr = append(n.Attr, nhtml.Attribute{Key: "href", Val: strings.TrimPrefix(href, "text/")})
+				}
+			}
+		}
+		if index, ok := takeAttr(n, "recindex"); ok && n.Data == "img" {
+			if i, err := strconv.Atoi(index); err == nil {
+				if img, ok := images[i]; ok {
+					n.Attr = append(n.Attr, nhtml.Attribute{Key: "src", Val: "../" + img.Href})
+				}
+			}
+		}
+		takeAttr(n, "hirecindex")
+		takeAttr(n, "lorecindex")
+	}
+
+	var docs []resource
+	for i, part := range b.parts {
+		doc, err := nhtml.Parse(strings.NewReader(decode(part)))
+		if err != nil {
+			continue
+		}
+		docs = append(docs, resource{
+			Href:      partHref(i),
+			MediaType: &mediatype.XHTML,
+			Data:      renderXHTML(doc, title, lang, rewrite),
+		})
+	}
+	return docs
+}
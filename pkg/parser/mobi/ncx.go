@@ -0,0 +1,72 @@
+package mobi
+
+import (
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/manifest"
+)
+
+// Entry of the NCX index, holding the table of contents of the book.
+type ncxEntry struct {
+	Title  string
+	Parent int // Index of the parent entry, or -1
+	Pos    int // Position in the text of a MOBI 6 book
+	FID    int // Position in a KF8 book, as an offset from a fragment
+	Offset int
+}
+
+// Reads the NCX index of the book. A broken index doesn't prevent reading the book, so errors are
+// ignored.
+func readNCX(records [][]byte, h *header) []ncxEntry {
+	if h.NCXIndex == nullIndex {
+		return nil
+	}
+	entries, labels, err := readIndex(records, h.NCXIndex)
+	if err != nil {
+		return nil
+	}
+	var ncx []ncxEntry
+	for _, e := range entries {
+		ncx = append(ncx, ncxEntry{
+			Title:  strings.TrimSpace(h.decode([]byte(labels[e.Value(3, 0, -1)]))),
+			Parent: e.Value(21, 0, -1),
+			Pos:    e.Value(1, 0, -1),
+			FID:    e.Value(6, 0, -1),
+			Offset: e.Value(6, 1, 0),
+		})
+	}
+	return ncx
+}
+
+// Builds the table of contents from the NCX [entries], using [href] to resolve their targets.
+// Entries without a target are replaced by their children.
+func tableOfContents(entries []ncxEntry, href func(ncxEntry) string) manifest.LinkList {
+	children := make([][]int, len(entries))
+	var roots []int
+	for i, e := range entries {
+		if e.Parent >= 0 && e.Parent < i {
+			children[e.Parent] = append(children[e.Parent], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var build func(indexes []int) manifest.LinkList
+	build = func(indexes []int) manifest.LinkList {
+		var links manifest.LinkList
+		for _, i := range indexes {
+			target := href(entries[i])
+			if target == "" {
+				links = append(links, build(children[i])...)
+				continue
+			}
+			links = append(links, manifest.Link{
+				Href:     manifest.MustNewHREFFromString(target, false),
+				Title:    entries[i].Title,
+				Children: build(children[i]),
+			})
+		}
+		return links
+	}
+	return build(roots)
+}
@@ -0,0 +1,98 @@
+package mobi
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/parser/epub"
+	"github.com/readium/go-toolkit/pkg/pub"
+)
+
+// Handles parsing of unencrypted Mobipocket (MOBI) and Kindle Format 8 (AZW3) books.
+// The text of the book is converted to XHTML documents, which are served along with the images
+// and style sheets by a fetcher replacing the original one.
+type Parser struct{}
+
+func NewParser() Parser {
+	return Parser{}
+}
+
+// Parse implements PublicationParser
+func (p Parser) Parse(asset asset.PublicationAsset, f fetcher.Fetcher) (*pub.Builder, error) {
+	mt := asset.MediaType()
+	if !mt.Matches(&mediatype.MOBI, &mediatype.AZW3) {
+		return nil, nil
+	}
+
+	links, err := f.Links()
+	if err != nil {
+		return nil, err
+	}
+	var link *manifest.Link
+	for _, l := range links {
+		if !extensions.IsHiddenOrThumbs(l.URL(nil, nil).Path()) {
+			link = &l
+			break
+		}
+	}
+	if link == nil {
+		return nil, errors.New("no MOBI file found in the publication")
+	}
+	data, rerr := f.Get(*link).Read(0, 0)
+	if rerr != nil {
+		return nil, errors.Wrap(rerr.Cause, "failed reading MOBI file")
+	}
+	records, err := readRecords(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading MOBI file")
+	}
+	b, err := readBook(records, strings.TrimSuffix(asset.Name(), path.Ext(asset.Name())))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading MOBI book")
+	}
+	if len(b.Documents) == 0 {
+		return nil, errors.New("MOBI book has no text")
+	}
+
+	bf := fetcher.NewBytesFetcher()
+	add := func(r resource) manifest.Link {
+		link := manifest.Link{
+			Href:      manifest.MustNewHREFFromString(r.Href, false),
+			MediaType: r.MediaType,
+		}
+		if r.Href == b.Cover {
+			link.Rels = manifest.Strings{"cover"}
+		}
+		bf.Add(link, func() []byte {
+			return r.Data
+		})
+		return link
+	}
+	var readingOrder, resources manifest.LinkList
+	for _, r := range b.Documents {
+		readingOrder = append(readingOrder, add(r))
+	}
+	for _, r := range b.Resources {
+		resources = append(resources, add(r))
+	}
+	f.Close() // The whole book is in memory from now on.
+
+	m := manifest.Manifest{
+		Context:         manifest.Strings{manifest.WebpubManifestContext},
+		Metadata:        b.Metadata,
+		ReadingOrder:    readingOrder,
+		Resources:       resources,
+		TableOfContents: b.TOC,
+	}
+
+	builder := pub.NewServicesBuilder(map[string]pub.ServiceFactory{
+		pub.PositionsService_Name: epub.PositionsServiceFactory(epub.OriginalLength{PageLength: 1024}),
+	})
+	return pub.NewBuilder(m, bf, builder), nil
+}
@@ -0,0 +1,131 @@
+package mobi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/stretchr/testify/assert"
+)
+
+func withParser(t *testing.T, filepath string, f func(*pub.Publication)) {
+	a := asset.File(filepath)
+	fet, err := a.CreateFetcher(asset.Dependencies{
+		ArchiveFactory: archive.NewArchiveFactory(),
+	}, "")
+	assert.NoError(t, err)
+	p, err := NewParser().Parse(a, fet)
+	if assert.NoError(t, err) && assert.NotNil(t, p) {
+		f(p.Build())
+	}
+}
+
+func hrefs(links manifest.LinkList) []string {
+	res := make([]string, 0, len(links))
+	for _, l := range links {
+		res = append(res, l.Title+" "+l.Href.String())
+	}
+	return res
+}
+
+func readString(t *testing.T, p *pub.Publication, href string) string {
+	data, err := p.Get(manifest.Link{Href: manifest.MustNewHREFFromString(href, false)}).Read(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestKF8Metadata(t *testing.T) {
+	withParser(t, "./testdata/book.azw3", func(p *pub.Publication) {
+		m := p.Manifest.Metadata
+		assert.Equal(t, "A Kindle Book", m.Title())
+		if assert.Len(t, m.Authors, 2) {
+			assert.Equal(t, "Jane Doe", m.Authors[0].Name())
+			assert.Equal(t, "John Smith", m.Authors[1].Name())
+		}
+		assert.Equal(t, "Acme", m.Publishers[0].Name())
+		assert.Equal(t, "urn:isbn:9783161484100", m.Identifier)
+		assert.Equal(t, manifest.Strings{"en"}, m.Languages)
+		assert.Equal(t, time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC), *m.Published)
+		assert.Equal(t, "Fiction", m.Subjects[0].Name())
+	})
+}
+
+func TestKF8Parts(t *testing.T) {
+	withParser(t, "./testdata/book.azw3", func(p *pub.Publication) {
+		assert.Equal(t, []string{
+			" text/part0000.xhtml",
+			" text/part0001.xhtml",
+		}, hrefs(p.Manifest.ReadingOrder))
+
+		part := readString(t, p, "text/part0000.xhtml")
+		assert.Contains(t, part, `<link rel="stylesheet" type="text/css" href="../styles/flow0001.css"/>`)
+		assert.Contains(t, part, `<body aid="0"><h1 id="aid-1" aid="1">Chapter One</h1>`)
+		assert.Contains(t, part, `<img src="../images/image-0001.png" alt="pic"/>`)
+		assert.Contains(t, part, `<a href="part0001.xhtml#two">chapter two</a>`)
+		assert.Contains(t, readString(t, p, "text/part0001.xhtml"), `<h1 id="two" aid="4">Chapter Two</h1><p id="aid-5" aid="5">The end, <a href="part0000.xhtml#aid-1">back</a>.</p></body>`)
+
+		for _, link := range p.Manifest.ReadingOrder {
+			assert.Equal(t, &mediatype.XHTML, link.MediaType)
+			_, err := p.Get(link).ReadAsXML(nil)
+			assert.Nil(t, err, "%s is not well-formed", link.Href)
+		}
+		assert.Len(t, p.Positions(), 2)
+	})
+}
+
+func TestKF8Resources(t *testing.T) {
+	withParser(t, "./testdata/book.azw3", func(p *pub.Publication) {
+		assert.Equal(t, []string{
+			" styles/flow0001.css",
+			" images/image-0001.png",
+			" images/image-0002.jpg",
+		}, hrefs(p.Manifest.Resources))
+		assert.Equal(t, manifest.Strings{"cover"}, p.Manifest.Resources[1].Rels)
+		assert.Equal(t, &mediatype.JPEG, p.Manifest.Resources[2].MediaType)
+		assert.Contains(t, readString(t, p, "styles/flow0001.css"), "url(../images/image-0002.jpg)")
+		assert.Equal(t, "\x89PNG", readString(t, p, "images/image-0001.png")[:4])
+	})
+}
+
+func TestKF8TableOfContents(t *testing.T) {
+	withParser(t, "./testdata/book.azw3", func(p *pub.Publication) {
+		toc := p.Manifest.TableOfContents
+		assert.Equal(t, []string{
+			"Chapter One text/part0000.xhtml#aid-1",
+			"Chapter Two text/part0001.xhtml#two",
+		}, hrefs(toc))
+		assert.Equal(t, []string{"The End text/part0001.xhtml#aid-5"}, hrefs(toc[1].Children))
+	})
+}
+
+func TestMOBI6(t *testing.T) {
+	withParser(t, "./testdata/book.mobi", func(p *pub.Publication) {
+		assert.Equal(t, "Legacy Book", p.Manifest.Metadata.Title())
+		assert.Equal(t, []string{
+			" text/part0000.xhtml",
+			" text/part0001.xhtml",
+		}, hrefs(p.Manifest.ReadingOrder))
+
+		toc := hrefs(p.Manifest.TableOfContents)
+		if assert.Len(t, toc, 2) {
+			assert.Regexp(t, `^Chapter One text/part0000\.xhtml#filepos\d+$`, toc[0])
+			assert.Regexp(t, `^Chapter Two text/part0001\.xhtml#filepos\d+$`, toc[1])
+		}
+
+		part := readString(t, p, "text/part0000.xhtml")
+		assert.Contains(t, part, "<p>Café &amp; crème <a href=\"part0001.xhtml#filepos")
+		assert.Contains(t, part, `<img src="../images/image-0001.png"/>`)
+		assert.NotContains(t, part, "guide")
+		assert.Contains(t, readString(t, p, "text/part0001.xhtml"), "<p>The end.</p>")
+		for _, link := range p.Manifest.ReadingOrder {
+			_, err := p.Get(link).ReadAsXML(nil)
+			assert.Nil(t, err, "%s is not well-formed", link.Href)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package mobi
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+const pdbHeaderLength = 78
+
+// Splits a Palm Database into its records.
+// Reference: https://wiki.mobileread.com/wiki/PDB
+func readRecords(data []byte) ([][]byte, error) {
+	if len(data) < pdbHeaderLength {
+		return nil, errors.New("truncated Palm Database header")
+	}
+	if string(data[60:68]) != "BOOKMOBI" {
+		return nil, errors.Errorf("unsupported Palm Database type %q", data[60:68])
+	}
+	count := int(binary.BigEndian.Uint16(data[76:78]))
+	if len(data) < pdbHeaderLength+count*8 {
+		return nil, errors.New("truncated Palm Database record list")
+	}
+
+	offsets := make([]int, count+1)
+	for i := 0; i < count; i++ {
+		offsets[i] = int(binary.BigEndian.Uint32(data[pdbHeaderLength+i*8:]))
+	}
+	offsets[count] = len(data)
+
+	records := make([][]byte, count)
+	for i := 0; i < count; i++ {
+		start, end := offsets[i], offsets[i+1]
+		if start > end || end > len(data) {
+			return nil, errors.Errorf("invalid offset of Palm Database record %d", i)
+		}
+		records[i] = data[start:end]
+	}
+	return records, nil
+}
+
+// Reads a big-endian unsigned integer of [size] bytes at [offset] in [data], or 0 when out of bounds.
+func readUint(data []byte, offset int, size int) uint32 {
+	if offset < 0 || offset+size > len(data) {
+		return 0
+	}
+	switch size {
+	case 1:
+		return uint32(data[offset])
+	case 2:
+		return uint32(binary.BigEndian.Uint16(data[offset:]))
+	default:
+		return binary.BigEndian.Uint32(data[offset:])
+	}
+}
@@ -0,0 +1,45 @@
+package mobi
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Reads and decompresses the text records of the book, following its header record.
+func readText(records [][]byte, h *header) ([]byte, error) {
+	var decompress func([]byte) ([]byte, error)
+	switch h.Compression {
+	case compressionNone:
+		decompress = func(data []byte) ([]byte, error) {
+			return data, nil
+		}
+	case compressionPalmDOC:
+		decompress = func(data []byte) ([]byte, error) {
+			return decompressPalmDOC(data), nil
+		}
+	case compressionHuffCDIC:
+		if h.HuffRecord == nullIndex || h.HuffRecord+h.HuffCount > len(records) {
+			return nil, errors.New("missing HUFF/CDIC records")
+		}
+		huff, err := newHuffCDIC(records[h.HuffRecord : h.HuffRecord+h.HuffCount])
+		if err != nil {
+			return nil, err
+		}
+		decompress = huff.Decompress
+	default:
+		return nil, errors.Errorf("unsupported MOBI compression %d", h.Compression)
+	}
+
+	var text []byte
+	for i := 1; i <= h.TextRecords && i < len(records); i++ {
+		record := records[i]
+		data, err := decompress(record[:len(record)-trailingEntriesSize(record, h.ExtraFlags)])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed decompressing text record %d", i)
+		}
+		text = append(text, data...)
+	}
+	if h.TextLength > 0 && len(text) > h.TextLength {
+		text = text[:h.TextLength]
+	}
+	return text, nil
+}
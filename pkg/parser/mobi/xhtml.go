@@ -0,0 +1,100 @@
+package mobi
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	nhtml "golang.org/x/net/html"
+)
+
+var xmlNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_.-]*$`)
+
+// Elements serialized as empty tags.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// Elements dropped with their content.
+var droppedElements = map[string]bool{
+	"guide": true, "head": true, "script": true,
+}
+
+// Renders the <body> of the HTML [doc] as a well-formed XHTML document. The elements are
+// passed to [rewrite] before being serialized, to update their attributes.
+func renderXHTML(doc *nhtml.Node, title string, lang string, rewrite func(*nhtml.Node)) []byte {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString("<!DOCTYPE html>\n")
+	sb.WriteString(`<html xmlns="http://www.w3.org/1999/xhtml"`)
+	if lang != "" {
+		sb.WriteString(` lang="` + html.EscapeString(lang) + `" xml:lang="` + html.EscapeString(lang) + `"`)
+	}
+	sb.WriteString(">\n<head>\n<title>" + html.EscapeString(title) + "</title>\n</head>\n<body>\n")
+	if body := findElement(doc, "body"); body != nil {
+		for c := body.FirstChild; c != nil; c = c.NextSibling {
+			writeNode(&sb, c, rewrite)
+		}
+	}
+	sb.WriteString("\n</body>\n</html>\n")
+	return []byte(sb.String())
+}
+
+func writeNode(sb *strings.Builder, n *nhtml.Node, rewrite func(*nhtml.Node)) {
+	switch n.Type {
+	case nhtml.TextNode:
+		sb.WriteString(html.EscapeString(n.Data))
+	case nhtml.ElementNode:
+		if droppedElements[n.Data] {
+			return
+		}
+		rewrite(n)
+		// Proprietary elements such as <mbp:nu> are unwrapped.
+		if !xmlNamePattern.MatchString(n.Data) {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				writeNode(sb, c, rewrite)
+			}
+			return
+		}
+		sb.WriteString("<" + n.Data)
+		for _, a := range n.Attr {
+			if a.Namespace != "" || !xmlNamePattern.MatchString(a.Key) {
+				continue
+			}
+			sb.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+		}
+		if voidElements[n.Data] {
+			sb.WriteString("/>")
+			return
+		}
+		sb.WriteString(">")
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			writeNode(sb, c, rewrite)
+		}
+		sb.WriteString("</" + n.Data + ">")
+	}
+}
+
+func findElement(n *nhtml.Node, name string) *nhtml.Node {
+	if n.Type == nhtml.ElementNode && n.Data == name {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Returns the value of the attribute [key] of [n], and removes it.
+func takeAttr(n *nhtml.Node, key string) (string, bool) {
+	for i, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			n.Attr = append(n.Attr[:i], n.Attr[i+1:]...)
+			return a.Val, true
+		}
+	}
+	return "", false
+}
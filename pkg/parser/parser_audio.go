@@ -59,7 +59,7 @@ func (p AudioParser) Parse(asset asset.PublicationAsset, fetcher fetcher.Fetcher
 		title = asset.Name()
 	}
 
-	manifest := manifest.Manifest{
+	manifest := &manifest.Manifest{
 		Context: manifest.Strings{manifest.WebpubManifestContext},
 		Metadata: manifest.Metadata{
 			LocalizedTitle: manifest.NewLocalizedStringFromString(title),
@@ -68,7 +68,22 @@ func (p AudioParser) Parse(asset asset.PublicationAsset, fetcher fetcher.Fetcher
 		ReadingOrder: readingOrder,
 	}
 
-	return pub.NewBuilder(manifest, fetcher, nil), nil // TODO services!
+	// Metadata embedded in the audio files: ID3, MP4 atoms or Vorbis comments
+	tracks := readAudioTracks(fetcher, readingOrder)
+
+	// A playlist defines the order of the tracks, otherwise they're sorted by track number.
+	tracks, ok := applyAudioPlaylist(fetcher, links, tracks)
+	if !ok {
+		sortAudioTracks(tracks)
+	}
+	if cover := applyAudioMetadata(manifest, tracks, links); cover != nil {
+		fetcher = withAudioCover(fetcher, cover, manifest.Resources[len(manifest.Resources)-1])
+	}
+
+	builder := pub.NewServicesBuilder(map[string]pub.ServiceFactory{
+		pub.PositionsService_Name: pub.AudioPositionsServiceFactory(pub.DefaultAudioPositionDuration),
+	})
+	return pub.NewBuilder(*manifest, fetcher, builder), nil
 }
 
 var allowed_extensions_audio_extra = map[string]struct{}{
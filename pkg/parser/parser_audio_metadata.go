@@ -0,0 +1,245 @@
+package parser
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/parser/audio"
+	"github.com/readium/go-toolkit/pkg/util/url"
+)
+
+// Base name of the cover art extracted from the audio files.
+const audioCoverName = "cover"
+
+// An audio file of the reading order, with its embedded metadata.
+type audioTrack struct {
+	link     manifest.Link
+	metadata *audio.Metadata // nil when the file couldn't be read
+}
+
+func readAudioTracks(f fetcher.Fetcher, links manifest.LinkList) []audioTrack {
+	tracks := make([]audioTrack, 0, len(links))
+	for _, link := range links {
+		resource := f.Get(link)
+		r := fetcher.NewResourceReadSeeker(resource)
+		md, err := audio.ReadWithOptions(r, audio.ReadOptions{
+			// Reading the end of a compressed entry requires inflating the whole track.
+			SkipTail: link.Duration > 0 || isCompressedEntry(resource),
+		})
+		r.Close()
+		if err != nil {
+			md = nil // TODO log
+		}
+		tracks = append(tracks, audioTrack{link: link, metadata: md})
+	}
+	return tracks
+}
+
+// Returns whether the [resource] is a compressed entry of an archive.
+func isCompressedEntry(resource fetcher.Resource) bool {
+	props := resource.Properties()
+	if p, ok := props.Get("https://readium.org/webpub-manifest/properties#archive").(map[string]interface{}); ok {
+		compressed, _ := p["isEntryCompressed"].(bool)
+		return compressed
+	}
+	return false
+}
+
+// Sorts the tracks by disc and track number, when all of them are numbered.
+// The original order is kept otherwise.
+func sortAudioTracks(tracks []audioTrack) {
+	for _, t := range tracks {
+		if t.metadata == nil || t.metadata.Track == 0 {
+			return
+		}
+	}
+	sort.SliceStable(tracks, func(i, j int) bool {
+		a, b := tracks[i].metadata, tracks[j].metadata
+		if a.Disc != b.Disc {
+			return a.Disc < b.Disc
+		}
+		return a.Track < b.Track
+	})
+}
+
+// Fills the [manifest] with the metadata embedded in the audio [tracks], and returns the embedded
+// cover art if any. The existing [links] of the publication are used to pick an unused HREF for
+// the cover.
+func applyAudioMetadata(m *manifest.Manifest, tracks []audioTrack, links manifest.LinkList) *audio.Picture {
+	var cover *audio.Picture
+	var duration float64
+	durationKnown := true
+	m.ReadingOrder = make(manifest.LinkList, 0, len(tracks))
+	var toc manifest.LinkList
+	hasChapters := false
+
+	for _, t := range tracks {
+		link := t.link
+		md := t.metadata
+		if md != nil {
+			// Titles from a playlist take precedence, but the embedded durations are more accurate.
+			if link.Title == "" {
+				link.Title = md.Title
+			}
+			if md.Duration > 0 {
+				link.Duration = md.Duration
+			}
+		}
+		if link.Duration > 0 {
+			duration += link.Duration
+		} else {
+			durationKnown = false
+		}
+		m.ReadingOrder = append(m.ReadingOrder, link)
+
+		if md == nil {
+			if link.Title != "" {
+				toc = append(toc, manifest.Link{
+					Href:  link.Href,
+					Title: link.Title,
+				})
+			}
+			continue
+		}
+		if cover == nil {
+			cover = md.Cover
+		}
+
+		// Chapters are targeted with a media fragment, e.g. track.m4b#t=120
+		if len(md.Chapters) > 0 {
+			hasChapters = true
+			for i, c := range md.Chapters {
+				title := c.Title
+				if title == "" {
+					title = "Chapter " + strconv.Itoa(i+1)
+				}
+				toc = append(toc, manifest.Link{
+					Href:  timeFragmentHref(link, c.Start),
+					Title: title,
+				})
+			}
+		} else if link.Title != "" {
+			toc = append(toc, manifest.Link{
+				Href:  link.Href,
+				Title: link.Title,
+			})
+		}
+	}
+	if hasChapters || len(tracks) > 1 {
+		m.TableOfContents = toc
+	}
+	if durationKnown && duration > 0 {
+		m.Metadata.Duration = &duration
+	}
+
+	// Publication-wide metadata is taken from the first track providing it
+	metadata := &m.Metadata
+	var title string
+	for _, t := range tracks {
+		md := t.metadata
+		if md == nil {
+			continue
+		}
+		if title == "" {
+			title = md.Album
+		}
+		if len(metadata.Authors) == 0 {
+			names := md.AlbumArtists
+			if len(names) == 0 {
+				names = md.Artists
+			}
+			metadata.Authors = contributorsFromNames(names)
+		}
+		if len(metadata.Narrators) == 0 {
+			// Composers are commonly used for the narrators of audiobooks.
+			names := md.Narrators
+			if len(names) == 0 {
+				names = md.Composers
+			}
+			metadata.Narrators = contributorsFromNames(names)
+		}
+		if len(metadata.Subjects) == 0 {
+			for _, genre := range md.Genres {
+				metadata.Subjects = append(metadata.Subjects, manifest.Subject{
+					LocalizedName: manifest.NewLocalizedStringFromString(genre),
+				})
+			}
+		}
+		if metadata.Published == nil {
+			metadata.Published = extensions.ParseDate(md.Date)
+		}
+	}
+	if title == "" && len(tracks) == 1 && tracks[0].metadata != nil {
+		title = tracks[0].metadata.Title
+	}
+	if title != "" {
+		metadata.LocalizedTitle = manifest.NewLocalizedStringFromString(title)
+	}
+
+	if cover != nil {
+		link := manifest.Link{
+			Rels:      manifest.Strings{"cover"},
+			MediaType: mediatype.OfString(cover.MediaType),
+		}
+		if link.MediaType == nil {
+			if mt, err := mediatype.NewOfString(cover.MediaType); err == nil {
+				link.MediaType = &mt
+			}
+		}
+		link.Href = audioCoverHref(link.MediaType, links)
+		m.Resources = append(m.Resources, link)
+	}
+	return cover
+}
+
+// Returns an HREF for the embedded cover art at the root of the publication, e.g. cover.jpeg,
+// which doesn't collide with the existing [links].
+func audioCoverHref(mt *mediatype.MediaType, links manifest.LinkList) manifest.HREF {
+	var ext string
+	if mt != nil && mt.FileExtension() != "" {
+		ext = "." + mt.FileExtension()
+	}
+	taken := make(map[string]bool, len(links))
+	for _, l := range links {
+		taken[l.URL(nil, nil).Path()] = true
+	}
+	name := audioCoverName + ext
+	for i := 2; taken[name]; i++ {
+		name = audioCoverName + "-" + strconv.Itoa(i) + ext
+	}
+	return manifest.MustNewHREFFromString(name, false)
+}
+
+func timeFragmentHref(link manifest.Link, start float64) manifest.HREF {
+	u, err := url.URLFromString(link.Href.String() + "#t=" + strconv.FormatFloat(start, 'f', -1, 64))
+	if err != nil {
+		return link.Href
+	}
+	return manifest.NewHREF(u)
+}
+
+func contributorsFromNames(names []string) manifest.Contributors {
+	var contributors manifest.Contributors
+	for _, name := range names {
+		contributors = append(contributors, manifest.Contributor{
+			LocalizedName: manifest.NewLocalizedStringFromString(name),
+		})
+	}
+	return contributors
+}
+
+// Serves the cover art embedded in the audio files at the HREF of its [link].
+func withAudioCover(f fetcher.Fetcher, cover *audio.Picture, link manifest.Link) fetcher.Fetcher {
+	return fetcher.NewTransformingFetcher(f, func(resource fetcher.Resource) fetcher.Resource {
+		if resource.Link().Href.String() != link.Href.String() {
+			return resource
+		}
+		return fetcher.NewBytesResource(link, func() []byte {
+			return cover.Data
+		})
+	})
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/parser/audio"
+)
+
+// Reorders the audio [tracks] according to the first playlist of the publication referencing
+// them, and sets their titles and durations from the playlist entries. Tracks missing from the
+// playlist are kept at the end, in their original order.
+// Returns false if no such playlist was found.
+func applyAudioPlaylist(f fetcher.Fetcher, links manifest.LinkList, tracks []audioTrack) ([]audioTrack, bool) {
+	for _, link := range audioPlaylists(links) {
+		res := f.Get(link)
+		data, err := res.Read(0, 0)
+		res.Close()
+		if err != nil {
+			continue // TODO log
+		}
+		p := link.URL(nil, nil).Path()
+		entries, perr := audio.ParsePlaylist(p, data)
+		if perr != nil {
+			continue // TODO log
+		}
+		if ordered, ok := orderAudioTracks(tracks, path.Dir(p), entries); ok {
+			return ordered, true
+		}
+	}
+	return tracks, false
+}
+
+// Returns the playlists found among the [links], the shallowest first.
+func audioPlaylists(links manifest.LinkList) []manifest.Link {
+	var playlists []manifest.Link
+	for _, link := range links {
+		p := link.URL(nil, nil).Path()
+		if !extensions.IsHiddenOrThumbs(p) && audio.IsPlaylist(p) {
+			playlists = append(playlists, link)
+		}
+	}
+	sort.SliceStable(playlists, func(i, j int) bool {
+		a, b := playlists[i].URL(nil, nil).Path(), playlists[j].URL(nil, nil).Path()
+		if da, db := strings.Count(a, "/"), strings.Count(b, "/"); da != db {
+			return da < db
+		}
+		return a < b
+	})
+	return playlists
+}
+
+func orderAudioTracks(tracks []audioTrack, dir string, entries []audio.PlaylistEntry) ([]audioTrack, bool) {
+	ordered := make([]audioTrack, 0, len(tracks))
+	used := make([]bool, len(tracks))
+	for _, entry := range entries {
+		i := findPlaylistTrack(tracks, dir, entry.Location)
+		if i < 0 || used[i] {
+			continue
+		}
+		used[i] = true
+		track := tracks[i]
+		if entry.Title != "" {
+			track.link.Title = entry.Title
+		}
+		if entry.Duration > 0 {
+			track.link.Duration = entry.Duration
+		}
+		ordered = append(ordered, track)
+	}
+	if len(ordered) == 0 {
+		return nil, false
+	}
+	for i, track := range tracks {
+		if !used[i] {
+			ordered = append(ordered, track)
+		}
+	}
+	return ordered, true
+}
+
+// Returns the index of the track referenced by the playlist [location], relative to the playlist
+// directory [dir], or -1 if not found.
+func findPlaylistTrack(tracks []audioTrack, dir string, location string) int {
+	location = strings.ReplaceAll(location, "\\", "/")
+	candidates := []string{location}
+	if u, err := url.Parse(location); err == nil && len(u.Scheme) > 1 {
+		// e.g. file:///home/user/book/01.mp3, the length check excludes Windows drive letters.
+		candidates = []string{u.Path}
+	} else if unescaped, err := url.PathUnescape(location); err == nil && unescaped != location {
+		// XSPF locations are URI references.
+		candidates = append(candidates, unescaped)
+	}
+
+	for _, c := range candidates {
+		// Absolute paths, e.g. /home/user/book/01.mp3 or C:/Users/book/01.mp3
+		if strings.HasPrefix(c, "/") || (len(c) > 1 && c[1] == ':') {
+			continue
+		}
+		if i := findTrackByPath(tracks, path.Join(dir, c)); i >= 0 {
+			return i
+		}
+	}
+
+	// Falls back on the file name, if it's unambiguous.
+	name := path.Base(candidates[len(candidates)-1])
+	found := -1
+	for i, t := range tracks {
+		if strings.EqualFold(path.Base(t.link.URL(nil, nil).Path()), name) {
+			if found >= 0 {
+				return -1
+			}
+			found = i
+		}
+	}
+	return found
+}
+
+func findTrackByPath(tracks []audioTrack, p string) int {
+	p = strings.TrimPrefix(p, "/")
+	for i, t := range tracks {
+		if strings.EqualFold(strings.TrimPrefix(t.link.URL(nil, nil).Path(), "/"), p) {
+			return i
+		}
+	}
+	return -1
+}
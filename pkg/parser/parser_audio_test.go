@@ -0,0 +1,186 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/parser/audio"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/stretchr/testify/assert"
+)
+
+func withAudioParser(t *testing.T, filepath string, f func(*pub.Publication)) {
+	a := asset.File(filepath)
+	fet, err := a.CreateFetcher(asset.Dependencies{
+		ArchiveFactory: archive.NewArchiveFactory(),
+	}, "")
+	assert.NoError(t, err)
+	p, err := AudioParser{}.Parse(a, fet)
+	if assert.NoError(t, err) && assert.NotNil(t, p) {
+		f(p.Build())
+	}
+}
+
+func TestAudioEmbeddedMetadata(t *testing.T) {
+	withAudioParser(t, "./testdata/audio/audiobook.zab", func(p *pub.Publication) {
+		m := p.Manifest.Metadata
+		assert.Equal(t, "The Audio Book", m.Title())
+		assert.Equal(t, []string{"Ann Author"}, contributorNames(m.Authors))
+		assert.Equal(t, []string{"Nat Narrator"}, contributorNames(m.Narrators))
+		assert.Equal(t, "2020", m.Published.Format("2006"))
+		if assert.NotNil(t, m.Duration) {
+			assert.InDelta(t, 0.260625+100*1152/44100.0, *m.Duration, 0.0001)
+		}
+	})
+}
+
+func TestAudioReadingOrderFollowsTrackNumbers(t *testing.T) {
+	withAudioParser(t, "./testdata/audio/audiobook.zab", func(p *pub.Publication) {
+		ro := p.Manifest.ReadingOrder
+		if assert.Len(t, ro, 2) {
+			assert.Equal(t, "Audio%20Book/b.mp3", ro[0].Href.String())
+			assert.Equal(t, "Prologue", ro[0].Title)
+			assert.InDelta(t, 0.260625, ro[0].Duration, 0.0001)
+			assert.Equal(t, "Audio%20Book/a.mp3", ro[1].Href.String())
+			assert.Equal(t, "Chapter Ünë", ro[1].Title)
+		}
+	})
+}
+
+func TestAudioChaptersTableOfContents(t *testing.T) {
+	withAudioParser(t, "./testdata/audio/audiobook.zab", func(p *pub.Publication) {
+		toc := make([]string, 0, len(p.Manifest.TableOfContents))
+		for _, l := range p.Manifest.TableOfContents {
+			toc = append(toc, l.Title+" "+l.Href.String())
+		}
+		assert.Equal(t, []string{
+			"Prologue Audio%20Book/b.mp3",
+			"Intro Audio%20Book/a.mp3#t=0",
+			"Main Audio%20Book/a.mp3#t=1.5",
+		}, toc)
+	})
+}
+
+func TestAudioEmbeddedCover(t *testing.T) {
+	withAudioParser(t, "./testdata/audio/audiobook.zab", func(p *pub.Publication) {
+		cover := p.Manifest.Resources.FirstWithRel("cover")
+		if !assert.NotNil(t, cover) {
+			return
+		}
+		assert.Equal(t, "image/jpeg", cover.MediaType.String())
+		assert.Equal(t, "cover.jpeg", cover.Href.String())
+		data, err := p.Get(*cover).Read(0, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{0xFF, 0xD8, 0xFF}, data[:3])
+
+		// Other resources are still served
+		_, err = p.Get(p.Manifest.ReadingOrder[0]).Read(0, 3)
+		assert.Nil(t, err)
+	})
+}
+
+func TestAudioCoverHref(t *testing.T) {
+	links := manifest.LinkList{
+		{Href: manifest.MustNewHREFFromString("cover.jpeg", false)},
+		{Href: manifest.MustNewHREFFromString("cover-2.jpeg", false)},
+		{Href: manifest.MustNewHREFFromString("sub/cover-3.jpeg", false)},
+	}
+	assert.Equal(t, "cover-3.jpeg", audioCoverHref(&mediatype.JPEG, links).String())
+	assert.Equal(t, "cover.png", audioCoverHref(&mediatype.PNG, links).String())
+	assert.Equal(t, "cover", audioCoverHref(nil, links).String())
+}
+
+func TestAudioIsCompressedEntry(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, method := range map[string]uint16{"stored.mp3": zip.Store, "deflated.mp3": zip.Deflate} {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+		assert.NoError(t, err)
+		w.Write(bytes.Repeat([]byte("audio"), 100))
+	}
+	assert.NoError(t, zw.Close())
+	a, err := archive.NewArchiveFactory().OpenBytes(buf.Bytes(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	f := fetcher.NewArchiveFetcher(a)
+
+	assert.False(t, isCompressedEntry(f.Get(manifest.Link{Href: manifest.MustNewHREFFromString("stored.mp3", false)})))
+	assert.True(t, isCompressedEntry(f.Get(manifest.Link{Href: manifest.MustNewHREFFromString("deflated.mp3", false)})))
+	assert.False(t, isCompressedEntry(fetcher.NewBytesResource(manifest.Link{}, func() []byte { return nil })))
+}
+
+func TestAudioWithoutMetadata(t *testing.T) {
+	tracks := []audioTrack{{link: manifest.Link{Href: manifest.MustNewHREFFromString("a.wav", false)}}}
+	m := &manifest.Manifest{Metadata: manifest.Metadata{LocalizedTitle: manifest.NewLocalizedStringFromString("Title")}}
+	assert.Nil(t, applyAudioMetadata(m, tracks, nil))
+	assert.Equal(t, "Title", m.Metadata.Title())
+	assert.Nil(t, m.Metadata.Duration)
+	assert.Empty(t, m.TableOfContents)
+	assert.Len(t, m.ReadingOrder, 1)
+}
+
+func TestAudioPositions(t *testing.T) {
+	withAudioParser(t, "./testdata/audio/audiobook.zab", func(p *pub.Publication) {
+		positions := p.Positions()
+		if assert.Len(t, positions, 2) {
+			assert.Equal(t, "Prologue", positions[0].Title)
+			assert.Equal(t, []string{"t=0"}, positions[1].Locations.Fragments)
+			assert.Equal(t, "Intro", positions[1].Title)
+		}
+	})
+}
+
+func TestAudioReadingOrderFollowsPlaylist(t *testing.T) {
+	withAudioParser(t, "./testdata/audio/playlist.zab", func(p *pub.Publication) {
+		ro := p.Manifest.ReadingOrder
+		if assert.Len(t, ro, 2) {
+			assert.Equal(t, "Audio%20Book/a.mp3", ro[0].Href.String())
+			assert.Equal(t, "First Part", ro[0].Title)
+			assert.Equal(t, "Audio%20Book/b.mp3", ro[1].Href.String())
+			assert.Equal(t, "Second Part", ro[1].Title)
+			// Embedded durations are more accurate than the playlist ones.
+			assert.InDelta(t, 0.260625, ro[1].Duration, 0.0001)
+		}
+	})
+}
+
+func TestAudioPlaylistLocations(t *testing.T) {
+	track := func(href string) audioTrack {
+		return audioTrack{link: manifest.Link{Href: manifest.MustNewHREFFromString(href, false)}}
+	}
+	tracks := []audioTrack{
+		track("book/cd1/01%20intro.mp3"),
+		track("book/cd2/01.mp3"),
+		track("book/extra.mp3"),
+		track("book/cd1/02.mp3"),
+	}
+	ordered, ok := orderAudioTracks(tracks, "book", []audio.PlaylistEntry{
+		{Location: "cd2\\01.mp3"},
+		{Location: "file:///C:/Users/me/book/cd1/02.mp3", Title: "Two"},
+		{Location: "cd1/01%20intro.mp3"},
+		{Location: "missing.mp3"},
+	})
+	if assert.True(t, ok) {
+		hrefs := make([]string, 0, len(ordered))
+		for _, t := range ordered {
+			hrefs = append(hrefs, t.link.Href.String())
+		}
+		assert.Equal(t, []string{
+			"book/cd2/01.mp3",
+			"book/cd1/02.mp3",
+			"book/cd1/01%20intro.mp3",
+			"book/extra.mp3",
+		}, hrefs)
+		assert.Equal(t, "Two", ordered[1].link.Title)
+	}
+
+	_, ok = orderAudioTracks(tracks, "book", []audio.PlaylistEntry{{Location: "missing.mp3"}})
+	assert.False(t, ok)
+}
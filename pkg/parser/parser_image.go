@@ -33,7 +33,7 @@ func (p ImageParser) Parse(asset asset.PublicationAsset, fetcher fetcher.Fetcher
 		path := link.URL(nil, nil).Path()
 
 		// Filter out all irrelevant files
-		if extensions.IsHiddenOrThumbs(path) || !link.MediaType.IsBitmap() {
+		if extensions.IsHiddenOrThumbs(path) || link.MediaType == nil || !link.MediaType.IsBitmap() {
 			continue
 		}
 		readingOrder = append(readingOrder, link)
@@ -57,7 +57,7 @@ func (p ImageParser) Parse(asset asset.PublicationAsset, fetcher fetcher.Fetcher
 	// First valid resource is the cover.
 	readingOrder[0].Rels = []string{"cover"}
 
-	manifest := manifest.Manifest{
+	manifest := &manifest.Manifest{
 		Context: manifest.Strings{manifest.WebpubManifestContext},
 		Metadata: manifest.Metadata{
 			LocalizedTitle: manifest.NewLocalizedStringFromString(title),
@@ -66,10 +66,13 @@ func (p ImageParser) Parse(asset asset.PublicationAsset, fetcher fetcher.Fetcher
 		ReadingOrder: readingOrder,
 	}
 
+	// Metadata from a ComicInfo.xml or ACBF file, if any
+	parseComicSidecar(fetcher, links, manifest)
+
 	builder := pub.NewServicesBuilder(map[string]pub.ServiceFactory{
 		pub.PositionsService_Name: pub.PerResourcePositionsServiceFactory(mediatype.MustNewOfString("image/*")),
 	})
-	return pub.NewBuilder(manifest, fetcher, builder), nil
+	return pub.NewBuilder(*manifest, fetcher, builder), nil
 }
 
 var allowed_extensions_image = map[string]struct{}{"acbf": {}, "xml": {}, "txt": {}, "json": {}}
@@ -88,7 +91,7 @@ func (p ImageParser) accepts(asset asset.PublicationAsset, fetcher fetcher.Fetch
 		if extensions.IsHiddenOrThumbs(path) {
 			continue
 		}
-		if link.MediaType.IsBitmap() {
+		if link.MediaType != nil && link.MediaType.IsBitmap() {
 			continue
 		}
 		fext := filepath.Ext(strings.ToLower(path))
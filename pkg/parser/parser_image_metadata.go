@@ -0,0 +1,374 @@
+package parser
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/util/url"
+	"github.com/readium/xmlquery"
+)
+
+// Looks for a ComicInfo.xml or ACBF sidecar file in the [links] of a comic archive,
+// and fills the [manifest] with the metadata and table of contents it contains.
+// ComicInfo.xml takes precedence when both are available.
+func parseComicSidecar(f fetcher.Fetcher, links manifest.LinkList, m *manifest.Manifest) {
+	var comicInfo, acbf *manifest.Link
+	for i, link := range links {
+		p := link.URL(nil, nil).Path()
+		if extensions.IsHiddenOrThumbs(p) {
+			continue
+		}
+		name := strings.ToLower(path.Base(p))
+		if name == "comicinfo.xml" && (comicInfo == nil || depth(p) < depth(comicInfo.URL(nil, nil).Path())) {
+			comicInfo = &links[i]
+		} else if path.Ext(name) == ".acbf" && acbf == nil {
+			acbf = &links[i]
+		}
+	}
+
+	if comicInfo != nil {
+		if doc, err := f.Get(*comicInfo).ReadAsXML(nil); err == nil {
+			parseComicInfo(doc, m)
+			return
+		}
+	}
+	if acbf != nil {
+		if doc, err := f.Get(*acbf).ReadAsXML(nil); err == nil {
+			parseACBF(doc, acbf.URL(nil, nil), m)
+		}
+	}
+}
+
+func depth(p string) int {
+	return strings.Count(p, "/")
+}
+
+// Parses a ComicInfo.xml document, as written by ComicRack and most comic managers.
+// https://anansi-project.github.io/docs/comicinfo/documentation
+func parseComicInfo(doc *xmlquery.Node, m *manifest.Manifest) {
+	root := doc.SelectElement("/ComicInfo")
+	if root == nil {
+		return
+	}
+	text := func(name string) string {
+		if n := root.SelectElement(name); n != nil {
+			return strings.TrimSpace(n.InnerText())
+		}
+		return ""
+	}
+	md := &m.Metadata
+
+	series := text("Series")
+	number := text("Number")
+	if title := text("Title"); title != "" {
+		md.LocalizedTitle = manifest.NewLocalizedStringFromString(title)
+	} else if series != "" {
+		title = series
+		if number != "" {
+			title += " #" + number
+		}
+		md.LocalizedTitle = manifest.NewLocalizedStringFromString(title)
+	}
+	if series != "" {
+		md.BelongsTo = addSeries(md.BelongsTo, series, number)
+	}
+
+	md.Authors = append(md.Authors, splitContributors(text("Writer"))...)
+	md.Pencilers = append(md.Pencilers, splitContributors(text("Penciller"))...)
+	md.Inkers = append(md.Inkers, splitContributors(text("Inker"))...)
+	md.Colorists = append(md.Colorists, splitContributors(text("Colorist"))...)
+	md.Letterers = append(md.Letterers, splitContributors(text("Letterer"))...)
+	md.Artists = append(md.Artists, splitContributors(text("CoverArtist"))...)
+	md.Editors = append(md.Editors, splitContributors(text("Editor"))...)
+	md.Translators = append(md.Translators, splitContributors(text("Translator"))...)
+	md.Publishers = append(md.Publishers, splitContributors(text("Publisher"))...)
+	md.Imprints = append(md.Imprints, splitContributors(text("Imprint"))...)
+
+	if lang := text("LanguageISO"); lang != "" {
+		md.Languages = manifest.Strings{lang}
+	}
+	if summary := text("Summary"); summary != "" {
+		md.Description = summary
+	}
+	for _, genre := range strings.Split(text("Genre"), ",") {
+		if genre = strings.TrimSpace(genre); genre != "" {
+			md.Subjects = append(md.Subjects, manifest.Subject{
+				LocalizedName: manifest.NewLocalizedStringFromString(genre),
+			})
+		}
+	}
+	if year, err := strconv.Atoi(text("Year")); err == nil && year > 0 {
+		month, err := strconv.Atoi(text("Month"))
+		if err != nil || month < 1 || month > 12 {
+			month = 1
+		}
+		day, err := strconv.Atoi(text("Day"))
+		if err != nil || day < 1 || day > 31 {
+			day = 1
+		}
+		published := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		md.Published = &published
+	}
+	switch text("Manga") {
+	case "Yes", "YesAndRightToLeft":
+		md.ReadingProgression = manifest.RTL
+	}
+
+	// Page types and bookmarks, the image index being the position in the reading order
+	lastType := ""
+	for _, page := range root.SelectElements("Pages/Page") {
+		index, err := strconv.Atoi(page.SelectAttr("Image"))
+		if err != nil || index < 0 || index >= len(m.ReadingOrder) {
+			continue
+		}
+		typ := page.SelectAttr("Type")
+		if typ == "FrontCover" {
+			setCover(m.ReadingOrder, index)
+		}
+
+		title := strings.TrimSpace(page.SelectAttr("Bookmark"))
+		if title == "" && typ != lastType {
+			title = comicPageTypeTitles[typ]
+		}
+		lastType = typ
+		if title != "" {
+			m.TableOfContents = append(m.TableOfContents, manifest.Link{
+				Href:  m.ReadingOrder[index].Href,
+				Title: title,
+			})
+		}
+	}
+}
+
+// Titles of the ComicInfo page types worth a table of contents entry, when the page has no bookmark.
+var comicPageTypeTitles = map[string]string{
+	"FrontCover": "Cover",
+	"InnerCover": "Inner Cover",
+	"Roundup":    "Roundup",
+	"Editorial":  "Editorial",
+	"Letters":    "Letters",
+	"Preview":    "Preview",
+	"BackCover":  "Back Cover",
+}
+
+// Parses an ACBF (Advanced Comic Book Format) document located at [base].
+// https://acbf.fandom.com/wiki/Advanced_Comic_Book_Format_Wiki
+func parseACBF(doc *xmlquery.Node, base url.URL, m *manifest.Manifest) {
+	root := doc.SelectElement("/" + localName("ACBF"))
+	if root == nil {
+		return
+	}
+	md := &m.Metadata
+
+	if info := root.SelectElement(localName("meta-data") + "/" + localName("book-info")); info != nil {
+		if title := info.SelectElement(localName("book-title")); title != nil {
+			if t := strings.TrimSpace(title.InnerText()); t != "" {
+				md.LocalizedTitle = manifest.NewLocalizedStringFromString(t)
+			}
+		}
+
+		for _, author := range info.SelectElements(localName("author")) {
+			c := acbfContributor(author)
+			if c == nil {
+				continue
+			}
+			switch author.SelectAttr("activity") {
+			case "Writer", "Adapter":
+				md.Authors = append(md.Authors, *c)
+			case "Penciller":
+				md.Pencilers = append(md.Pencilers, *c)
+			case "Inker":
+				md.Inkers = append(md.Inkers, *c)
+			case "Colorist":
+				md.Colorists = append(md.Colorists, *c)
+			case "Letterer":
+				md.Letterers = append(md.Letterers, *c)
+			case "Artist", "CoverArtist":
+				md.Artists = append(md.Artists, *c)
+			case "Editor":
+				md.Editors = append(md.Editors, *c)
+			case "Translator":
+				md.Translators = append(md.Translators, *c)
+			default:
+				md.Contributors = append(md.Contributors, *c)
+			}
+		}
+
+		for _, sequence := range info.SelectElements(localName("sequence")) {
+			if title := strings.TrimSpace(sequence.SelectAttr("title")); title != "" {
+				md.BelongsTo = addSeries(md.BelongsTo, title, strings.TrimSpace(sequence.InnerText()))
+			}
+		}
+
+		for _, genre := range info.SelectElements(localName("genre")) {
+			if g := strings.TrimSpace(genre.InnerText()); g != "" {
+				md.Subjects = append(md.Subjects, manifest.Subject{
+					LocalizedName: manifest.NewLocalizedStringFromString(g),
+				})
+			}
+		}
+
+		if annotation := info.SelectElement(localName("annotation")); annotation != nil {
+			var paragraphs []string
+			for _, p := range annotation.SelectElements(localName("p")) {
+				if t := strings.TrimSpace(p.InnerText()); t != "" {
+					paragraphs = append(paragraphs, t)
+				}
+			}
+			if len(paragraphs) == 0 {
+				if t := strings.TrimSpace(annotation.InnerText()); t != "" {
+					paragraphs = append(paragraphs, t)
+				}
+			}
+			if len(paragraphs) > 0 {
+				md.Description = strings.Join(paragraphs, "\n")
+			}
+		}
+
+		for _, layer := range info.SelectElements(localName("languages") + "/" + localName("text-layer")) {
+			if lang := layer.SelectAttr("lang"); lang != "" && !extensions.Contains(md.Languages, lang) {
+				md.Languages = append(md.Languages, lang)
+			}
+		}
+
+		if direction := info.SelectElement(localName("reading-direction")); direction != nil {
+			if strings.EqualFold(strings.TrimSpace(direction.InnerText()), "RTL") {
+				md.ReadingProgression = manifest.RTL
+			}
+		}
+
+		if image := info.SelectElement(localName("coverpage") + "/" + localName("image")); image != nil {
+			if index := indexOfImage(m.ReadingOrder, base, image.SelectAttr("href")); index >= 0 {
+				setCover(m.ReadingOrder, index)
+			}
+		}
+	}
+
+	if publish := root.SelectElement(localName("meta-data") + "/" + localName("publish-info")); publish != nil {
+		if publisher := publish.SelectElement(localName("publisher")); publisher != nil {
+			md.Publishers = append(md.Publishers, splitContributors(publisher.InnerText())...)
+		}
+		if date := publish.SelectElement(localName("publish-date")); date != nil {
+			if t := extensions.ParseDate(date.SelectAttr("value")); t != nil {
+				md.Published = t
+			}
+		}
+	}
+
+	// Pages with a title start a new entry in the table of contents
+	for _, page := range root.SelectElements(localName("body") + "/" + localName("page")) {
+		title := page.SelectElement(localName("title"))
+		image := page.SelectElement(localName("image"))
+		if title == nil || image == nil {
+			continue
+		}
+		t := strings.TrimSpace(title.InnerText())
+		index := indexOfImage(m.ReadingOrder, base, image.SelectAttr("href"))
+		if t == "" || index < 0 {
+			continue
+		}
+		m.TableOfContents = append(m.TableOfContents, manifest.Link{
+			Href:  m.ReadingOrder[index].Href,
+			Title: t,
+		})
+	}
+}
+
+// ACBF elements are namespaced, with a namespace depending on the version of the format.
+func localName(name string) string {
+	return "*[local-name()='" + name + "']"
+}
+
+func acbfContributor(author *xmlquery.Node) *manifest.Contributor {
+	var parts []string
+	for _, name := range []string{"first-name", "middle-name", "last-name"} {
+		if n := author.SelectElement(localName(name)); n != nil {
+			if t := strings.TrimSpace(n.InnerText()); t != "" {
+				parts = append(parts, t)
+			}
+		}
+	}
+	if len(parts) == 0 {
+		if n := author.SelectElement(localName("nickname")); n != nil {
+			if t := strings.TrimSpace(n.InnerText()); t != "" {
+				parts = append(parts, t)
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return &manifest.Contributor{
+		LocalizedName: manifest.NewLocalizedStringFromString(strings.Join(parts, " ")),
+	}
+}
+
+// Returns the index of the reading order item targeted by an image [href] relative to [base],
+// or -1. Images embedded in the ACBF document (#id) are not supported.
+func indexOfImage(readingOrder manifest.LinkList, base url.URL, href string) int {
+	if href == "" || strings.HasPrefix(href, "#") {
+		return -1
+	}
+	u, err := url.FromEPUBHref(href)
+	if err != nil {
+		return -1
+	}
+	p := base.Resolve(u).Path()
+	for i, link := range readingOrder {
+		if link.URL(nil, nil).Path() == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// Makes the item at [index] the only cover of the reading order.
+func setCover(readingOrder manifest.LinkList, index int) {
+	for i := range readingOrder {
+		rels := readingOrder[i].Rels[:0:0]
+		for _, rel := range readingOrder[i].Rels {
+			if rel != "cover" {
+				rels = append(rels, rel)
+			}
+		}
+		if i == index {
+			rels = append(rels, "cover")
+		}
+		if len(rels) == 0 {
+			rels = nil
+		}
+		readingOrder[i].Rels = rels
+	}
+}
+
+func addSeries(belongsTo map[string]manifest.Collections, name string, number string) map[string]manifest.Collections {
+	collection := manifest.Collection{
+		LocalizedName: manifest.NewLocalizedStringFromString(name),
+	}
+	if position, err := strconv.ParseFloat(number, 64); err == nil {
+		collection.Position = &position
+	}
+	if belongsTo == nil {
+		belongsTo = make(map[string]manifest.Collections)
+	}
+	belongsTo["series"] = append(belongsTo["series"], collection)
+	return belongsTo
+}
+
+// Contributors are separated by commas in ComicInfo.xml.
+func splitContributors(s string) manifest.Contributors {
+	var contributors manifest.Contributors
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			contributors = append(contributors, manifest.Contributor{
+				LocalizedName: manifest.NewLocalizedStringFromString(name),
+			})
+		}
+	}
+	return contributors
+}
@@ -89,3 +89,80 @@ func TestImageTitleBasedOnRoot(t *testing.T) {
 		)
 	})
 }
+
+func contributorNames(contributors manifest.Contributors) []string {
+	names := make([]string, 0, len(contributors))
+	for _, c := range contributors {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+func TestImageComicInfo(t *testing.T) {
+	withImageParser(t, "./testdata/image/comicinfo.cbz", func(p *pub.Builder) {
+		pub := p.Build()
+		m := pub.Manifest.Metadata
+
+		assert.Equal(t, "The Long Night", m.Title())
+		if assert.Len(t, m.BelongsToSeries(), 1) {
+			assert.Equal(t, "Night Tales", m.BelongsToSeries()[0].Name())
+			assert.Equal(t, 3.0, *m.BelongsToSeries()[0].Position)
+		}
+		assert.Equal(t, []string{"Jane Writer", "John Cowriter"}, contributorNames(m.Authors))
+		assert.Equal(t, []string{"Paula Pencil"}, contributorNames(m.Pencilers))
+		assert.Equal(t, []string{"Ian Ink"}, contributorNames(m.Inkers))
+		assert.Equal(t, []string{"Carl Color"}, contributorNames(m.Colorists))
+		assert.Equal(t, []string{"Lea Letter"}, contributorNames(m.Letterers))
+		assert.Equal(t, []string{"Night Press"}, contributorNames(m.Publishers))
+		assert.Equal(t, manifest.Strings{"ja"}, m.Languages)
+		assert.Equal(t, manifest.RTL, m.ReadingProgression)
+		assert.Equal(t, "A story of the night.", m.Description)
+		assert.Len(t, m.Subjects, 2)
+		assert.Equal(t, "2021-10-31", m.Published.Format("2006-01-02"))
+
+		assert.Nil(t, pub.Manifest.ReadingOrder[0].Rels)
+		assert.Equal(t, manifest.Strings{"cover"}, pub.Manifest.ReadingOrder[1].Rels)
+
+		toc := make([]string, 0, len(pub.Manifest.TableOfContents))
+		for _, l := range pub.Manifest.TableOfContents {
+			toc = append(toc, l.Title+" "+l.Href.String())
+		}
+		assert.Equal(t, []string{
+			"Inner Cover 00.jpg",
+			"Cover 01.jpg",
+			"Chapter 1 02.jpg",
+			"Back Cover 04.jpg",
+		}, toc)
+	})
+}
+
+func TestImageACBF(t *testing.T) {
+	withImageParser(t, "./testdata/image/acbf.cbz", func(p *pub.Builder) {
+		pub := p.Build()
+		m := pub.Manifest.Metadata
+
+		assert.Equal(t, "Doorways", m.Title())
+		if assert.Len(t, m.BelongsToSeries(), 1) {
+			assert.Equal(t, "Portals", m.BelongsToSeries()[0].Name())
+			assert.Equal(t, 2.0, *m.BelongsToSeries()[0].Position)
+		}
+		assert.Equal(t, []string{"Ann Author"}, contributorNames(m.Authors))
+		assert.Equal(t, []string{"Pen"}, contributorNames(m.Pencilers))
+		assert.Equal(t, []string{"Cole R. Ist"}, contributorNames(m.Colorists))
+		assert.Equal(t, []string{"ACBF Books"}, contributorNames(m.Publishers))
+		assert.Equal(t, manifest.Strings{"en", "fr"}, m.Languages)
+		assert.Equal(t, "First line.\nSecond line.", m.Description)
+		assert.Equal(t, "2014-05-01", m.Published.Format("2006-01-02"))
+
+		cover := pub.Manifest.ReadingOrder.FirstWithRel("cover")
+		if assert.NotNil(t, cover) {
+			assert.Equal(t, "images/cover.jpg", cover.Href.String())
+		}
+
+		toc := make([]string, 0, len(pub.Manifest.TableOfContents))
+		for _, l := range pub.Manifest.TableOfContents {
+			toc = append(toc, l.Title+" "+l.Href.String())
+		}
+		assert.Equal(t, []string{"Opening images/p1.jpg", "Closing images/p3.jpg"}, toc)
+	})
+}
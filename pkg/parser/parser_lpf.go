@@ -0,0 +1,179 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/readium/go-toolkit/pkg/util/url"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Handles parsing of Lightweight Packaging Format (LPF) archives, containing a W3C Publication
+// Manifest (publication.json) or a primary entry page (index.html) embedding it.
+// https://www.w3.org/TR/lpf/
+type LPFParser struct{}
+
+// Parse implements PublicationParser
+func (p LPFParser) Parse(asset asset.PublicationAsset, fetcher fetcher.Fetcher) (*pub.Builder, error) {
+	if !asset.MediaType().Equal(&mediatype.LPF) {
+		return nil, nil
+	}
+
+	js, baseURL, err := readLPFManifest(fetcher)
+	if err != nil {
+		return nil, err
+	}
+	manifest := w3cManifestToRWPM(js, baseURL)
+
+	// Without a reading order, the primary entry page is the only resource of the reading order.
+	if len(manifest.ReadingOrder) == 0 {
+		manifest.ReadingOrder = lpfEntryPage(fetcher)
+	}
+	if len(manifest.ReadingOrder) == 0 {
+		return nil, errors.New("no reading order found in the LPF publication")
+	}
+	if manifest.Metadata.LocalizedTitle.Length() == 0 {
+		manifest.Metadata.LocalizedTitle.SetDefaultTranslation(asset.Name())
+	}
+	manifest.TableOfContents = lpfTableOfContents(fetcher, manifest)
+
+	return pub.NewBuilder(*manifest, fetcher, webpubServices(asset.MediaType(), manifest, fetcher)), nil
+}
+
+// Reads the W3C manifest from publication.json, or from the primary entry page when missing.
+// Returns the manifest with the URL its relative URLs are relative to.
+func readLPFManifest(f fetcher.Fetcher) (map[string]interface{}, url.URL, error) {
+	manifestURL := url.MustURLFromString("publication.json")
+	js, rerr := f.Get(manifest.Link{Href: manifest.NewHREF(manifestURL)}).ReadAsJSON()
+	if rerr == nil {
+		return js, manifestURL, nil
+	}
+
+	// The manifest can be embedded in the entry page.
+	// https://www.w3.org/TR/pub-manifest/#manifest-embed
+	entryURL := url.MustURLFromString("index.html")
+	data, err := f.Get(manifest.Link{Href: manifest.NewHREF(entryURL)}).Read(0, 0)
+	if err != nil {
+		return nil, nil, errors.Wrap(rerr.Cause, "failed reading publication.json")
+	}
+	doc, herr := html.Parse(bytes.NewReader(data))
+	if herr != nil {
+		return nil, nil, errors.Wrap(herr, "failed parsing index.html")
+	}
+	for _, script := range cascadia.QueryAll(doc, cascadia.MustCompile(`script[type="application/ld+json"]`)) {
+		if script.FirstChild == nil {
+			continue
+		}
+		var embedded map[string]interface{}
+		if json.Unmarshal([]byte(script.FirstChild.Data), &embedded) == nil && embedded != nil {
+			return embedded, entryURL, nil
+		}
+	}
+	// A primary entry page without manifest is still a valid publication.
+	return map[string]interface{}{}, entryURL, nil
+}
+
+func lpfEntryPage(f fetcher.Fetcher) manifest.LinkList {
+	link := manifest.Link{
+		Href:      manifest.MustNewHREFFromString("index.html", false),
+		MediaType: &mediatype.HTML,
+	}
+	if _, err := f.Get(link).Length(); err != nil {
+		return nil
+	}
+	return manifest.LinkList{link}
+}
+
+// Parses the table of contents from the HTML resource with the "contents" rel.
+// https://www.w3.org/TR/pub-manifest/#app-toc-structure
+func lpfTableOfContents(f fetcher.Fetcher, m *manifest.Manifest) manifest.LinkList {
+	link := m.LinkWithRel("contents")
+	if link == nil {
+		return nil
+	}
+	data, err := f.Get(*link).Read(0, 0)
+	if err != nil {
+		return nil
+	}
+	doc, herr := html.Parse(bytes.NewReader(data))
+	if herr != nil {
+		return nil
+	}
+
+	toc := cascadia.Query(doc, cascadia.MustCompile(`[role~="doc-toc"]`))
+	if toc == nil {
+		toc = cascadia.Query(doc, cascadia.MustCompile("nav"))
+	}
+	if toc == nil {
+		return nil
+	}
+	ol := cascadia.Query(toc, cascadia.MustCompile("ol"))
+	if ol == nil {
+		return nil
+	}
+	return parseHTMLTOCList(ol, link.URL(nil, nil))
+}
+
+func parseHTMLTOCList(ol *html.Node, base url.URL) manifest.LinkList {
+	var links manifest.LinkList
+	for li := ol.FirstChild; li != nil; li = li.NextSibling {
+		if li.DataAtom != atom.Li {
+			continue
+		}
+		link := manifest.Link{
+			Href: manifest.MustNewHREFFromString("#", false),
+		}
+		for c := li.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.DataAtom {
+			case atom.Ol:
+				link.Children = parseHTMLTOCList(c, base)
+			case atom.A:
+				for _, attr := range c.Attr {
+					if attr.Key != "href" {
+						continue
+					}
+					if u, err := url.URLFromString(attr.Val); err == nil {
+						link.Href = manifest.NewHREF(base.Resolve(u))
+					}
+				}
+				link.Title = htmlText(c)
+			default:
+				if link.Title == "" {
+					link.Title = htmlText(c)
+				}
+			}
+		}
+		if link.Title == "" && len(link.Children) == 0 {
+			continue
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+func htmlText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/stretchr/testify/assert"
+)
+
+func withLPFParser(t *testing.T, filepath string, f func(*pub.Publication)) {
+	a := asset.File(filepath)
+	fet, err := a.CreateFetcher(asset.Dependencies{
+		ArchiveFactory: archive.NewArchiveFactory(),
+	}, "")
+	assert.NoError(t, err)
+	p, err := LPFParser{}.Parse(a, fet)
+	if assert.NoError(t, err) && assert.NotNil(t, p) {
+		f(p.Build())
+	}
+}
+
+func TestLPFMetadata(t *testing.T) {
+	withLPFParser(t, "./testdata/lpf/webpub.lpf", func(p *pub.Publication) {
+		m := p.Manifest.Metadata
+		assert.Empty(t, m.ConformsTo)
+		assert.Equal(t, "urn:isbn:9780000000001", m.Identifier)
+		assert.Equal(t, "Moby-Dick", m.LocalizedTitle.GetOrFallback("en"))
+		assert.Equal(t, "Moby Dick", m.LocalizedTitle.GetOrFallback("fr"))
+		assert.Equal(t, []string{"Herman Melville"}, contributorNames(m.Authors))
+		assert.Equal(t, "https://example.com/melville", m.Authors[0].Identifier)
+		assert.Equal(t, []string{"Ed One", "Ed Two"}, contributorNames(m.Editors))
+		assert.Equal(t, []string{"Harper & Brothers"}, contributorNames(m.Publishers))
+		assert.Equal(t, manifest.Strings{"en"}, m.Languages)
+		assert.Equal(t, "1851-10-18", m.Published.Format("2006-01-02"))
+		assert.NotNil(t, m.Modified)
+		assert.Equal(t, manifest.LTR, m.ReadingProgression)
+		if assert.NotNil(t, m.Accessibility) {
+			assert.Equal(t, "Accessible.", m.Accessibility.Summary)
+			assert.Equal(t, []manifest.A11yAccessMode{manifest.A11yAccessModeTextual, manifest.A11yAccessModeVisual}, m.Accessibility.AccessModes)
+			assert.Equal(t, [][]manifest.A11yPrimaryAccessMode{
+				{manifest.A11yPrimaryAccessModeTextual},
+				{manifest.A11yPrimaryAccessModeTextual, manifest.A11yPrimaryAccessModeVisual},
+			}, m.Accessibility.AccessModesSufficient)
+		}
+	})
+}
+
+func TestLPFLinks(t *testing.T) {
+	withLPFParser(t, "./testdata/lpf/webpub.lpf", func(p *pub.Publication) {
+		ro := p.Manifest.ReadingOrder
+		if assert.Len(t, ro, 2) {
+			assert.Equal(t, "html/chapter1.html", ro[0].Href.String())
+			assert.Equal(t, &mediatype.HTML, ro[0].MediaType)
+			assert.Equal(t, "html/chapter%202.html", ro[1].Href.String())
+			assert.Equal(t, "Loomings", ro[1].Title)
+		}
+		cover := p.Manifest.LinkWithRel("cover")
+		if assert.NotNil(t, cover) {
+			assert.Equal(t, "images/cover.jpg", cover.Href.String())
+			assert.Equal(t, &mediatype.JPEG, cover.MediaType)
+		}
+	})
+}
+
+func TestLPFTableOfContents(t *testing.T) {
+	withLPFParser(t, "./testdata/lpf/webpub.lpf", func(p *pub.Publication) {
+		assert.Equal(t, manifest.LinkList{
+			{Title: "Etymology", Href: manifest.MustNewHREFFromString("html/chapter1.html", false)},
+			{Title: "Part One", Href: manifest.MustNewHREFFromString("#", false), Children: manifest.LinkList{
+				{Title: "Chapter 1. Loomings", Href: manifest.MustNewHREFFromString("html/chapter%202.html#loomings", false)},
+			}},
+		}, p.Manifest.TableOfContents)
+	})
+}
+
+func TestLPFEmbeddedAudiobookManifest(t *testing.T) {
+	withLPFParser(t, "./testdata/lpf/audiobook.lpf", func(p *pub.Publication) {
+		m := p.Manifest.Metadata
+		assert.Equal(t, manifest.Profiles{manifest.ProfileAudiobook}, m.ConformsTo)
+		assert.Equal(t, "Flatland", m.Title())
+		assert.Equal(t, []string{"Ruth Golding"}, contributorNames(m.Narrators))
+		if assert.NotNil(t, m.Duration) {
+			assert.Equal(t, 900.0, *m.Duration)
+		}
+		ro := p.Manifest.ReadingOrder
+		if assert.Len(t, ro, 2) {
+			assert.Equal(t, "audio/part1.mp3", ro[0].Href.String())
+			assert.Equal(t, 600.0, ro[0].Duration)
+		}
+		assert.Len(t, p.Positions(), 15)
+	})
+}
+
+func TestISO8601Duration(t *testing.T) {
+	for raw, expected := range map[string]float64{
+		"PT1H30M": 5400,
+		"PT5.5S":  5.5,
+		"P1DT1M":  86460,
+		"P1W":     604800,
+		"pt2m10s": 130,
+		"PT0S":    0,
+	} {
+		d, ok := parseISO8601Duration(raw)
+		assert.True(t, ok, raw)
+		assert.Equal(t, expected, d, raw)
+	}
+	for _, raw := range []string{"", "P", "PT", "p", "pt", " P", "PT ", "P1Y", "1H", "PT1H30"} {
+		_, ok := parseISO8601Duration(raw)
+		assert.False(t, ok, raw)
+	}
+}
+
+func TestLPFReadingOrderWithoutExtension(t *testing.T) {
+	f := fetcher.NewBytesFetcher()
+	f.Add(manifest.Link{Href: manifest.MustNewHREFFromString("publication.json", false)}, func() []byte {
+		return []byte(`{"name": "Untyped", "readingOrder": ["chapter1"]}`)
+	})
+	p, err := LPFParser{}.Parse(asset.FileWithMediaType("untyped.lpf", &mediatype.LPF), f)
+	if assert.NoError(t, err) && assert.NotNil(t, p) {
+		m := p.Build().Manifest
+		assert.Empty(t, m.Metadata.ConformsTo)
+		if assert.Len(t, m.ReadingOrder, 1) {
+			assert.Equal(t, "chapter1", m.ReadingOrder[0].Href.String())
+		}
+	}
+}
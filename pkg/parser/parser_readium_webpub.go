@@ -2,13 +2,17 @@ package parser
 
 import (
 	"net/http"
+	"slices"
 
 	"github.com/pkg/errors"
 	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/content/iterator"
 	"github.com/readium/go-toolkit/pkg/fetcher"
 	"github.com/readium/go-toolkit/pkg/manifest"
 	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/parser/epub"
 	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/readium/go-toolkit/pkg/util/url"
 )
 
 type WebPubParser struct {
@@ -70,17 +74,89 @@ func (p WebPubParser) Parse(asset asset.PublicationAsset, fetcher fetcher.Fetche
 			baseURL = path.Base(link.Href)
 		}*/
 
-		panic("remote HttpFetcher not implemented!")
+		// TODO HttpFetcher using p.client
+		return nil, errors.New("remote Readium Web Publication manifests are not supported yet")
+	}
+
+	if err := validateWebPubPackage(mediaType, manifest, lFetcher); err != nil {
+		return nil, err
+	}
+
+	return pub.NewBuilder(*manifest, lFetcher, webpubServices(mediaType, manifest, lFetcher)), nil
+}
+
+// Checks the requirements of the Readium Web Publication packaging, and of the profile of the
+// package.
+// https://readium.org/webpub-manifest/packaging.html
+func validateWebPubPackage(mediaType mediatype.MediaType, m *manifest.Manifest, f fetcher.Fetcher) error {
+	readingOrder := m.ReadingOrder
+	if len(readingOrder) == 0 {
+		return errors.New("the reading order of the package is empty")
+	}
+
+	// Every local resource of the reading order must be packaged.
+	links, err := f.Links()
+	if err != nil {
+		return err
+	}
+	packaged := make(map[string]struct{}, len(links))
+	for _, link := range links {
+		packaged[link.URL(nil, nil).Path()] = struct{}{}
+	}
+	for _, link := range readingOrder {
+		if link.Href.IsTemplated() {
+			continue
+		}
+		u := link.URL(nil, nil)
+		if _, ok := u.(url.AbsoluteURL); ok {
+			continue
+		}
+		if _, ok := packaged[u.Path()]; !ok {
+			return errors.Errorf("reading order item %s not found in the package", link.Href)
+		}
+	}
+
+	switch {
+	case mediaType.Matches(&mediatype.ReadiumAudiobook, &mediatype.LCPProtectedAudiobook) && !readingOrder.AllAreAudio():
+		return errors.New("invalid audiobook: the reading order must only contain audio resources")
+	case mediaType.Equal(&mediatype.ReadiumDivina) && !readingOrder.AllAreBitmap():
+		return errors.New("invalid Divina: the reading order must only contain bitmap images")
+	case mediaType.Equal(&mediatype.LCPProtectedPDF) && !readingOrder.AllMatchMediaType(&mediatype.PDF):
+		// Checks the requirements from the LCPDF specification.
+		// https://readium.org/lcp-specs/notes/lcp-for-pdf.html
+		return errors.New("invalid LCP protected PDF")
+	}
+	return nil
+}
 
-		lFetcher = fetcher // TODO HttpFetcher using p.client
+// Picks the services of a Readium Web Publication according to the profile it conforms to,
+// declared or inferred from its reading order. The content iterators read the embedded resources
+// with the publication fetcher [f].
+func webpubServices(mediaType mediatype.MediaType, m *manifest.Manifest, f fetcher.Fetcher) *pub.ServicesBuilder {
+	conformsTo := func(profile manifest.Profile) bool {
+		return slices.Contains(m.Metadata.ConformsTo, profile) || m.ConformsTo(profile)
 	}
 
-	// Checks the requirements from the LCPDF specification.
-	// https://readium.org/lcp-specs/notes/lcp-for-pdf.html
-	readingOrder := manifest.ReadingOrder
-	if mediaType.Equal(&mediatype.LCPProtectedPDF) && (len(readingOrder) == 0 || !readingOrder.AllMatchMediaType(&mediatype.PDF)) {
-		return nil, errors.New("invalid LCP protected PDF")
+	switch {
+	case mediaType.Matches(&mediatype.ReadiumAudiobook, &mediatype.ReadiumAudiobookManifest, &mediatype.LCPProtectedAudiobook) || conformsTo(manifest.ProfileAudiobook):
+		return pub.NewServicesBuilder(map[string]pub.ServiceFactory{
+			pub.PositionsService_Name: pub.AudioPositionsServiceFactory(pub.DefaultAudioPositionDuration),
+		})
+	case mediaType.Matches(&mediatype.ReadiumDivina, &mediatype.ReadiumDivinaManifest) || conformsTo(manifest.ProfileDivina):
+		return pub.NewServicesBuilder(map[string]pub.ServiceFactory{
+			pub.PositionsService_Name: pub.PerResourcePositionsServiceFactory(mediatype.MustNewOfString("image/*")),
+		})
+	case mediaType.Equal(&mediatype.LCPProtectedPDF) || conformsTo(manifest.ProfilePDF):
+		// Computing the positions of PDF documents requires parsing them, which is the job of the
+		// PDF parser.
+		return nil
 	}
 
-	return pub.NewBuilder(*manifest, lFetcher, nil), nil // TODO services!
+	return pub.NewServicesBuilder(map[string]pub.ServiceFactory{
+		pub.PositionsService_Name: epub.PositionsServiceFactory(nil),
+		pub.ContentService_Name: pub.DefaultContentServiceFactory([]iterator.ResourceContentIteratorFactory{
+			iterator.HTMLFactoryWithFetcher(f),
+		}),
+		pub.GuidedNavigationService_Name: epub.MediaOverlayFactory(),
+	})
 }
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseWebPub(t *testing.T, filepath string) (*pub.Builder, error) {
+	a := asset.File(filepath)
+	fet, err := a.CreateFetcher(asset.Dependencies{
+		ArchiveFactory: archive.NewArchiveFactory(),
+	}, "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return NewWebPubParser(nil).Parse(a, fet)
+}
+
+func withWebPubParser(t *testing.T, filepath string, f func(*pub.Publication)) {
+	p, err := parseWebPub(t, filepath)
+	if assert.NoError(t, err) && assert.NotNil(t, p) {
+		f(p.Build())
+	}
+}
+
+func TestWebPubPackageServices(t *testing.T) {
+	withWebPubParser(t, "./testdata/webpub/book.webpub", func(p *pub.Publication) {
+		positions := p.PositionsByReadingOrder()
+		if assert.Len(t, positions, 2) {
+			assert.Greater(t, len(positions[0]), 1)
+			assert.Len(t, positions[1], 1)
+		}
+		assert.NotNil(t, p.FindService(pub.ContentService_Name))
+	})
+}
+
+func TestWebPubDivinaServices(t *testing.T) {
+	withWebPubParser(t, "./testdata/webpub/comic.divina", func(p *pub.Publication) {
+		positions := p.Positions()
+		if assert.Len(t, positions, 2) {
+			assert.Equal(t, "page2.png", positions[1].Href.String())
+		}
+		assert.Nil(t, p.FindService(pub.ContentService_Name))
+	})
+}
+
+func TestWebPubAudiobookServices(t *testing.T) {
+	withWebPubParser(t, "./testdata/webpub/book.audiobook", func(p *pub.Publication) {
+		positions := p.PositionsByReadingOrder()
+		if assert.Len(t, positions, 2) {
+			assert.Greater(t, len(positions[0]), len(positions[1]))
+		}
+		assert.Nil(t, p.FindService(pub.ContentService_Name))
+	})
+}
+
+func TestWebPubPackageValidation(t *testing.T) {
+	_, err := parseWebPub(t, "./testdata/webpub/missing.webpub")
+	assert.ErrorContains(t, err, "chapter2.xhtml not found")
+
+	_, err = parseWebPub(t, "./testdata/webpub/invalid.divina")
+	assert.ErrorContains(t, err, "invalid Divina")
+}
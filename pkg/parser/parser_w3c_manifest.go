@@ -0,0 +1,289 @@
+package parser
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/util/url"
+)
+
+// Profile of the W3C Audiobooks specification.
+// https://www.w3.org/TR/audiobooks/
+const w3cAudiobookProfile = "https://www.w3.org/TR/audiobooks/"
+
+// Maps the W3C Publication Manifest roles to the RWPM contributors.
+// https://www.w3.org/TR/pub-manifest/#creators
+var w3cContributorRoles = []struct {
+	role  string
+	field func(m *manifest.Metadata) *manifest.Contributors
+}{
+	{"artist", func(m *manifest.Metadata) *manifest.Contributors { return &m.Artists }},
+	{"author", func(m *manifest.Metadata) *manifest.Contributors { return &m.Authors }},
+	{"colorist", func(m *manifest.Metadata) *manifest.Contributors { return &m.Colorists }},
+	{"contributor", func(m *manifest.Metadata) *manifest.Contributors { return &m.Contributors }},
+	{"creator", func(m *manifest.Metadata) *manifest.Contributors { return &m.Contributors }},
+	{"editor", func(m *manifest.Metadata) *manifest.Contributors { return &m.Editors }},
+	{"illustrator", func(m *manifest.Metadata) *manifest.Contributors { return &m.Illustrators }},
+	{"inker", func(m *manifest.Metadata) *manifest.Contributors { return &m.Inkers }},
+	{"letterer", func(m *manifest.Metadata) *manifest.Contributors { return &m.Letterers }},
+	{"penciler", func(m *manifest.Metadata) *manifest.Contributors { return &m.Pencilers }},
+	{"publisher", func(m *manifest.Metadata) *manifest.Contributors { return &m.Publishers }},
+	{"readBy", func(m *manifest.Metadata) *manifest.Contributors { return &m.Narrators }},
+	{"translator", func(m *manifest.Metadata) *manifest.Contributors { return &m.Translators }},
+}
+
+// Maps a W3C Publication Manifest to a Readium Web Publication Manifest.
+// The relative URLs of the manifest are resolved against [baseURL].
+// https://www.w3.org/TR/pub-manifest/
+func w3cManifestToRWPM(js map[string]interface{}, baseURL url.URL) *manifest.Manifest {
+	m := &manifest.Manifest{
+		Context:        manifest.Strings{manifest.WebpubManifestContext},
+		Subcollections: make(manifest.PublicationCollectionMap),
+	}
+	metadata := &m.Metadata
+
+	if id, ok := js["id"].(string); ok {
+		metadata.Identifier = id
+	} else if urls := w3cStrings(js["url"]); len(urls) > 0 {
+		metadata.Identifier = urls[0]
+	}
+	if name := w3cLocalizedString(js["name"]); name != nil {
+		metadata.LocalizedTitle = *name
+	}
+	for _, r := range w3cContributorRoles {
+		contributors := r.field(metadata)
+		*contributors = append(*contributors, w3cContributors(js[r.role])...)
+	}
+	metadata.Languages = w3cStrings(js["inLanguage"])
+	if published, ok := js["datePublished"].(string); ok {
+		metadata.Published = extensions.ParseDate(published)
+	}
+	if modified, ok := js["dateModified"].(string); ok {
+		metadata.Modified = extensions.ParseDate(modified)
+	}
+	if description := w3cLocalizedString(js["description"]); description != nil {
+		metadata.Description = description.String()
+	}
+	if duration, ok := parseISO8601Duration(js["duration"]); ok {
+		metadata.Duration = &duration
+	}
+	switch js["readingProgression"] {
+	case "ltr":
+		metadata.ReadingProgression = manifest.LTR
+	case "rtl":
+		metadata.ReadingProgression = manifest.RTL
+	}
+	metadata.Accessibility = w3cAccessibility(js)
+
+	m.ReadingOrder = w3cLinks(js["readingOrder"], baseURL)
+	m.Resources = w3cLinks(js["resources"], baseURL)
+	m.Links = w3cLinks(js["links"], baseURL)
+
+	if w3cIsAudiobook(js, m.ReadingOrder) {
+		metadata.ConformsTo = manifest.Profiles{manifest.ProfileAudiobook}
+	}
+	return m
+}
+
+// Returns whether the publication is an audiobook, according to its profile, type or content.
+func w3cIsAudiobook(js map[string]interface{}, readingOrder manifest.LinkList) bool {
+	if extensions.Contains(w3cStrings(js["conformsTo"]), w3cAudiobookProfile) {
+		return true
+	}
+	for _, t := range w3cStrings(js["type"]) {
+		if strings.EqualFold(strings.TrimPrefix(t, "schema:"), "Audiobook") {
+			return true
+		}
+	}
+	return len(readingOrder) > 0 && readingOrder.AllAreAudio()
+}
+
+// Values of the W3C manifest can be a single item or an array of items.
+func w3cArray(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
+
+func w3cStrings(v interface{}) []string {
+	var res []string
+	for _, item := range w3cArray(v) {
+		if s, ok := item.(string); ok && s != "" {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// Parses a localizable string, which is either a string, a {value, language} object or an array of them.
+// https://www.w3.org/TR/pub-manifest/#value-localizable-string
+func w3cLocalizedString(v interface{}) *manifest.LocalizedString {
+	var ls manifest.LocalizedString
+	for _, item := range w3cArray(v) {
+		switch item := item.(type) {
+		case string:
+			ls.SetDefaultTranslation(item)
+		case map[string]interface{}:
+			value, _ := item["value"].(string)
+			if value == "" {
+				continue
+			}
+			language, _ := item["language"].(string)
+			ls.SetTranslation(language, value)
+		}
+	}
+	if ls.Length() == 0 {
+		return nil
+	}
+	return &ls
+}
+
+// Parses entities, which are either names or Person/Organization objects.
+// https://www.w3.org/TR/pub-manifest/#value-entity
+func w3cContributors(v interface{}) manifest.Contributors {
+	var contributors manifest.Contributors
+	for _, item := range w3cArray(v) {
+		var contributor manifest.Contributor
+		switch item := item.(type) {
+		case string:
+			contributor.LocalizedName = manifest.NewLocalizedStringFromString(item)
+		case map[string]interface{}:
+			name := w3cLocalizedString(item["name"])
+			if name == nil {
+				continue
+			}
+			contributor.LocalizedName = *name
+			if id, ok := item["id"].(string); ok {
+				contributor.Identifier = id
+			}
+			for _, u := range w3cStrings(item["url"]) {
+				if href, err := manifest.NewHREFFromString(u, false); err == nil {
+					contributor.Links = append(contributor.Links, manifest.Link{Href: href})
+				}
+			}
+		default:
+			continue
+		}
+		contributors = append(contributors, contributor)
+	}
+	return contributors
+}
+
+// Parses linked resources, which are either URLs or LinkedResource objects.
+// https://www.w3.org/TR/pub-manifest/#value-linked-resource
+func w3cLinks(v interface{}, baseURL url.URL) manifest.LinkList {
+	var links manifest.LinkList
+	for _, item := range w3cArray(v) {
+		var link *manifest.Link
+		switch item := item.(type) {
+		case string:
+			link = w3cLink(map[string]interface{}{"url": item}, baseURL)
+		case map[string]interface{}:
+			link = w3cLink(item, baseURL)
+		}
+		if link != nil {
+			links = append(links, *link)
+		}
+	}
+	return links
+}
+
+func w3cLink(js map[string]interface{}, baseURL url.URL) *manifest.Link {
+	rawURL, _ := js["url"].(string)
+	if rawURL == "" {
+		return nil
+	}
+	u, err := url.URLFromString(rawURL)
+	if err != nil {
+		return nil
+	}
+	u = baseURL.Resolve(u)
+
+	link := &manifest.Link{
+		Href: manifest.NewHREF(u),
+		Rels: w3cStrings(js["rel"]),
+	}
+	if format, ok := js["encodingFormat"].(string); ok {
+		link.MediaType = mediatype.MaybeNewOfString(format)
+	}
+	if link.MediaType == nil {
+		link.MediaType = mediatype.OfExtension(strings.TrimPrefix(path.Ext(u.Path()), "."))
+	}
+	if name := w3cLocalizedString(js["name"]); name != nil {
+		link.Title = name.String()
+	}
+	if duration, ok := parseISO8601Duration(js["duration"]); ok {
+		link.Duration = duration
+	}
+	link.Alternates = w3cLinks(js["alternate"], baseURL)
+	return link
+}
+
+// Maps the schema.org accessibility properties.
+// https://www.w3.org/TR/pub-manifest/#accessibility
+func w3cAccessibility(js map[string]interface{}) *manifest.A11y {
+	a11y := manifest.NewA11y()
+	if summary, ok := js["accessibilitySummary"].(string); ok {
+		a11y.Summary = summary
+	}
+	a11y.AccessModes = manifest.A11yAccessModesFromStrings(w3cStrings(js["accessMode"]))
+	for _, item := range w3cArray(js["accessModeSufficient"]) {
+		var modes []string
+		switch item := item.(type) {
+		case string: // e.g. "textual,visual"
+			for _, mode := range strings.Split(item, ",") {
+				modes = append(modes, strings.TrimSpace(mode))
+			}
+		case map[string]interface{}: // ItemList
+			modes = w3cStrings(item["itemListElement"])
+		}
+		if len(modes) > 0 {
+			a11y.AccessModesSufficient = append(a11y.AccessModesSufficient, manifest.A11yPrimaryAccessModesFromStrings(modes))
+		}
+	}
+	a11y.Features = manifest.A11yFeaturesFromStrings(w3cStrings(js["accessibilityFeature"]))
+	a11y.Hazards = manifest.A11yHazardsFromStrings(w3cStrings(js["accessibilityHazard"]))
+	if a11y.IsEmpty() {
+		return nil
+	}
+	return &a11y
+}
+
+var iso8601DurationRegex = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)W)?(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// Parses an ISO 8601 duration such as PT1H30M5.5S into seconds.
+// Years and months are not supported, as their duration is ambiguous.
+func parseISO8601Duration(v interface{}) (float64, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, false
+	}
+	s = strings.ToUpper(strings.TrimSpace(s))
+	matches := iso8601DurationRegex.FindStringSubmatch(s)
+	if matches == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, false
+	}
+	units := []float64{7 * 24 * 3600, 24 * 3600, 3600, 60, 1}
+	var duration float64
+	for i, unit := range units {
+		if matches[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(matches[i+1], 64)
+		if err != nil {
+			return 0, false
+		}
+		duration += n * unit
+	}
+	return duration, true
+}
@@ -0,0 +1,212 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/content/element"
+	"github.com/readium/go-toolkit/pkg/content/iterator"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+)
+
+// Iterates the text of a PDF [resource], paragraph by paragraph, starting from the given [locator].
+// If you want to start mid-resource, the [locator] must contain a `page=N` fragment.
+// If you want to start from the end of the resource, the [locator] must have a `progression` of 1.0.
+type ContentIterator struct {
+	resource        fetcher.Resource
+	locator         manifest.Locator
+	BeforeMaxLength int // Locators will contain a `before` context of up to this amount of characters.
+
+	currentElement *iterator.ElementWithDelta
+	currentIndex   *int
+	parsedElements *iterator.ParsedElements
+}
+
+func NewContentIterator(resource fetcher.Resource, locator manifest.Locator) *ContentIterator {
+	return &ContentIterator{
+		resource:        resource,
+		locator:         locator,
+		BeforeMaxLength: 50,
+	}
+}
+
+func ContentIteratorFactory() iterator.ResourceContentIteratorFactory {
+	return func(resource fetcher.Resource, locator manifest.Locator) iterator.Iterator {
+		if resource.Link().MediaType.Matches(&mediatype.PDF) {
+			return NewContentIterator(resource, locator)
+		}
+		return nil
+	}
+}
+
+func (it *ContentIterator) HasPrevious() (bool, error) {
+	if it.currentElement != nil && it.currentElement.Delta == -1 {
+		return true, nil
+	}
+
+	elements, err := it.elements()
+	if err != nil {
+		return false, err
+	}
+	index := elements.StartIndex
+	if it.currentIndex != nil {
+		index = *it.currentIndex
+	}
+	index--
+
+	if index < 0 || index >= len(elements.Elements) {
+		return false, nil
+	}
+
+	it.currentIndex = &index
+	it.currentElement = &iterator.ElementWithDelta{
+		El:    elements.Elements[index],
+		Delta: -1,
+	}
+	return true, nil
+}
+
+func (it *ContentIterator) Previous() element.Element {
+	if it.currentElement == nil || it.currentElement.Delta != -1 {
+		panic("Previous() in pdf.ContentIterator called without a previous call to HasPrevious()")
+	}
+	el := it.currentElement.El
+	it.currentElement = nil
+	return el
+}
+
+func (it *ContentIterator) HasNext() (bool, error) {
+	if it.currentElement != nil && it.currentElement.Delta == 1 {
+		return true, nil
+	}
+
+	elements, err := it.elements()
+	if err != nil {
+		return false, err
+	}
+	index := elements.StartIndex - 1
+	if it.currentIndex != nil {
+		index = *it.currentIndex
+	}
+	index++
+
+	if index < 0 || index >= len(elements.Elements) {
+		return false, nil
+	}
+
+	it.currentIndex = &index
+	it.currentElement = &iterator.ElementWithDelta{
+		El:    elements.Elements[index],
+		Delta: 1,
+	}
+	return true, nil
+}
+
+func (it *ContentIterator) Next() element.Element {
+	if it.currentElement == nil || it.currentElement.Delta != 1 {
+		panic("Next() in pdf.ContentIterator called without a previous call to HasNext()")
+	}
+	el := it.currentElement.El
+	it.currentElement = nil
+	return el
+}
+
+func (it *ContentIterator) elements() (*iterator.ParsedElements, error) {
+	if it.parsedElements == nil {
+		elements, err := it.parseElements()
+		if err != nil {
+			return nil, err
+		}
+		it.parsedElements = elements
+	}
+	return it.parsedElements, nil
+}
+
+func (it *ContentIterator) parseElements() (*iterator.ParsedElements, error) {
+	ctx, err := open(fetcher.NewResourceReadSeeker(it.resource))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading PDF "+it.resource.Link().Href.String())
+	}
+
+	startPage := it.startPage(ctx.PageCount)
+	res := &iterator.ParsedElements{
+		Elements: []element.Element{},
+	}
+	startFound := false
+	var before string
+	for page := 1; page <= ctx.PageCount; page++ {
+		if !startFound && page >= startPage {
+			res.StartIndex = len(res.Elements)
+			startFound = true
+		}
+
+		paragraphs, err := extractPageParagraphs(ctx, page)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed extracting text of page %d of PDF %s", page, it.resource.Link().Href.String())
+		}
+		for _, paragraph := range paragraphs {
+			locator := it.pageLocator(page, ctx.PageCount)
+			locator.Text = manifest.Text{
+				Before:    before,
+				Highlight: paragraph,
+			}
+			res.Elements = append(res.Elements, element.NewTextElement(
+				locator,
+				element.Body{},
+				[]element.TextSegment{{
+					Locator: locator,
+					Text:    paragraph,
+				}},
+				nil,
+			))
+
+			before += paragraph + " "
+			if len(before) > it.BeforeMaxLength {
+				before = strings.ToValidUTF8(before[len(before)-it.BeforeMaxLength:], "")
+			}
+		}
+	}
+	if !startFound {
+		res.StartIndex = len(res.Elements)
+	}
+	return res, nil
+}
+
+// Page from which the iteration starts, according to the starting locator.
+// Returns a page after the last one to start from the end.
+func (it *ContentIterator) startPage(pageCount int) int {
+	for _, f := range it.locator.Locations.Fragments {
+		if v, ok := strings.CutPrefix(f, "page="); ok {
+			if page, err := strconv.Atoi(v); err == nil && page > 0 {
+				return page
+			}
+		}
+	}
+	if p := it.locator.Locations.Progression; p != nil && *p > 0 {
+		if *p >= 1 {
+			return pageCount + 1
+		}
+		return int(*p*float64(pageCount)) + 1
+	}
+	return 1
+}
+
+func (it *ContentIterator) pageLocator(page int, pageCount int) manifest.Locator {
+	progression := float64(page-1) / float64(pageCount)
+	position := uint(page)
+	return manifest.Locator{
+		Href:      it.locator.Href,
+		MediaType: it.locator.MediaType,
+		Title:     it.locator.Title,
+		Locations: manifest.Locations{
+			Fragments:        []string{fmt.Sprintf("page=%d", page)},
+			Progression:      &progression,
+			TotalProgression: &progression,
+			Position:         &position,
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package pdf
+
+import (
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/content/element"
+	"github.com/readium/go-toolkit/pkg/content/iterator"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/stretchr/testify/assert"
+)
+
+type textWithPage struct {
+	Text     string
+	Fragment string
+	Position uint
+}
+
+func collectText(t *testing.T, it iterator.Iterator) []textWithPage {
+	var res []textWithPage
+	for {
+		el, err := iterator.ItNextOrNil(it)
+		if !assert.NoError(t, err) || el == nil {
+			return res
+		}
+		te, ok := el.(element.TextElement)
+		if !assert.True(t, ok) {
+			return res
+		}
+		loc := te.Locator()
+		assert.Equal(t, te.Text(), loc.Text.Highlight)
+		res = append(res, textWithPage{te.Text(), loc.Locations.Fragments[0], *loc.Locations.Position})
+	}
+}
+
+func TestPDFContentIterator(t *testing.T) {
+	withPDFParser(t, "./testdata/text.pdf", func(p *pub.Publication) {
+		service, ok := p.FindService(pub.ContentService_Name).(pub.ContentService)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, []textWithPage{
+			{"First paragraph line one, continues here.", "page=1", 1},
+			{"Second paragraph.", "page=1", 1},
+			{"Hi", "page=1", 1}, // Decoded with a ToUnicode CMap
+			{"Café in a (form)", "page=2", 2},
+		}, collectText(t, service.Content(nil).Iterator()))
+	})
+}
+
+func TestPDFContentIteratorStartingAtPage(t *testing.T) {
+	withPDFParser(t, "./testdata/text.pdf", func(p *pub.Publication) {
+		link := p.Manifest.ReadingOrder[0]
+		locator := p.LocatorFromLink(link)
+		locator.Locations.Fragments = []string{"page=2"}
+
+		it := ContentIteratorFactory()(p.Get(link), *locator)
+		if !assert.NotNil(t, it) {
+			return
+		}
+		assert.Equal(t, []textWithPage{
+			{"Café in a (form)", "page=2", 2},
+		}, collectText(t, it))
+
+		el, err := iterator.ItPreviousOrNil(it)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hi", el.(element.TextElement).Text())
+		assert.Equal(t, "graph line one, continues here. Second paragraph. ", el.Locator().Text.Before) // Up to BeforeMaxLength
+	})
+}
+
+func TestPDFContentIteratorIgnoresOtherResources(t *testing.T) {
+	withPDFParser(t, "./testdata/text.pdf", func(p *pub.Publication) {
+		link := manifest.Link{Href: manifest.MustNewHREFFromString("page.html", false), MediaType: &mediatype.HTML}
+		assert.Nil(t, ContentIteratorFactory()(p.Get(link), manifest.Locator{}))
+	})
+}
@@ -0,0 +1,252 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+)
+
+// Minimal lexer for the PDF syntax used in content streams and CMaps.
+// Operands are returned as float64 (numbers), []byte (literal and hex strings),
+// pdfName (names), []interface{} (arrays) and nil (dictionaries, which are skipped).
+type contentLexer struct {
+	data []byte
+	pos  int
+}
+
+type pdfName string
+
+// An operator and the operands preceding it.
+type contentOp struct {
+	name     string
+	operands []interface{}
+}
+
+func newContentLexer(data []byte) *contentLexer {
+	return &contentLexer{data: data}
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return c == '(' || c == ')' || c == '<' || c == '>' || c == '[' || c == ']' || c == '{' || c == '}' || c == '/' || c == '%'
+}
+
+// Returns the next operator with its operands, or false at the end of the data.
+func (l *contentLexer) next() (contentOp, bool) {
+	var operands []interface{}
+	for {
+		tok, isOp, ok := l.token()
+		if !ok {
+			return contentOp{}, false
+		}
+		if isOp {
+			op := contentOp{name: tok.(string), operands: operands}
+			if op.name == "BI" {
+				l.skipInlineImage()
+			}
+			return op, true
+		}
+		operands = append(operands, tok)
+	}
+}
+
+// Reads the next token. isOp is true when the token is an operator keyword.
+func (l *contentLexer) token() (tok interface{}, isOp bool, ok bool) {
+	l.skipWhitespaceAndComments()
+	if l.pos >= len(l.data) {
+		return nil, false, false
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '(':
+		return l.literalString(), false, true
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.skipDict()
+			return nil, false, true
+		}
+		return l.hexString(), false, true
+	case c == '/':
+		l.pos++
+		return pdfName(l.regular()), false, true
+	case c == '[':
+		l.pos++
+		arr := []interface{}{}
+		for {
+			l.skipWhitespaceAndComments()
+			if l.pos >= len(l.data) {
+				return arr, false, true
+			}
+			if l.data[l.pos] == ']' {
+				l.pos++
+				return arr, false, true
+			}
+			t, op, ok := l.token()
+			if !ok {
+				return arr, false, true
+			}
+			if !op {
+				arr = append(arr, t)
+			}
+		}
+	case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+		// Unbalanced delimiter, ignored
+		l.pos++
+		return l.token()
+	}
+
+	word := l.regular()
+	if word == "" {
+		l.pos++
+		return l.token()
+	}
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, false, true
+	}
+	return word, true, true
+}
+
+func (l *contentLexer) skipWhitespaceAndComments() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) {
+			l.pos++
+		} else if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		} else {
+			return
+		}
+	}
+}
+
+// Reads a sequence of regular characters.
+func (l *contentLexer) regular() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+func (l *contentLexer) literalString() []byte {
+	l.pos++ // (
+	var buf bytes.Buffer
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return buf.Bytes()
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return buf.Bytes()
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case 'b':
+				buf.WriteByte('\b')
+			case 'f':
+				buf.WriteByte('\f')
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+				// Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					buf.WriteByte(byte(v))
+				} else {
+					buf.WriteByte(e)
+				}
+			}
+			continue
+		}
+		buf.WriteByte(c)
+	}
+	return buf.Bytes()
+}
+
+func (l *contentLexer) hexString() []byte {
+	l.pos++ // <
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isPDFWhitespace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // >
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	b, _ := hex.DecodeString(string(digits))
+	return b
+}
+
+func (l *contentLexer) skipDict() {
+	depth := 0
+	for l.pos < len(l.data) {
+		switch {
+		case bytes.HasPrefix(l.data[l.pos:], []byte("<<")):
+			depth++
+			l.pos += 2
+		case bytes.HasPrefix(l.data[l.pos:], []byte(">>")):
+			depth--
+			l.pos += 2
+			if depth == 0 {
+				return
+			}
+		case l.data[l.pos] == '(':
+			l.literalString()
+		default:
+			l.pos++
+		}
+	}
+}
+
+// Skips the data of an inline image, up to and including the EI operator.
+func (l *contentLexer) skipInlineImage() {
+	idx := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if idx < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += idx + 3
+	for l.pos < len(l.data) {
+		idx := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if idx < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		end := l.pos + idx
+		l.pos = end + 2
+		if isPDFWhitespace(l.data[end-1]) && (l.pos >= len(l.data) || isPDFWhitespace(l.data[l.pos])) {
+			return
+		}
+	}
+}
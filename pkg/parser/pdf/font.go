@@ -0,0 +1,281 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Decodes the strings shown with a font to Unicode text.
+type fontDecoder struct {
+	toUnicode  map[string]string // From a ToUnicode CMap, by character code
+	codeLength []int             // Byte lengths of the codes in toUnicode, longest first
+	simple     *[256]rune        // Encoding of a simple font, when there is no ToUnicode CMap
+}
+
+// Decodes the given string, or returns false when the font can't be mapped to Unicode.
+func (d *fontDecoder) decode(s []byte) (string, bool) {
+	if d == nil {
+		// Unknown font, assumes a standard encoding
+		return decodeSimple(s, &winAnsiEncoding), true
+	}
+	if d.toUnicode != nil {
+		var sb strings.Builder
+		for i := 0; i < len(s); {
+			matched := false
+			for _, n := range d.codeLength {
+				if i+n > len(s) {
+					continue
+				}
+				if u, ok := d.toUnicode[string(s[i:i+n])]; ok {
+					sb.WriteString(u)
+					i += n
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				i += d.codeLength[len(d.codeLength)-1]
+			}
+		}
+		return sb.String(), true
+	}
+	if d.simple != nil {
+		return decodeSimple(s, d.simple), true
+	}
+	return "", false
+}
+
+func decodeSimple(s []byte, encoding *[256]rune) string {
+	runes := make([]rune, 0, len(s))
+	for _, c := range s {
+		if r := encoding[c]; r != 0 {
+			runes = append(runes, r)
+		}
+	}
+	return string(runes)
+}
+
+var (
+	winAnsiEncoding  = charmapTable(charmap.Windows1252)
+	macRomanEncoding = charmapTable(charmap.Macintosh)
+)
+
+func charmapTable(cm *charmap.Charmap) (table [256]rune) {
+	for i := range table {
+		r := cm.DecodeByte(byte(i))
+		if r != '�' {
+			table[i] = r
+		}
+	}
+	return
+}
+
+// Loads the decoders of the fonts in the given page resources, by resource name.
+func loadFontDecoders(ctx *model.Context, resources types.Dict) map[string]*fontDecoder {
+	decoders := make(map[string]*fontDecoder)
+	if resources == nil {
+		return decoders
+	}
+	fonts, err := ctx.DereferenceDict(resources["Font"])
+	if err != nil || fonts == nil {
+		return decoders
+	}
+	for name, o := range fonts {
+		font, err := ctx.DereferenceDict(o)
+		if err != nil || font == nil {
+			continue
+		}
+		decoders[name] = newFontDecoder(ctx, font)
+	}
+	return decoders
+}
+
+func newFontDecoder(ctx *model.Context, font types.Dict) *fontDecoder {
+	d := &fontDecoder{}
+
+	if sd, _, err := ctx.DereferenceStreamDict(font["ToUnicode"]); err == nil && sd != nil {
+		if err := sd.Decode(); err == nil {
+			d.parseToUnicode(sd.Content)
+		}
+	}
+	if d.toUnicode != nil {
+		return d
+	}
+
+	if subtype := font.NameEntry("Subtype"); subtype != nil && *subtype == "Type0" {
+		// Composite font without a ToUnicode CMap: the text can't be recovered.
+		return d
+	}
+
+	table := winAnsiEncoding
+	var differences types.Array
+	switch enc := derefOrNil(ctx, font["Encoding"]).(type) {
+	case types.Name:
+		if enc.Value() == "MacRomanEncoding" {
+			table = macRomanEncoding
+		}
+	case types.Dict:
+		if base := enc.NameEntry("BaseEncoding"); base != nil && *base == "MacRomanEncoding" {
+			table = macRomanEncoding
+		}
+		differences, _ = ctx.DereferenceArray(enc["Differences"])
+	}
+
+	// Applies the /Differences array: [code /name1 /name2 ... code /name ...]
+	code := -1
+	for _, o := range differences {
+		switch o := o.(type) {
+		case types.Integer:
+			code = o.Value()
+		case types.Name:
+			if code >= 0 && code < 256 {
+				if r, ok := glyphNameToRune(o.Value()); ok {
+					table[code] = r
+				}
+				code++
+			}
+		}
+	}
+	d.simple = &table
+	return d
+}
+
+func derefOrNil(ctx *model.Context, o types.Object) types.Object {
+	o, err := ctx.Dereference(o)
+	if err != nil {
+		return nil
+	}
+	return o
+}
+
+// Common glyph names which are not a single character.
+var glyphNames = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#', "dollar": '$',
+	"percent": '%', "ampersand": '&', "quotesingle": '\'', "parenleft": '(',
+	"parenright": ')', "asterisk": '*', "plus": '+', "comma": ',', "hyphen": '-',
+	"period": '.', "slash": '/', "zero": '0', "one": '1', "two": '2', "three": '3',
+	"four": '4', "five": '5', "six": '6', "seven": '7', "eight": '8', "nine": '9',
+	"colon": ':', "semicolon": ';', "less": '<', "equal": '=', "greater": '>',
+	"question": '?', "at": '@', "bracketleft": '[', "backslash": '\\',
+	"bracketright": ']', "underscore": '_', "quoteleft": '‘', "quoteright": '’',
+	"quotedblleft": '“', "quotedblright": '”', "endash": '–', "emdash": '—',
+	"bullet": '•', "ellipsis": '…', "fi": 'ﬁ', "fl": 'ﬂ', "ff": 'ﬀ', "ffi": 'ﬃ',
+	"ffl": 'ﬄ', "eacute": 'é', "egrave": 'è', "agrave": 'à', "ccedilla": 'ç',
+}
+
+func glyphNameToRune(name string) (rune, bool) {
+	if r, ok := glyphNames[name]; ok {
+		return r, true
+	}
+	if len(name) == 1 {
+		return rune(name[0]), true
+	}
+	if hexa, ok := strings.CutPrefix(name, "uni"); ok && len(hexa) == 4 {
+		if v, err := strconv.ParseUint(hexa, 16, 32); err == nil {
+			return rune(v), true
+		}
+	}
+	return 0, false
+}
+
+// Parses the bfchar and bfrange mappings of a ToUnicode CMap.
+func (d *fontDecoder) parseToUnicode(data []byte) {
+	mappings := make(map[string]string)
+	lengths := make(map[int]struct{})
+	add := func(code []byte, dst string) {
+		mappings[string(code)] = dst
+		lengths[len(code)] = struct{}{}
+	}
+
+	l := newContentLexer(data)
+	for {
+		op, ok := l.next()
+		if !ok {
+			break
+		}
+		switch op.name {
+		case "endbfchar":
+			for i := 0; i+1 < len(op.operands); i += 2 {
+				src, ok1 := op.operands[i].([]byte)
+				dst, ok2 := op.operands[i+1].([]byte)
+				if ok1 && ok2 && len(src) > 0 {
+					add(src, utf16BEToString(dst))
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(op.operands); i += 3 {
+				lo, ok1 := op.operands[i].([]byte)
+				hi, ok2 := op.operands[i+1].([]byte)
+				if !ok1 || !ok2 || len(lo) == 0 || len(lo) != len(hi) || len(lo) > 4 {
+					continue
+				}
+				start, end := bytesToInt(lo), bytesToInt(hi)
+				if end < start || end-start > 0xFFFF {
+					continue
+				}
+				switch dst := op.operands[i+2].(type) {
+				case []byte:
+					base := utf16.Decode(utf16BE(dst))
+					if len(base) == 0 {
+						continue
+					}
+					for c := start; c <= end; c++ {
+						runes := append([]rune{}, base...)
+						runes[len(runes)-1] += rune(c - start)
+						add(intToBytes(c, len(lo)), string(runes))
+					}
+				case []interface{}:
+					for j, o := range dst {
+						if b, ok := o.([]byte); ok && start+j <= end {
+							add(intToBytes(start+j, len(lo)), utf16BEToString(b))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if len(mappings) == 0 {
+		return
+	}
+	d.toUnicode = mappings
+	for n := 4; n > 0; n-- {
+		if _, ok := lengths[n]; ok {
+			d.codeLength = append(d.codeLength, n)
+		}
+	}
+}
+
+func utf16BE(b []byte) []uint16 {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		u = append(u, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return u
+}
+
+func utf16BEToString(b []byte) string {
+	return string(utf16.Decode(utf16BE(b)))
+}
+
+func bytesToInt(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func intToBytes(v int, n int) []byte {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+	return b
+}
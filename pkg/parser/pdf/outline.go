@@ -0,0 +1,244 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/util/url"
+)
+
+// Guards against corrupted documents with cyclic or absurdly deep structures.
+const maxOutlineDepth = 32
+
+// Builds the table of contents from the outline (bookmarks) of the PDF document.
+// Each entry links to the page of its destination, e.g. `file.pdf#page=3`, with [href]
+// being the URL of the PDF document. Entries without a destination link to the page
+// of their first child, and are dropped if they have none.
+func ParseOutline(ctx *model.Context, href url.URL) manifest.LinkList {
+	outlines := ctx.Outlines
+	if outlines == nil {
+		catalog, err := ctx.Catalog()
+		if err != nil {
+			return nil
+		}
+		if outlines, err = ctx.DereferenceDict(catalog["Outlines"]); err != nil || outlines == nil {
+			return nil
+		}
+	}
+
+	p := &outlineParser{
+		ctx:     ctx,
+		href:    href,
+		pages:   pageNumbers(ctx),
+		visited: make(map[int]struct{}),
+	}
+	return p.items(outlines["First"], 0)
+}
+
+type outlineParser struct {
+	ctx     *model.Context
+	href    url.URL
+	pages   map[int]int // Page number by page object number
+	visited map[int]struct{}
+}
+
+// Parses an outline item and its following siblings.
+func (p *outlineParser) items(first types.Object, depth int) manifest.LinkList {
+	if depth > maxOutlineDepth {
+		return nil
+	}
+
+	var links manifest.LinkList
+	for o := first; o != nil; {
+		ir, ok := o.(types.IndirectRef)
+		if !ok {
+			break
+		}
+		if _, ok := p.visited[ir.ObjectNumber.Value()]; ok {
+			break
+		}
+		p.visited[ir.ObjectNumber.Value()] = struct{}{}
+
+		d, err := p.ctx.DereferenceDict(ir)
+		if err != nil || d == nil {
+			break
+		}
+		o = d["Next"]
+
+		title, _ := p.ctx.DereferenceText(d["Title"])
+		link := manifest.Link{
+			Title:     cleanTitle(title),
+			MediaType: &mediatype.PDF,
+			Children:  p.items(d["First"], depth+1),
+		}
+
+		page := p.destinationPage(p.itemDestination(d), 0)
+		if page == 0 {
+			if len(link.Children) == 0 {
+				continue
+			}
+			link.Href = link.Children[0].Href
+		} else {
+			link.Href = manifest.NewHREF(url.MustURLFromString(fmt.Sprintf("%s#page=%d", p.href.String(), page)))
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// Returns the destination of an outline item, either given directly or with a GoTo action.
+func (p *outlineParser) itemDestination(d types.Dict) types.Object {
+	if dest, ok := d["Dest"]; ok {
+		return dest
+	}
+	action, err := p.ctx.DereferenceDict(d["A"])
+	if err != nil || action == nil {
+		return nil
+	}
+	if s := action.NameEntry("S"); s == nil || *s != "GoTo" {
+		return nil
+	}
+	return action["D"]
+}
+
+// Resolves an explicit or named destination to a page number, or 0 if it can't be resolved.
+func (p *outlineParser) destinationPage(dest types.Object, depth int) int {
+	if depth > 4 { // Named destinations can't reasonably point to other named destinations
+		return 0
+	}
+	dest, err := p.ctx.Dereference(dest)
+	if err != nil || dest == nil {
+		return 0
+	}
+
+	switch dest := dest.(type) {
+	case types.Array:
+		// Explicit destination: [page /XYZ left top zoom]
+		if len(dest) == 0 {
+			return 0
+		}
+		if ir, ok := dest[0].(types.IndirectRef); ok {
+			return p.pages[ir.ObjectNumber.Value()]
+		}
+		return 0
+	case types.Dict:
+		// Destination dictionary of a named destination
+		return p.destinationPage(dest["D"], depth+1)
+	case types.Name:
+		// Named destination from the `Dests` dictionary of the catalog (PDF 1.1)
+		catalog, err := p.ctx.Catalog()
+		if err != nil {
+			return 0
+		}
+		dests, err := p.ctx.DereferenceDict(catalog["Dests"])
+		if err != nil || dests == nil {
+			return 0
+		}
+		return p.destinationPage(dests[dest.Value()], depth+1)
+	case types.StringLiteral, types.HexLiteral:
+		// Named destination from the `Dests` name tree (PDF 1.2)
+		name, err := model.Text(dest)
+		if err != nil {
+			return 0
+		}
+		catalog, err := p.ctx.Catalog()
+		if err != nil {
+			return 0
+		}
+		names, err := p.ctx.DereferenceDict(catalog["Names"])
+		if err != nil || names == nil {
+			return 0
+		}
+		return p.destinationPage(p.lookupNameTree(names["Dests"], name, 0), depth+1)
+	}
+	return 0
+}
+
+// Finds the value of the given key in a name tree.
+func (p *outlineParser) lookupNameTree(node types.Object, key string, depth int) types.Object {
+	if depth > maxOutlineDepth {
+		return nil
+	}
+	d, err := p.ctx.DereferenceDict(node)
+	if err != nil || d == nil {
+		return nil
+	}
+
+	if limits, err := p.ctx.DereferenceArray(d["Limits"]); err == nil && len(limits) == 2 {
+		lower, _ := p.ctx.DereferenceText(limits[0])
+		upper, _ := p.ctx.DereferenceText(limits[1])
+		if key < lower || key > upper {
+			return nil
+		}
+	}
+
+	if names, err := p.ctx.DereferenceArray(d["Names"]); err == nil {
+		for i := 0; i+1 < len(names); i += 2 {
+			if k, _ := p.ctx.DereferenceText(names[i]); k == key {
+				return names[i+1]
+			}
+		}
+	}
+
+	if kids, err := p.ctx.DereferenceArray(d["Kids"]); err == nil {
+		for _, kid := range kids {
+			if v := p.lookupNameTree(kid, key, depth+1); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// Maps the object number of each page to its page number, starting from 1.
+func pageNumbers(ctx *model.Context) map[int]int {
+	pages := make(map[int]int, ctx.PageCount)
+	root, err := ctx.Pages()
+	if err != nil || root == nil {
+		return pages
+	}
+
+	visited := make(map[int]struct{})
+	var walk func(ir types.IndirectRef, depth int)
+	walk = func(ir types.IndirectRef, depth int) {
+		if depth > maxOutlineDepth {
+			return
+		}
+		if _, ok := visited[ir.ObjectNumber.Value()]; ok {
+			return
+		}
+		visited[ir.ObjectNumber.Value()] = struct{}{}
+
+		d, err := ctx.DereferenceDict(ir)
+		if err != nil || d == nil {
+			return
+		}
+		kids, err := ctx.DereferenceArray(d["Kids"])
+		if err != nil || kids == nil {
+			// Leaf of the page tree
+			pages[ir.ObjectNumber.Value()] = len(pages) + 1
+			return
+		}
+		for _, kid := range kids {
+			if kir, ok := kid.(types.IndirectRef); ok {
+				walk(kir, depth+1)
+			}
+		}
+	}
+	walk(*root, 0)
+	return pages
+}
+
+// Removes the control characters sometimes found in outline titles.
+func cleanTitle(s string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < 32 {
+			return ' '
+		}
+		return r
+	}, s))
+}
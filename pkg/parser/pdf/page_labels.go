@@ -0,0 +1,156 @@
+package pdf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// A range of pages sharing the same labelling style, from the `PageLabels` number tree.
+type pageLabelRange struct {
+	start  int    // Index of the first page of the range, starting from 0.
+	style  string // Numbering style: D, R, r, A, a, or empty for labels without a number.
+	prefix string
+	first  int // Number of the first page of the range.
+}
+
+// Returns the label of each page of the PDF document, e.g. "iv" or "A-12",
+// as defined by the `PageLabels` number tree of its catalog.
+// Returns nil when the document doesn't define page labels.
+func ParsePageLabels(ctx *model.Context) []string {
+	if ctx.PageCount <= 0 {
+		return nil
+	}
+	catalog, err := ctx.Catalog()
+	if err != nil {
+		return nil
+	}
+	var ranges []pageLabelRange
+	collectPageLabelRanges(ctx, catalog["PageLabels"], &ranges, 0)
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+
+	labels := make([]string, ctx.PageCount)
+	r := -1
+	for i := range labels {
+		for r+1 < len(ranges) && ranges[r+1].start <= i {
+			r++
+		}
+		if r < 0 {
+			// Pages before the first range have no label, use their number.
+			labels[i] = strconv.Itoa(i + 1)
+			continue
+		}
+		rng := ranges[r]
+		labels[i] = rng.prefix + formatPageNumber(rng.first+i-rng.start, rng.style)
+	}
+	return labels
+}
+
+// Walks a node of the `PageLabels` number tree.
+func collectPageLabelRanges(ctx *model.Context, node types.Object, ranges *[]pageLabelRange, depth int) {
+	if depth > maxOutlineDepth {
+		return
+	}
+	d, err := ctx.DereferenceDict(node)
+	if err != nil || d == nil {
+		return
+	}
+
+	if nums, err := ctx.DereferenceArray(d["Nums"]); err == nil {
+		for i := 0; i+1 < len(nums); i += 2 {
+			start, err := ctx.DereferenceInteger(nums[i])
+			if err != nil || start == nil || start.Value() < 0 {
+				continue
+			}
+			label, err := ctx.DereferenceDict(nums[i+1])
+			if err != nil || label == nil {
+				continue
+			}
+			rng := pageLabelRange{
+				start: start.Value(),
+				first: 1,
+			}
+			if s := label.NameEntry("S"); s != nil {
+				rng.style = *s
+			}
+			if p, err := ctx.DereferenceText(label["P"]); err == nil {
+				rng.prefix = p
+			}
+			if st, err := ctx.DereferenceInteger(label["St"]); err == nil && st != nil && st.Value() > 0 {
+				rng.first = st.Value()
+			}
+			*ranges = append(*ranges, rng)
+		}
+	}
+
+	if kids, err := ctx.DereferenceArray(d["Kids"]); err == nil {
+		for _, kid := range kids {
+			collectPageLabelRanges(ctx, kid, ranges, depth+1)
+		}
+	}
+}
+
+// Formats a page number with one of the numbering styles of the PDF specification.
+func formatPageNumber(n int, style string) string {
+	switch style {
+	case "D":
+		return strconv.Itoa(n)
+	case "R":
+		return toRoman(n)
+	case "r":
+		return strings.ToLower(toRoman(n))
+	case "A":
+		return toLetters(n)
+	case "a":
+		return strings.ToLower(toLetters(n))
+	default:
+		return ""
+	}
+}
+
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+// Largest number written with roman numerals, or as letters, by [formatPageNumber]. Larger numbers
+// are written in decimal, to avoid building huge labels from a crafted start number.
+const (
+	maxRomanPageNumber  = 4999
+	maxLetterPageNumber = 26 * 100
+)
+
+func toRoman(n int) string {
+	if n <= 0 || n > maxRomanPageNumber {
+		return strconv.Itoa(n)
+	}
+	var sb strings.Builder
+	for _, r := range romanNumerals {
+		for n >= r.value {
+			sb.WriteString(r.symbol)
+			n -= r.value
+		}
+	}
+	return sb.String()
+}
+
+// A to Z for the first 26 pages, AA to ZZ for the next 26, and so on.
+func toLetters(n int) string {
+	if n <= 0 || n > maxLetterPageNumber {
+		return strconv.Itoa(n)
+	}
+	letter := string(rune('A' + (n-1)%26))
+	return strings.Repeat(letter, (n-1)/26+1)
+}
@@ -1,11 +1,14 @@
 package pdf
 
 import (
+	"io"
+
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/validate"
 	"github.com/pkg/errors"
 	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/content/iterator"
 	"github.com/readium/go-toolkit/pkg/fetcher"
 	"github.com/readium/go-toolkit/pkg/manifest"
 	"github.com/readium/go-toolkit/pkg/mediatype"
@@ -44,18 +47,11 @@ func (p Parser) Parse(asset asset.PublicationAsset, f fetcher.Fetcher) (*pub.Bui
 		return nil, errors.New("unable to find PDF file: no matching link found")
 	}
 
-	conf := model.NewDefaultConfiguration()
-	conf.ValidationMode = model.ValidationRelaxed
-	ctx, err := pdfcpu.Read(fetcher.NewResourceReadSeeker(f.Get(*link)), conf)
+	ctx, err := open(fetcher.NewResourceReadSeeker(f.Get(*link)))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed opening PDF")
+		return nil, err
 	}
 
-	// Clean up and prepare document
-	validate.XRefTable(ctx.XRefTable)
-	pdfcpu.OptimizeXRefTable(ctx)
-	ctx.EnsurePageCount()
-
 	m, err := ParseMetadata(ctx, link)
 
 	// Fallback title
@@ -66,6 +62,25 @@ func (p Parser) Parse(asset asset.PublicationAsset, f fetcher.Fetcher) (*pub.Bui
 	// Finalize
 	builder := pub.NewServicesBuilder(map[string]pub.ServiceFactory{
 		pub.PositionsService_Name: PositionsServiceFactory(),
+		pub.ContentService_Name: pub.DefaultContentServiceFactory([]iterator.ResourceContentIteratorFactory{
+			ContentIteratorFactory(),
+		}),
 	})
 	return pub.NewBuilder(m, f, builder), nil
 }
+
+// Reads and prepares the PDF document.
+func open(rs io.ReadSeeker) (*model.Context, error) {
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationRelaxed
+	ctx, err := pdfcpu.Read(rs, conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed opening PDF")
+	}
+
+	// Clean up and prepare document
+	validate.XRefTable(ctx.XRefTable)
+	pdfcpu.OptimizeXRefTable(ctx)
+	ctx.EnsurePageCount()
+	return ctx, nil
+}
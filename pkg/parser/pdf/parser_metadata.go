@@ -182,28 +182,32 @@ func ParsePDFMetadata(ctx *model.Context, m *manifest.Manifest) error {
 		}
 	}
 
+	href := url.MustURLFromString("")
+	if rootLink := m.ReadingOrder.FirstWithMediaType(&mediatype.PDF); rootLink != nil {
+		href = rootLink.URL(nil, nil)
+	}
+
 	// Bookmarks (TOC)
-	if bookmarks, err := pdfcpu.Bookmarks(ctx); err == nil {
-		rootLink := m.ReadingOrder.FirstWithMediaType(&mediatype.PDF)
-		var bf func(toc manifest.LinkList, bookmarks []pdfcpu.Bookmark)
-		bf = func(toc manifest.LinkList, bookmarks []pdfcpu.Bookmark) {
-			for _, b := range bookmarks {
-				lnk := manifest.Link{
-					Title:     b.Title,
-					MediaType: &mediatype.PDF,
-				}
-				if rootLink == nil {
-					lnk.Href = manifest.NewHREF(url.MustURLFromString(fmt.Sprintf("#page=%d", b.PageFrom)))
-				} else {
-					lnk.Href = manifest.NewHREF(url.MustURLFromString(fmt.Sprintf("%s#page=%d", rootLink.URL(nil, nil).String(), b.PageFrom)))
-				}
-				if len(b.Kids) > 0 {
-					bf(lnk.Children, b.Kids)
-				}
-				m.TableOfContents = append(m.TableOfContents, lnk)
+	if len(m.TableOfContents) == 0 {
+		m.TableOfContents = ParseOutline(ctx, href)
+	}
+
+	// Page labels (page list)
+	if labels := ParsePageLabels(ctx); len(labels) > 0 {
+		pageList := make(manifest.LinkList, len(labels))
+		for i, label := range labels {
+			pageList[i] = manifest.Link{
+				Href:      manifest.NewHREF(url.MustURLFromString(fmt.Sprintf("%s#page=%d", href.String(), i+1))),
+				MediaType: &mediatype.PDF,
+				Title:     label,
 			}
 		}
-		bf(m.TableOfContents, bookmarks)
+		if m.Subcollections == nil {
+			m.Subcollections = make(manifest.PublicationCollectionMap)
+		}
+		m.Subcollections["pageList"] = []manifest.PublicationCollection{{
+			Links: pageList,
+		}}
 	}
 
 	return nil
@@ -0,0 +1,117 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/stretchr/testify/assert"
+)
+
+func withPDFParser(t *testing.T, filepath string, f func(*pub.Publication)) {
+	a := asset.File(filepath)
+	fet, err := a.CreateFetcher(asset.Dependencies{
+		ArchiveFactory: archive.NewArchiveFactory(),
+	}, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	b, err := NewParser().Parse(a, fet)
+	if assert.NoError(t, err) && assert.NotNil(t, b) {
+		f(b.Build())
+	}
+}
+
+func tocSummary(ll manifest.LinkList) []interface{} {
+	var res []interface{}
+	for _, l := range ll {
+		res = append(res, l.Title+" "+l.Href.String())
+		if len(l.Children) > 0 {
+			res = append(res, tocSummary(l.Children))
+		}
+	}
+	return res
+}
+
+func TestPDFOutline(t *testing.T) {
+	withPDFParser(t, "./testdata/outline.pdf", func(p *pub.Publication) {
+		assert.Equal(t, []interface{}{
+			"Cover outline.pdf#page=1",
+			"Part I outline.pdf#page=2",
+			[]interface{}{
+				"Chapter 1 outline.pdf#page=2", // Named destination in the name tree
+				"Chapter 2 outline.pdf#page=3", // GoTo action with a UTF-16 title
+			},
+			"Appendix outline.pdf#page=4", // Named destination in the catalog
+		}, tocSummary(p.Manifest.TableOfContents))
+		assert.Equal(t, "application/pdf", p.Manifest.TableOfContents[0].MediaType.String())
+	})
+}
+
+func TestPDFPositionTitles(t *testing.T) {
+	withPDFParser(t, "./testdata/outline.pdf", func(p *pub.Publication) {
+		var titles []string
+		for _, l := range p.Positions() {
+			titles = append(titles, l.Title)
+		}
+		assert.Equal(t, []string{"Cover", "Part I", "Chapter 2", "Appendix"}, titles)
+	})
+}
+
+func TestPDFPageList(t *testing.T) {
+	withPDFParser(t, "./testdata/page_labels.pdf", func(p *pub.Publication) {
+		pageList := p.Manifest.Subcollections["pageList"]
+		if !assert.Len(t, pageList, 1) {
+			return
+		}
+		var labels []string
+		for _, l := range pageList[0].Links {
+			labels = append(labels, l.Title+" "+l.Href.String())
+		}
+		assert.Equal(t, []string{
+			"i page_labels.pdf#page=1",
+			"ii page_labels.pdf#page=2",
+			"5 page_labels.pdf#page=3",
+			"6 page_labels.pdf#page=4",
+			"App-A page_labels.pdf#page=5",
+			"App-B page_labels.pdf#page=6",
+		}, labels)
+	})
+}
+
+func TestPDFPositionPageLabels(t *testing.T) {
+	withPDFParser(t, "./testdata/page_labels.pdf", func(p *pub.Publication) {
+		var labels []interface{}
+		for _, l := range p.Positions() {
+			labels = append(labels, l.Locations.OtherLocations[PageLabelLocationKey])
+		}
+		assert.Equal(t, []interface{}{"i", "ii", "5", "6", "App-A", "App-B"}, labels)
+	})
+}
+
+func TestPDFWithoutPageLabels(t *testing.T) {
+	withPDFParser(t, "./testdata/outline.pdf", func(p *pub.Publication) {
+		assert.Empty(t, p.Manifest.Subcollections["pageList"])
+		assert.Nil(t, p.Positions()[0].Locations.OtherLocations)
+	})
+}
+
+func TestFormatPageNumber(t *testing.T) {
+	assert.Equal(t, "xiv", formatPageNumber(14, "r"))
+	assert.Equal(t, "MCMXCIV", formatPageNumber(1994, "R"))
+	assert.Equal(t, "z", formatPageNumber(26, "a"))
+	assert.Equal(t, "AA", formatPageNumber(27, "A"))
+	assert.Equal(t, "CCC", formatPageNumber(55, "A"))
+	assert.Equal(t, "", formatPageNumber(3, ""))
+
+	// Falls back on decimal numbers for huge start numbers
+	assert.Equal(t, "MMMMCMXCIX", formatPageNumber(4999, "R"))
+	assert.Equal(t, "5000", formatPageNumber(5000, "R"))
+	assert.Equal(t, "2000000000", formatPageNumber(2000000000, "r"))
+	assert.Equal(t, strings.Repeat("z", 100), formatPageNumber(2600, "a"))
+	assert.Equal(t, "2601", formatPageNumber(2601, "A"))
+	assert.Equal(t, "2000000000", formatPageNumber(2000000000, "A"))
+}
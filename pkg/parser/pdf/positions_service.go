@@ -10,11 +10,16 @@ import (
 	"github.com/readium/go-toolkit/pkg/util/url"
 )
 
+// Key of the page label in the [manifest.Locations] of a position, when the
+// PDF document defines page labels.
+const PageLabelLocationKey = "pageLabel"
+
 // Positions Service for an PDF.
 type PositionsService struct {
 	link            manifest.Link        // The [Link] to the PDF document in the [Publication].
 	pageCount       uint                 // Total page count in the PDF document.
 	tableOfContents manifest.LinkList    // Table of contents used to compute the position titles.
+	pageList        manifest.LinkList    // Page list used to compute the position page labels.
 	positions       [][]manifest.Locator // Cached calculated positions
 }
 
@@ -52,6 +57,11 @@ func (s *PositionsService) computePositions() [][]manifest.Locator {
 		return [][]manifest.Locator{}
 	}
 
+	toc := s.tableOfContents.Flatten()
+	labels := make(map[string]string, len(s.pageList))
+	for _, link := range s.pageList {
+		labels[link.URL(nil, nil).Normalize().String()] = link.Title
+	}
 	positions := make([][]manifest.Locator, s.pageCount)
 	for i := uint(0); i < s.pageCount; i++ {
 		progression := float64(i) / float64(s.pageCount)
@@ -65,20 +75,27 @@ func (s *PositionsService) computePositions() [][]manifest.Locator {
 		u := s.link.URL(nil, nil)
 
 		var title string
-		if link := s.tableOfContents.FirstWithHref(url.MustURLFromString(u.String() + "#" + fragment)); link != nil {
+		if link := toc.FirstWithHref(url.MustURLFromString(u.String() + "#" + fragment)); link != nil {
 			title = link.Title
 		}
 
+		locations := manifest.Locations{
+			Fragments:        []string{fragment},
+			Progression:      &progression,
+			TotalProgression: &progression,
+			Position:         &position,
+		}
+		if label := labels[url.MustURLFromString(u.String()+"#"+fragment).Normalize().String()]; label != "" {
+			locations.OtherLocations = map[string]interface{}{
+				PageLabelLocationKey: label,
+			}
+		}
+
 		positions[i] = []manifest.Locator{{
 			Href:      u,
 			MediaType: *typ,
-			Locations: manifest.Locations{
-				Fragments:        []string{fragment},
-				Progression:      &progression,
-				TotalProgression: &progression,
-				Position:         &position,
-			},
-			Title: title,
+			Locations: locations,
+			Title:     title,
 		}}
 	}
 	return positions
@@ -95,10 +112,16 @@ func PositionsServiceFactory() pub.ServiceFactory {
 			count = *context.Manifest.Metadata.NumberOfPages
 		}
 
+		var pageList manifest.LinkList
+		for _, c := range context.Manifest.Subcollections["pageList"] {
+			pageList = append(pageList, c.Links...)
+		}
+
 		return &PositionsService{
 			link:            context.Manifest.ReadingOrder[0],
 			pageCount:       count,
 			tableOfContents: context.Manifest.TableOfContents,
+			pageList:        pageList,
 		}
 	}
 }
@@ -0,0 +1,259 @@
+package pdf
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Maximum nesting of form XObjects drawn from a content stream.
+const maxFormDepth = 8
+
+// Extracts the text of a page, grouped in paragraphs.
+//
+// Glyph widths are not taken into account, so the paragraphs are only an
+// approximation of the layout: text runs on the same baseline form a line,
+// and consecutive lines are merged unless they are separated by a gap larger
+// than the font size.
+func extractPageParagraphs(ctx *model.Context, pageNr int) ([]string, error) {
+	page, _, attrs, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return nil, err
+	}
+	if page == nil {
+		return nil, nil
+	}
+	content, err := ctx.PageContent(page)
+	if err != nil {
+		if errors.Is(err, model.ErrNoContent) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var resources types.Dict
+	if attrs != nil {
+		resources = attrs.Resources
+	}
+	e := &textExtractor{ctx: ctx}
+	e.run(content, resources, 0)
+	e.endParagraph()
+	return e.paragraphs, nil
+}
+
+type textExtractor struct {
+	ctx        *model.Context
+	paragraphs []string
+	current    strings.Builder
+
+	// Text state
+	tm, tlm  [6]float64 // Text matrix and text line matrix
+	font     *fontDecoder
+	fontSize float64
+	leading  float64
+
+	// Position of the end of the last text run, in text space
+	hasLast       bool
+	lastX, lastY  float64
+	lastLineSize  float64
+	pendingSpaces bool
+}
+
+var identityMatrix = [6]float64{1, 0, 0, 1, 0, 0}
+
+func (e *textExtractor) run(content []byte, resources types.Dict, depth int) {
+	decoders := loadFontDecoders(e.ctx, resources)
+	l := newContentLexer(content)
+	for {
+		op, ok := l.next()
+		if !ok {
+			return
+		}
+		args := op.operands
+		switch op.name {
+		case "BT":
+			e.tm, e.tlm = identityMatrix, identityMatrix
+		case "Tf":
+			if len(args) >= 2 {
+				if name, ok := args[0].(pdfName); ok {
+					e.font = decoders[string(name)]
+				}
+				e.fontSize = number(args[1])
+			}
+		case "TL":
+			if len(args) >= 1 {
+				e.leading = number(args[0])
+			}
+		case "Td":
+			if len(args) >= 2 {
+				e.moveLine(number(args[0]), number(args[1]))
+			}
+		case "TD":
+			if len(args) >= 2 {
+				e.leading = -number(args[1])
+				e.moveLine(number(args[0]), number(args[1]))
+			}
+		case "Tm":
+			if len(args) >= 6 {
+				for i := range e.tm {
+					e.tm[i] = number(args[i])
+				}
+				e.tlm = e.tm
+			}
+		case "T*":
+			e.moveLine(0, -e.leading)
+		case "Tj":
+			if len(args) >= 1 {
+				e.show(args[0])
+			}
+		case "'":
+			e.moveLine(0, -e.leading)
+			if len(args) >= 1 {
+				e.show(args[0])
+			}
+		case "\"":
+			e.moveLine(0, -e.leading)
+			if len(args) >= 3 {
+				e.show(args[2])
+			}
+		case "TJ":
+			if len(args) >= 1 {
+				if arr, ok := args[0].([]interface{}); ok {
+					for _, item := range arr {
+						if n, ok := item.(float64); ok {
+							// A large negative adjustment (in thousandths of em) is a word space
+							if n < -200 {
+								e.pendingSpaces = true
+							}
+							e.tm[4] -= n / 1000 * e.fontSize * e.tm[0]
+							continue
+						}
+						e.show(item)
+					}
+				}
+			}
+		case "Do":
+			if len(args) >= 1 && depth < maxFormDepth {
+				if name, ok := args[0].(pdfName); ok {
+					e.drawForm(string(name), resources, depth)
+				}
+			}
+		}
+	}
+}
+
+func number(o interface{}) float64 {
+	if f, ok := o.(float64); ok {
+		return f
+	}
+	return 0
+}
+
+// Moves to the start of the next line, offset from the start of the current line.
+func (e *textExtractor) moveLine(tx, ty float64) {
+	e.tlm[4] += tx*e.tlm[0] + ty*e.tlm[2]
+	e.tlm[5] += tx*e.tlm[1] + ty*e.tlm[3]
+	e.tm = e.tlm
+}
+
+// Appends a text run at the current position.
+func (e *textExtractor) show(o interface{}) {
+	s, ok := o.([]byte)
+	if !ok {
+		return
+	}
+	text, ok := e.font.decode(s)
+	if !ok || text == "" {
+		return
+	}
+
+	size := math.Abs(e.fontSize * e.tm[3])
+	if size == 0 {
+		size = math.Abs(e.fontSize * e.tm[0])
+	}
+	if size == 0 {
+		size = 1
+	}
+	x, y := e.tm[4], e.tm[5]
+
+	if e.hasLast {
+		dy := math.Abs(y - e.lastY)
+		switch {
+		case dy < size/2:
+			// Same line, separated by a space if there is a gap
+			if e.pendingSpaces || x > e.lastX+size/4 {
+				e.space()
+			}
+		case dy <= math.Max(size, e.lastLineSize)*1.8:
+			// Next line of the same paragraph
+			e.space()
+		default:
+			e.endParagraph()
+		}
+	}
+	e.current.WriteString(text)
+	e.pendingSpaces = false
+
+	// Estimates the width of the run, as glyph widths are not known
+	advance := float64(utf8.RuneCountInString(text)) * e.fontSize * 0.5 * e.tm[0]
+	e.tm[4] += advance
+	e.hasLast = true
+	e.lastX, e.lastY = e.tm[4], y
+	e.lastLineSize = size
+}
+
+func (e *textExtractor) space() {
+	s := e.current.String()
+	if s == "" {
+		return
+	}
+	r, _ := utf8.DecodeLastRuneInString(s)
+	if !unicode.IsSpace(r) {
+		e.current.WriteByte(' ')
+	}
+}
+
+func (e *textExtractor) endParagraph() {
+	text := strings.Join(strings.Fields(e.current.String()), " ")
+	if text != "" {
+		e.paragraphs = append(e.paragraphs, text)
+	}
+	e.current.Reset()
+	e.hasLast = false
+	e.pendingSpaces = false
+}
+
+// Extracts the text of a form XObject, which has its own content stream.
+func (e *textExtractor) drawForm(name string, resources types.Dict, depth int) {
+	if resources == nil {
+		return
+	}
+	xobjects, err := e.ctx.DereferenceDict(resources["XObject"])
+	if err != nil || xobjects == nil {
+		return
+	}
+	sd, _, err := e.ctx.DereferenceStreamDict(xobjects[name])
+	if err != nil || sd == nil {
+		return
+	}
+	if subtype := sd.Dict.NameEntry("Subtype"); subtype == nil || *subtype != "Form" {
+		return
+	}
+	if err := sd.Decode(); err != nil {
+		return
+	}
+	formResources, err := e.ctx.DereferenceDict(sd.Dict["Resources"])
+	if err != nil || formResources == nil {
+		formResources = resources
+	}
+
+	// The text state is not shared with the form
+	tm, tlm, font, fontSize, leading := e.tm, e.tlm, e.font, e.fontSize, e.leading
+	e.run(sd.Content, formResources, depth+1)
+	e.tm, e.tlm, e.font, e.fontSize, e.leading = tm, tlm, font, fontSize, leading
+}
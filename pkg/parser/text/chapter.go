@@ -0,0 +1,183 @@
+package text
+
+import (
+	"fmt"
+	"html"
+	gurl "net/url"
+	"strconv"
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/manifest"
+)
+
+// Chapter of a text publication, rendered as an XHTML document of the reading order.
+type chapter struct {
+	Title    string    // Empty for an untitled chapter, e.g. a preamble
+	Body     string    // XHTML content of the <body>
+	Sections []section // Titled sections of the chapter
+}
+
+// Heading of a chapter, targeted by the table of contents.
+type section struct {
+	ID    string
+	Title string
+	Level int
+}
+
+// HREF of the generated document of the chapter at [index].
+func chapterHref(index int) string {
+	return fmt.Sprintf("chapter-%03d.xhtml", index+1)
+}
+
+// Splits a Markdown document into chapters, at the headings of the highest level. A single
+// heading of that level opening the document is the title of the book, so the document is split
+// at the following level instead, and the title is returned.
+func splitMarkdown(s string) (chapters []chapter, title string) {
+	lines := strings.Split(normalizeNewlines(s), "\n")
+	for i, line := range lines {
+		lines[i] = expandTabs(line)
+	}
+	blocks := parseBlocks(lines)
+
+	// Unique IDs of the headings
+	ids := make(map[string]int)
+	var levels []int
+	for i := range blocks {
+		if blocks[i].Kind != blockHeading {
+			continue
+		}
+		id := slug(plainText(blocks[i].Text))
+		if id == "" {
+			id = "section"
+		}
+		if n, ok := ids[id]; ok {
+			ids[id] = n + 1
+			id += "-" + strconv.Itoa(n)
+		}
+		ids[id]++
+		blocks[i].ID = id
+		levels = append(levels, blocks[i].Level)
+	}
+
+	splitLevel := 0
+	if len(levels) > 0 {
+		splitLevel = levels[0]
+		for _, l := range levels {
+			splitLevel = min(splitLevel, l)
+		}
+		count := 0
+		deeper := 0
+		for _, l := range levels {
+			if l == splitLevel {
+				count++
+			} else if deeper == 0 || l < deeper {
+				deeper = l
+			}
+		}
+		if count == 1 && deeper > 0 && blocks[0].Kind == blockHeading && blocks[0].Level == splitLevel {
+			title = plainText(blocks[0].Text)
+			splitLevel = deeper
+		}
+	}
+
+	// Splits the blocks into chapters, and indexes the chapter of each heading ID.
+	var parts [][]block
+	chapterOfID := make(map[string]int)
+	for _, b := range blocks {
+		if len(parts) == 0 || (b.Kind == blockHeading && b.Level <= splitLevel && len(parts[len(parts)-1]) > 0) {
+			parts = append(parts, nil)
+		}
+		parts[len(parts)-1] = append(parts[len(parts)-1], b)
+		if b.Kind == blockHeading {
+			chapterOfID[b.ID] = len(parts) - 1
+		}
+	}
+
+	for i, part := range parts {
+		r := inlineRenderer{resolve: func(href string) string {
+			id, ok := strings.CutPrefix(href, "#")
+			if !ok {
+				return href
+			}
+			if j, ok := chapterOfID[id]; ok && j != i {
+				return (&gurl.URL{Path: chapterHref(j), Fragment: id}).String()
+			}
+			return href
+		}}
+		var c chapter
+		var body strings.Builder
+		renderBlocks(&body, part, r)
+		c.Body = body.String()
+		for j, b := range part {
+			if b.Kind != blockHeading {
+				continue
+			}
+			if j == 0 {
+				c.Title = plainText(b.Text)
+			} else {
+				c.Sections = append(c.Sections, section{ID: b.ID, Title: plainText(b.Text), Level: b.Level})
+			}
+		}
+		chapters = append(chapters, c)
+	}
+	return
+}
+
+// Builds the table of contents from the [chapters] titles and their sections.
+func tableOfContents(chapters []chapter) manifest.LinkList {
+	var toc manifest.LinkList
+	for i, c := range chapters {
+		href := chapterHref(i)
+		sections := nestSections(href, c.Sections)
+		if c.Title == "" {
+			toc = append(toc, sections...)
+			continue
+		}
+		toc = append(toc, manifest.Link{
+			Href:     manifest.MustNewHREFFromString(href, false),
+			Title:    c.Title,
+			Children: sections,
+		})
+	}
+	return toc
+}
+
+// Nests the flat list of [sections] according to their level.
+func nestSections(href string, sections []section) manifest.LinkList {
+	var links manifest.LinkList
+	for i := 0; i < len(sections); {
+		j := i + 1
+		for j < len(sections) && sections[j].Level > sections[i].Level {
+			j++
+		}
+		links = append(links, manifest.Link{
+			Href:     manifest.MustNewHREFFromString((&gurl.URL{Path: href, Fragment: sections[i].ID}).String(), false),
+			Title:    sections[i].Title,
+			Children: nestSections(href, sections[i+1:j]),
+		})
+		i = j
+	}
+	return links
+}
+
+// Renders the XHTML document of a chapter.
+func renderChapter(c chapter, title string, lang string) []byte {
+	if c.Title != "" {
+		title = c.Title
+	}
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString("<!DOCTYPE html>\n")
+	sb.WriteString(`<html xmlns="http://www.w3.org/1999/xhtml"`)
+	if lang != "" {
+		sb.WriteString(` lang="` + escape(lang) + `" xml:lang="` + escape(lang) + `"`)
+	}
+	sb.WriteString(">\n<head>\n<title>" + escape(title) + "</title>\n</head>\n<body>\n")
+	sb.WriteString(c.Body)
+	sb.WriteString("</body>\n</html>\n")
+	return []byte(sb.String())
+}
+
+func escape(s string) string {
+	return html.EscapeString(s)
+}
@@ -0,0 +1,63 @@
+package text
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Decodes the text [data] into UTF-8, using the [declared] encoding if any, or a detected one.
+func decode(data []byte, declared encoding.Encoding) string {
+	enc := declared
+	if enc == nil {
+		enc = detectEncoding(data)
+	}
+	// A byte order mark takes precedence over any other detection.
+	decoded, _, err := transform.Bytes(unicode.BOMOverride(enc.NewDecoder()), data)
+	if err != nil {
+		return string(data)
+	}
+	return string(decoded)
+}
+
+// Guesses the encoding of [data] lacking a byte order mark: UTF-16 when a quarter of the bytes are
+// null, UTF-8 when valid, otherwise a legacy 8-bit encoding.
+func detectEncoding(data []byte) encoding.Encoding {
+	sample := data
+	if len(sample) > 4096 {
+		sample = sample[:4096]
+	}
+	var evenNulls, oddNulls, high, upper int
+	for i, b := range sample {
+		switch {
+		case b == 0 && i%2 == 0:
+			evenNulls++
+		case b == 0:
+			oddNulls++
+		case b >= 0xC0:
+			upper++
+			high++
+		case b >= 0x80:
+			high++
+		}
+	}
+	if oddNulls > len(sample)/4 {
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	}
+	if evenNulls > len(sample)/4 {
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	}
+	if utf8.Valid(data) {
+		return unicode.UTF8
+	}
+
+	// Letters of the Cyrillic alphabet are in the upper half of the Windows-1251 code page and make
+	// most of a text, while Latin texts use only a few accented letters.
+	if upper*10 >= high*7 && high*3 >= len(sample) {
+		return charmap.Windows1251
+	}
+	return charmap.Windows1252
+}
@@ -0,0 +1,445 @@
+package text
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Kind of a Markdown block.
+type blockKind uint8
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockCode
+	blockQuote
+	blockList
+	blockRule
+)
+
+// Block of a Markdown document, following the CommonMark block structure.
+// https://spec.commonmark.org/0.31.2/#blocks-and-inlines
+type block struct {
+	Kind     blockKind
+	Level    int    // Heading level
+	Text     string // Inline content, or the literal content of a code block
+	ID       string // Heading ID
+	Ordered  bool
+	Start    int       // Number of the first item of an ordered list
+	Items    [][]block // List items
+	Children []block   // Blockquote content
+}
+
+var (
+	atxHeading    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	setextLine    = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	thematicBreak = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	codeFence     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	listMarker    = regexp.MustCompile(`^( {0,3})([-*+]|(\d{1,9})[.)])([ \t]+|$)`)
+	quoteMarker   = regexp.MustCompile(`^ {0,3}> ?`)
+)
+
+// Parses the block structure of the Markdown [lines].
+func parseBlocks(lines []string) []block {
+	var blocks []block
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, block{Kind: blockParagraph, Text: strings.Join(paragraph, "\n")})
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case codeFence.MatchString(line):
+			flush()
+			marker := codeFence.FindStringSubmatch(line)[1]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), marker); i++ {
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, block{Kind: blockCode, Text: strings.Join(code, "\n")})
+		case atxHeading.MatchString(line):
+			flush()
+			m := atxHeading.FindStringSubmatch(line)
+			blocks = append(blocks, block{Kind: blockHeading, Level: len(m[1]), Text: m[2]})
+		case len(paragraph) > 0 && setextLine.MatchString(line):
+			level := 2
+			if strings.TrimSpace(line)[0] == '=' {
+				level = 1
+			}
+			blocks = append(blocks, block{Kind: blockHeading, Level: level, Text: strings.Join(paragraph, " ")})
+			paragraph = nil
+		case thematicBreak.MatchString(line):
+			flush()
+			blocks = append(blocks, block{Kind: blockRule})
+		case quoteMarker.MatchString(line):
+			flush()
+			var quote []string
+			for ; i < len(lines) && quoteMarker.MatchString(lines[i]); i++ {
+				quote = append(quote, quoteMarker.ReplaceAllString(lines[i], ""))
+			}
+			i--
+			blocks = append(blocks, block{Kind: blockQuote, Children: parseBlocks(quote)})
+		case listMarker.MatchString(line):
+			flush()
+			var list block
+			list, i = parseList(lines, i)
+			i--
+			blocks = append(blocks, list)
+		case len(paragraph) == 0 && leadingSpaces(line) >= 4:
+			var code []string
+			for ; i < len(lines) && (leadingSpaces(lines[i]) >= 4 || strings.TrimSpace(lines[i]) == ""); i++ {
+				code = append(code, dedent(lines[i], 4))
+			}
+			i--
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			blocks = append(blocks, block{Kind: blockCode, Text: strings.Join(code, "\n")})
+		default:
+			paragraph = append(paragraph, strings.TrimSpace(line))
+		}
+	}
+	flush()
+	return blocks
+}
+
+// Parses the list starting at the line [i], returning the index of the line following it.
+func parseList(lines []string, i int) (block, int) {
+	m := listMarker.FindStringSubmatch(lines[i])
+	list := block{Kind: blockList, Ordered: m[3] != ""}
+	if list.Ordered {
+		list.Start, _ = strconv.Atoi(m[3])
+	}
+
+	for i < len(lines) {
+		m := listMarker.FindStringSubmatch(lines[i])
+		if m == nil || (m[3] != "") != list.Ordered {
+			break
+		}
+		// Continuation lines are indented to the content of the first line.
+		indent := len(m[0])
+		if m[4] == "" {
+			indent++
+		}
+		item := []string{lines[i][len(m[0]):]}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				item = append(item, "")
+			} else if leadingSpaces(line) >= indent {
+				item = append(item, dedent(line, indent))
+			} else if item[len(item)-1] != "" && !listMarker.MatchString(line) && !isBlockStart(line) {
+				item = append(item, strings.TrimSpace(line)) // Lazy continuation of a paragraph
+			} else {
+				break
+			}
+		}
+		list.Items = append(list.Items, parseBlocks(item))
+	}
+	return list, i
+}
+
+func isBlockStart(line string) bool {
+	return atxHeading.MatchString(line) || codeFence.MatchString(line) || thematicBreak.MatchString(line) || quoteMarker.MatchString(line)
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// Removes up to [n] spaces of indentation.
+func dedent(line string, n int) string {
+	return line[min(n, leadingSpaces(line)):]
+}
+
+// Expands the tabs of the indentation of [line] into spaces, with a tab stop of 4 characters.
+func expandTabs(line string) string {
+	var sb strings.Builder
+	col := 0
+	for i, r := range line {
+		switch r {
+		case ' ':
+			sb.WriteByte(' ')
+			col++
+		case '\t':
+			n := 4 - col%4
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+		default:
+			return sb.String() + line[i:]
+		}
+	}
+	return sb.String()
+}
+
+// Splits a YAML front matter from the Markdown document [s], returning its simple key: value
+// entries.
+func splitFrontMatter(s string) (map[string]string, string) {
+	if !strings.HasPrefix(s, "---\n") {
+		return nil, s
+	}
+	end := strings.Index(s[4:], "\n---")
+	if end < 0 {
+		return nil, s
+	}
+	matter := make(map[string]string)
+	for _, line := range strings.Split(s[4:4+end], "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, " ") {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if value != "" {
+			matter[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+	rest := s[4+end+4:]
+	if i := strings.Index(rest, "\n"); i >= 0 {
+		rest = rest[i+1:]
+	} else {
+		rest = ""
+	}
+	return matter, rest
+}
+
+// Renders the Markdown [blocks] into XHTML.
+func renderBlocks(sb *strings.Builder, blocks []block, r inlineRenderer) {
+	for _, b := range blocks {
+		switch b.Kind {
+		case blockParagraph:
+			sb.WriteString("<p>" + r.render(b.Text) + "</p>\n")
+		case blockHeading:
+			tag := "h" + strconv.Itoa(b.Level)
+			sb.WriteString("<" + tag)
+			if b.ID != "" {
+				sb.WriteString(` id="` + escape(b.ID) + `"`)
+			}
+			sb.WriteString(">" + r.render(b.Text) + "</" + tag + ">\n")
+		case blockCode:
+			sb.WriteString("<pre><code>" + escape(b.Text) + "</code></pre>\n")
+		case blockQuote:
+			sb.WriteString("<blockquote>\n")
+			renderBlocks(sb, b.Children, r)
+			sb.WriteString("</blockquote>\n")
+		case blockRule:
+			sb.WriteString("<hr/>\n")
+		case blockList:
+			tag := "ul"
+			if b.Ordered {
+				tag = "ol"
+			}
+			sb.WriteString("<" + tag)
+			if b.Ordered && b.Start != 1 {
+				sb.WriteString(` start="` + strconv.Itoa(b.Start) + `"`)
+			}
+			sb.WriteString(">\n")
+			for _, item := range b.Items {
+				sb.WriteString("<li>")
+				if len(item) == 1 && item[0].Kind == blockParagraph {
+					sb.WriteString(r.render(item[0].Text)) // Tight list
+				} else {
+					renderBlocks(sb, item, r)
+				}
+				sb.WriteString("</li>\n")
+			}
+			sb.WriteString("</" + tag + ">\n")
+		}
+	}
+}
+
+// Renders the inline content of Markdown blocks: emphasis, code spans, links, images and hard line
+// breaks. Raw HTML is escaped.
+type inlineRenderer struct {
+	resolve func(href string) string // Resolves the destination of links
+}
+
+func (r inlineRenderer) render(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			sb.WriteString(escape(s[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			sb.WriteString("<br/>\n")
+			i += 2
+			continue
+		case c == ' ' && strings.HasPrefix(s[i:], "  \n"):
+			sb.WriteString("<br/>\n")
+			i += 3
+			continue
+		case c == '`':
+			n := runLength(s[i:], '`')
+			if end := strings.Index(s[i+n:], strings.Repeat("`", n)); end >= 0 {
+				code := strings.ReplaceAll(s[i+n:i+n+end], "\n", " ")
+				if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+					code = code[1 : len(code)-1]
+				}
+				sb.WriteString("<code>" + escape(code) + "</code>")
+				i += 2*n + end
+				continue
+			}
+			sb.WriteString(s[i : i+n])
+			i += n
+			continue
+		case c == '!' && strings.HasPrefix(s[i:], "!["):
+			if text, dest, title, n, ok := parseLink(s[i+1:]); ok {
+				sb.WriteString(`<img src="` + escape(dest) + `" alt="` + escape(plainText(text)) + `"`)
+				if title != "" {
+					sb.WriteString(` title="` + escape(title) + `"`)
+				}
+				sb.WriteString("/>")
+				i += 1 + n
+				continue
+			}
+		case c == '[':
+			if text, dest, title, n, ok := parseLink(s[i:]); ok {
+				if r.resolve != nil {
+					dest = r.resolve(dest)
+				}
+				sb.WriteString(`<a href="` + escape(dest) + `"`)
+				if title != "" {
+					sb.WriteString(` title="` + escape(title) + `"`)
+				}
+				sb.WriteString(">" + r.render(text) + "</a>")
+				i += n
+				continue
+			}
+		case c == '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				dest := s[i+1 : i+end]
+				if !strings.ContainsAny(dest, " \t\n<") && (strings.Contains(dest, "://") || strings.HasPrefix(dest, "mailto:")) {
+					sb.WriteString(`<a href="` + escape(dest) + `">` + escape(strings.TrimPrefix(dest, "mailto:")) + "</a>")
+					i += end + 1
+					continue
+				}
+			}
+		case c == '*' || c == '_':
+			n := min(runLength(s[i:], c), 2)
+			opening := i+n < len(s) && !unicode.IsSpace(rune(s[i+n]))
+			if c == '_' && i > 0 && isAlphanumeric(s[i-1]) {
+				opening = false // No intraword emphasis with underscores
+			}
+			if opening {
+				if end := closingDelimiter(s[i+n:], c, n); end > 0 {
+					tag := "em"
+					if n == 2 {
+						tag = "strong"
+					}
+					sb.WriteString("<" + tag + ">" + r.render(s[i+n:i+n+end]) + "</" + tag + ">")
+					i += 2*n + end
+					continue
+				}
+			}
+			sb.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		sb.WriteString(escape(s[i : i+1]))
+		i++
+	}
+	return sb.String()
+}
+
+// Parses a link starting at [s] with [text](destination "title"), returning the length of the
+// link.
+func parseLink(s string) (text string, dest string, title string, n int, ok bool) {
+	depth := 0
+	closing := -1
+	for i := 0; i < len(s) && closing < 0; i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closing = i
+			}
+		}
+	}
+	if closing < 0 || closing+1 >= len(s) || s[closing+1] != '(' {
+		return
+	}
+	end := strings.IndexByte(s[closing+1:], ')')
+	if end < 0 {
+		return
+	}
+	inner := strings.TrimSpace(s[closing+2 : closing+1+end])
+	dest, title, _ = strings.Cut(inner, " ")
+	dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+	title = strings.Trim(strings.TrimSpace(title), `"'`)
+	return s[1:closing], dest, title, closing + 2 + end, true
+}
+
+// Finds the delimiter run of [n] [c] closing an emphasis in [s].
+func closingDelimiter(s string, c byte, n int) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '`':
+			// Code spans take precedence over emphasis.
+			m := runLength(s[i:], '`')
+			if end := strings.Index(s[i+m:], strings.Repeat("`", m)); end >= 0 {
+				i += 2*m + end - 1
+			}
+		case c:
+			m := runLength(s[i:], c)
+			if m >= n && !unicode.IsSpace(rune(s[i-1])) && (c != '_' || i+m >= len(s) || !isAlphanumeric(s[i+m])) {
+				return i
+			}
+			i += m - 1
+		}
+	}
+	return -1
+}
+
+func runLength(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+func isASCIIPunct(c byte) bool {
+	return c < 0x80 && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+var markdownLink = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+
+// Strips the Markdown syntax of an inline content, e.g. to title a heading in the TOC.
+func plainText(s string) string {
+	s = markdownLink.ReplaceAllString(s, "$1")
+	s = strings.NewReplacer("\\", "", "*", "", "`", "").Replace(s)
+	s = strings.Trim(s, "_")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Generates the ID of a heading from its [title], like GitHub does.
+func slug(title string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteByte('-')
+		}
+	}
+	return sb.String()
+}
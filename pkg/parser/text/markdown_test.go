@@ -0,0 +1,63 @@
+package text
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func renderMarkdown(s string) string {
+	var sb strings.Builder
+	renderBlocks(&sb, parseBlocks(strings.Split(s, "\n")), inlineRenderer{})
+	return sb.String()
+}
+
+func TestMarkdownInlines(t *testing.T) {
+	r := inlineRenderer{}
+	assert.Equal(t, "<em>a</em> <strong>b</strong> <em>c</em> <strong>d</strong>", r.render("*a* **b** _c_ __d__"))
+	assert.Equal(t, "snake_case_name", r.render("snake_case_name"))
+	assert.Equal(t, "2 * 3 * 4", r.render("2 * 3 * 4"))
+	assert.Equal(t, "<code>*not emphasis*</code>", r.render("`*not emphasis*`"))
+	assert.Equal(t, "*literal*", r.render(`\*literal\*`))
+	assert.Equal(t, `<a href="https://example.com" title="Title">a <em>link</em></a>`, r.render(`[a *link*](https://example.com "Title")`))
+	assert.Equal(t, `<img src="image.png" alt="An image"/>`, r.render("![An image](image.png)"))
+	assert.Equal(t, `<a href="https://example.com">https://example.com</a>`, r.render("<https://example.com>"))
+	assert.Equal(t, "&lt;b&gt;raw&lt;/b&gt;", r.render("<b>raw</b>"))
+	assert.Equal(t, "line<br/>\nbreak", r.render("line  \nbreak"))
+}
+
+func TestMarkdownHeadings(t *testing.T) {
+	assert.Equal(t, "<h1>Title</h1>\n<h2>Setext</h2>\n<h3>Closed</h3>\n", renderMarkdown("# Title\nSetext\n---\n### Closed ###"))
+}
+
+func TestMarkdownCodeBlocks(t *testing.T) {
+	assert.Equal(t, "<pre><code>a &lt; b\n\nc</code></pre>\n", renderMarkdown("```go\na < b\n\nc\n```"))
+	assert.Equal(t, "<p>text</p>\n<pre><code>indented</code></pre>\n", renderMarkdown("text\n\n    indented\n"))
+}
+
+func TestMarkdownLists(t *testing.T) {
+	assert.Equal(t, "<ol start=\"3\">\n<li>three</li>\n<li>four\ncontinued</li>\n</ol>\n", renderMarkdown("3. three\n4. four\n   continued"))
+	assert.Equal(t, "<ul>\n<li><p>item</p>\n<ul>\n<li>nested</li>\n</ul>\n</li>\n</ul>\n<p>after</p>\n", renderMarkdown("- item\n  - nested\n\nafter"))
+}
+
+func TestMarkdownBlockquoteAndRule(t *testing.T) {
+	assert.Equal(t, "<blockquote>\n<p>quoted\ntext</p>\n</blockquote>\n<hr/>\n", renderMarkdown("> quoted\n> text\n\n***"))
+}
+
+func TestMarkdownHeadingIDs(t *testing.T) {
+	chapters, title := splitMarkdown("## Intro\n\n## Intro\n\n## Ça va? Oui!")
+	assert.Equal(t, "", title)
+	if assert.Len(t, chapters, 3) {
+		assert.Contains(t, chapters[0].Body, `id="intro"`)
+		assert.Contains(t, chapters[1].Body, `id="intro-1"`)
+		assert.Contains(t, chapters[2].Body, `id="ça-va-oui"`)
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	assert.Equal(t, "héllo", decode([]byte("héllo"), nil))
+	assert.Equal(t, "héllo", decode([]byte("h\xe9llo"), nil))
+	assert.Equal(t, "привет мир", decode([]byte("\xef\xf0\xe8\xe2\xe5\xf2 \xec\xe8\xf0"), nil))
+	assert.Equal(t, "hi", decode([]byte("h\x00i\x00"), nil))
+}
@@ -0,0 +1,134 @@
+package text
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/parser/epub"
+	"github.com/readium/go-toolkit/pkg/pub"
+)
+
+// Handles parsing of plain text and Markdown documents.
+// The document is split into chapters at its headings, or at the form feeds of a plain text, which
+// are rendered as XHTML documents served by a fetcher replacing the original one.
+type Parser struct{}
+
+func NewParser() Parser {
+	return Parser{}
+}
+
+// Parse implements PublicationParser
+func (p Parser) Parse(asset asset.PublicationAsset, f fetcher.Fetcher) (*pub.Builder, error) {
+	mt := asset.MediaType()
+	markdown := mediatype.Markdown.Contains(&mt)
+	if !markdown && !mediatype.Text.Contains(&mt) {
+		return nil, nil
+	}
+
+	links, err := f.Links()
+	if err != nil {
+		return nil, err
+	}
+	var link *manifest.Link
+	for _, l := range links {
+		if !extensions.IsHiddenOrThumbs(l.URL(nil, nil).Path()) {
+			link = &l
+			break
+		}
+	}
+	if link == nil {
+		return nil, errors.New("no text document found in the publication")
+	}
+	data, rerr := f.Get(*link).Read(0, 0)
+	if rerr != nil {
+		return nil, errors.Wrap(rerr.Cause, "failed reading text document")
+	}
+	s := decode(data, mt.Charset())
+
+	var metadata manifest.Metadata
+	var chapters []chapter
+	if markdown {
+		var matter map[string]string
+		var title string
+		matter, s = splitFrontMatter(normalizeNewlines(s))
+		chapters, title = splitMarkdown(s)
+		metadata = frontMatterMetadata(matter)
+		if metadata.LocalizedTitle.Length() == 0 && title != "" {
+			metadata.LocalizedTitle = manifest.NewLocalizedStringFromString(title)
+		}
+	} else {
+		chapters = splitPlainText(s)
+	}
+	if len(chapters) == 0 {
+		chapters = []chapter{{}}
+	}
+	if metadata.LocalizedTitle.Length() == 0 {
+		metadata.LocalizedTitle = manifest.NewLocalizedStringFromString(strings.TrimSuffix(asset.Name(), path.Ext(asset.Name())))
+	}
+	layout := manifest.EPUBLayoutReflowable
+	metadata.Presentation = &manifest.Presentation{Layout: &layout}
+	var lang string
+	if len(metadata.Languages) > 0 {
+		lang = metadata.Languages[0]
+	}
+
+	tf := fetcher.NewBytesFetcher()
+	var readingOrder manifest.LinkList
+	for i, c := range chapters {
+		link := manifest.Link{
+			Href:      manifest.MustNewHREFFromString(chapterHref(i), false),
+			MediaType: &mediatype.XHTML,
+		}
+		readingOrder = append(readingOrder, link)
+		tf.Add(link, func() []byte {
+			return renderChapter(c, metadata.Title(), lang)
+		})
+	}
+	f.Close() // The whole document is in memory from now on.
+
+	toc := tableOfContents(chapters)
+	if len(toc) == 0 {
+		toc = manifest.LinkList{{
+			Href:  readingOrder[0].Href,
+			Title: metadata.Title(),
+		}}
+	}
+	m := manifest.Manifest{
+		Context:         manifest.Strings{manifest.WebpubManifestContext},
+		Metadata:        metadata,
+		ReadingOrder:    readingOrder,
+		TableOfContents: toc,
+	}
+
+	builder := pub.NewServicesBuilder(map[string]pub.ServiceFactory{
+		pub.PositionsService_Name: epub.PositionsServiceFactory(epub.OriginalLength{PageLength: 1024}),
+	})
+	return pub.NewBuilder(m, tf, builder), nil
+}
+
+// Maps the title, author, language, date and description of a Markdown front matter.
+func frontMatterMetadata(matter map[string]string) manifest.Metadata {
+	var metadata manifest.Metadata
+	if title := matter["title"]; title != "" {
+		metadata.LocalizedTitle = manifest.NewLocalizedStringFromString(title)
+	}
+	if author := matter["author"]; author != "" {
+		metadata.Authors = manifest.Contributors{{
+			LocalizedName: manifest.NewLocalizedStringFromString(author),
+		}}
+	}
+	for _, key := range []string{"lang", "language"} {
+		if lang := matter[key]; lang != "" {
+			metadata.Languages = manifest.Strings{lang}
+		}
+	}
+	metadata.Published = extensions.ParseDate(matter["date"])
+	metadata.Description = matter["description"]
+	return metadata
+}
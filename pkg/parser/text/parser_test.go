@@ -0,0 +1,126 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/archive"
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/stretchr/testify/assert"
+)
+
+func withParser(t *testing.T, filepath string, f func(*pub.Publication)) {
+	a := asset.File(filepath)
+	fet, err := a.CreateFetcher(asset.Dependencies{
+		ArchiveFactory: archive.NewArchiveFactory(),
+	}, "")
+	assert.NoError(t, err)
+	p, err := NewParser().Parse(a, fet)
+	if assert.NoError(t, err) && assert.NotNil(t, p) {
+		f(p.Build())
+	}
+}
+
+func hrefs(links manifest.LinkList) []string {
+	res := make([]string, 0, len(links))
+	for _, l := range links {
+		res = append(res, l.Title+" "+l.Href.String())
+	}
+	return res
+}
+
+func readString(t *testing.T, p *pub.Publication, href string) string {
+	data, err := p.Get(manifest.Link{Href: manifest.MustNewHREFFromString(href, false)}).Read(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestTextSplitByChapterHeadings(t *testing.T) {
+	withParser(t, "./testdata/book.txt", func(p *pub.Publication) {
+		assert.Equal(t, "book", p.Manifest.Metadata.Title())
+		assert.Equal(t, []string{
+			" chapter-001.xhtml",
+			" chapter-002.xhtml",
+			" chapter-003.xhtml",
+		}, hrefs(p.Manifest.ReadingOrder))
+		assert.Equal(t, []string{
+			"CHAPTER I. The Beginning chapter-002.xhtml",
+			"CHAPTER II. The End chapter-003.xhtml",
+		}, hrefs(p.Manifest.TableOfContents))
+
+		chapter := readString(t, p, "chapter-002.xhtml")
+		assert.Contains(t, chapter, "<title>CHAPTER I. The Beginning</title>")
+		assert.Contains(t, chapter, "<h1>CHAPTER I. The Beginning</h1>")
+		assert.Contains(t, chapter, "<p>It was a dark and stormy night; the rain fell in torrents, except at\noccasional intervals.</p>")
+		assert.Contains(t, chapter, "<p>Then &lt;everything&gt; &amp; more stopped.</p>")
+		for _, link := range p.Manifest.ReadingOrder {
+			assert.Equal(t, &mediatype.XHTML, link.MediaType)
+			_, err := p.Get(link).ReadAsXML(nil)
+			assert.Nil(t, err, "%s is not well-formed", link.Href)
+		}
+		assert.Len(t, p.Positions(), 3)
+	})
+}
+
+func TestTextSplitByFormFeeds(t *testing.T) {
+	withParser(t, "./testdata/formfeed.txt", func(p *pub.Publication) {
+		assert.Len(t, p.Manifest.ReadingOrder, 2)
+		// Only the pages following a form feed are titled.
+		assert.Equal(t, []string{"Second page chapter-002.xhtml"}, hrefs(p.Manifest.TableOfContents))
+	})
+}
+
+func TestTextLegacyEncoding(t *testing.T) {
+	withParser(t, "./testdata/cp1251.txt", func(p *pub.Publication) {
+		assert.Equal(t, []string{
+			"Глава 1 chapter-001.xhtml",
+			"Глава 2 chapter-002.xhtml",
+		}, hrefs(p.Manifest.TableOfContents))
+		assert.Contains(t, readString(t, p, "chapter-001.xhtml"), "Жили-были дед да баба.")
+	})
+}
+
+func TestTextByteOrderMark(t *testing.T) {
+	withParser(t, "./testdata/utf16.txt", func(p *pub.Publication) {
+		assert.Contains(t, readString(t, p, "chapter-001.xhtml"), "<p>Hello, wörld!</p>")
+		// Untitled text gets a single TOC entry.
+		assert.Equal(t, []string{"utf16 chapter-001.xhtml"}, hrefs(p.Manifest.TableOfContents))
+	})
+}
+
+func TestMarkdownFrontMatter(t *testing.T) {
+	withParser(t, "./testdata/book.md", func(p *pub.Publication) {
+		m := p.Manifest.Metadata
+		assert.Equal(t, "A Markdown Book", m.Title())
+		assert.Equal(t, "Jane Doe", m.Authors[0].Name())
+		assert.Equal(t, manifest.Strings{"en"}, m.Languages)
+	})
+}
+
+func TestMarkdownSplitByHeadings(t *testing.T) {
+	withParser(t, "./testdata/book.md", func(p *pub.Publication) {
+		assert.Len(t, p.Manifest.ReadingOrder, 3)
+		toc := p.Manifest.TableOfContents
+		assert.Equal(t, []string{
+			"Ignored Title chapter-001.xhtml",
+			"First Chapter chapter-002.xhtml",
+			"Second Chapter chapter-003.xhtml",
+		}, hrefs(toc))
+		assert.Equal(t, []string{"A Section chapter-002.xhtml#a-section"}, hrefs(toc[1].Children))
+
+		chapter := readString(t, p, "chapter-002.xhtml")
+		assert.Contains(t, chapter, `<html xmlns="http://www.w3.org/1999/xhtml" lang="en" xml:lang="en">`)
+		assert.Contains(t, chapter, `<h2 id="first-chapter">First Chapter</h2>`)
+		assert.Contains(t, chapter, `<a href="chapter-003.xhtml#second-chapter">link</a>`)
+		assert.Contains(t, chapter, "<code>code &lt;b&gt;</code>")
+		assert.Contains(t, chapter, "<ul>\n<li>one</li>\n<li>two</li>\n</ul>")
+		for _, link := range p.Manifest.ReadingOrder {
+			_, err := p.Get(link).ReadAsXML(nil)
+			assert.Nil(t, err, "%s is not well-formed", link.Href)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package text
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Lines opening a chapter in a plain text, e.g. "CHAPTER IV." or "Глава 2".
+var chapterHeading = regexp.MustCompile(`^(?i:chapter|part|book|prologue|epilogue|preface|introduction|afterword|appendix|глава|часть|книга|пролог|эпилог|предисловие|послесловие|розділ|частина|rozdział|część|kapitola|část|fejezet|capitolul)(?:\s|[.:]|$)`)
+
+const maxHeadingLength = 80
+
+// Splits a plain text into chapters, at form feeds and at the lines looking like a chapter heading.
+func splitPlainText(s string) []chapter {
+	s = normalizeNewlines(s)
+	// Texts without any blank line use one line per paragraph.
+	linePerParagraph := !strings.Contains(s, "\n\n")
+
+	var chapters []chapter
+	for i, page := range strings.Split(s, "\f") {
+		var lines []string
+		pageStart := true
+		flush := func() {
+			// A page started by a form feed is titled by its first line.
+			if c, ok := plainTextChapter(lines, i > 0 && pageStart, linePerParagraph); ok {
+				chapters = append(chapters, c)
+				pageStart = false
+			}
+			lines = nil
+		}
+		for _, line := range strings.Split(page, "\n") {
+			if isChapterHeading(line) && (len(lines) == 0 || strings.TrimSpace(lines[len(lines)-1]) == "") {
+				flush()
+			}
+			lines = append(lines, line)
+		}
+		flush()
+	}
+	return chapters
+}
+
+func isChapterHeading(line string) bool {
+	line = strings.TrimSpace(line)
+	return line != "" && utf8.RuneCountInString(line) <= maxHeadingLength && chapterHeading.MatchString(line)
+}
+
+// Renders the [lines] of a chapter, whose first non-blank line is its title when [titled] is true
+// or when it's a chapter heading. Returns false if there's no content.
+func plainTextChapter(lines []string, titled bool, linePerParagraph bool) (chapter, bool) {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return chapter{}, false
+	}
+
+	var c chapter
+	var body strings.Builder
+	first := strings.TrimSpace(lines[0])
+	if (titled || isChapterHeading(first)) && utf8.RuneCountInString(first) <= maxHeadingLength {
+		c.Title = first
+		body.WriteString("<h1>" + escape(first) + "</h1>\n")
+		lines = lines[1:]
+	}
+
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			body.WriteString("<p>" + escape(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		paragraph = append(paragraph, strings.TrimSpace(line))
+		if linePerParagraph {
+			flush()
+		}
+	}
+	flush()
+	c.Body = body.String()
+	return c, true
+}
+
+func normalizeNewlines(s string) string {
+	return strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(s)
+}
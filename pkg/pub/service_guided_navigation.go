@@ -2,6 +2,7 @@ package pub
 
 import (
 	"encoding/json"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"github.com/readium/go-toolkit/pkg/fetcher"
@@ -11,10 +12,21 @@ import (
 )
 
 var GuidedNavigationLink = manifest.Link{
-	Href:      manifest.MustNewHREFFromString("~readium/guided-navigation.json{?ref}", true),
+	Href:      manifest.MustNewHREFFromString("~readium/guided-navigation.json{?ref,page}", true),
 	MediaType: &mediatype.ReadiumGuidedNavigationDocument,
 }
 
+// Returns the [GuidedNavigationLink] to the document of the resource with the given [ref] HREF,
+// with the given relations, e.g. "next".
+func GuidedNavigationRefLink(ref string, rels ...string) manifest.Link {
+	l := GuidedNavigationLink
+	l.Href = manifest.NewHREF(l.URL(nil, map[string]string{
+		"ref": ref,
+	}))
+	l.Rels = rels
+	return l
+}
+
 // Pre-cached value of the guided navigation link's path
 var resolvedGuidedNavigation url.URL
 
@@ -38,12 +50,11 @@ func GetForGuidedNavigationService(service GuidedNavigationService, link manifes
 		return nil, false
 	}
 
-	ref := u.Raw().Query().Get("ref")
+	query := u.Raw().Query()
+	ref := query.Get("ref")
 	if ref == "" {
-		// No ref parameter
-		// TODO: support omission of ref to generate entire doc.
-		// Waiting for guided navigation cache implementation to make this feasible
-		return nil, false
+		// Without a ref parameter, the document of the whole publication is requested
+		return getPublicationGuidedNavigation(service, link, query.Get("page"))
 	}
 
 	// Overrride the link's href with the expanded guided navigation link
@@ -71,3 +82,37 @@ func GetForGuidedNavigationService(service GuidedNavigationService, link manifes
 		return bin
 	}), true
 }
+
+func getPublicationGuidedNavigation(service GuidedNavigationService, link manifest.Link, page string) (fetcher.Resource, bool) {
+	ps, ok := service.(PublicationGuidedNavigationService)
+	if !ok {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	p := 0
+	if page != "" {
+		params["page"] = page
+		var err error
+		if p, err = strconv.Atoi(page); err != nil || p < 1 {
+			return fetcher.NewFailureResource(link, fetcher.BadRequest(
+				errors.Errorf("invalid guided navigation page %q", page),
+			)), true
+		}
+	}
+	link.Href = manifest.NewHREF(GuidedNavigationLink.URL(nil, params))
+
+	doc, err := ps.GuideForPublication(p)
+	if err != nil {
+		return fetcher.NewFailureResource(link, fetcher.Other(err)), true
+	}
+	if doc == nil {
+		return fetcher.NewFailureResource(link, fetcher.NotFound(
+			errors.New("guided navigation page not found"),
+		)), true
+	}
+	return fetcher.NewBytesResource(link, func() []byte {
+		bin, _ := json.Marshal(doc)
+		return bin
+	}), true
+}
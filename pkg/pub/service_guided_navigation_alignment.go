@@ -0,0 +1,299 @@
+package pub
+
+import (
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/content/element"
+	"github.com/readium/go-toolkit/pkg/content/iterator"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/util/alignment"
+	"github.com/readium/go-toolkit/pkg/util/url"
+)
+
+// Sidecar file aligning the text of a reading order resource with its narration.
+type AlignmentSidecar struct {
+	Text      string        // HREF of the aligned HTML resource of the reading order.
+	Audio     string        // HREF of the audio resource narrating the text.
+	Alignment manifest.Link // Aeneas JSON sync map, WebVTT or SubRip file, served by the publication fetcher.
+}
+
+// AlignmentGuidedNavigationService implements GuidedNavigationService
+// Synchronizes the text of HTML resources with their narration from sidecar alignment files,
+// for publications without media overlays.
+type AlignmentGuidedNavigationService struct {
+	fetcher      fetcher.Fetcher
+	readingOrder manifest.LinkList
+	sidecars     map[string]AlignmentSidecar
+	hrefs        []string // Resources having a sidecar, in reading order
+}
+
+func (s *AlignmentGuidedNavigationService) Close() {
+	clear(s.sidecars)
+}
+
+func (s *AlignmentGuidedNavigationService) Links() manifest.LinkList {
+	return manifest.LinkList{GuidedNavigationLink}
+}
+
+func (s *AlignmentGuidedNavigationService) Get(link manifest.Link) (fetcher.Resource, bool) {
+	return GetForGuidedNavigationService(s, link)
+}
+
+func (s *AlignmentGuidedNavigationService) HasGuideForResource(href string) bool {
+	_, ok := s.sidecars[href]
+	return ok
+}
+
+func (s *AlignmentGuidedNavigationService) GuideForResource(href string) (*manifest.GuidedNavigationDocument, error) {
+	sidecar, ok := s.sidecars[href]
+	if !ok {
+		return nil, nil
+	}
+
+	cues, err := s.readCues(sidecar.Alignment)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := s.textElements(href)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &manifest.GuidedNavigationDocument{
+		Guided: alignedGuidedNavigationObjects(href, sidecar.Audio, AlignCues(elements, cues)),
+	}
+	for i, h := range s.hrefs {
+		if h != href {
+			continue
+		}
+		if i > 0 {
+			doc.Links = append(doc.Links, GuidedNavigationRefLink(s.hrefs[i-1], "prev"))
+		}
+		if i < len(s.hrefs)-1 {
+			doc.Links = append(doc.Links, GuidedNavigationRefLink(s.hrefs[i+1], "next"))
+		}
+	}
+	return doc, nil
+}
+
+func (s *AlignmentGuidedNavigationService) readCues(link manifest.Link) ([]alignment.Cue, error) {
+	res := s.fetcher.Get(link)
+	defer res.Close()
+	data, rerr := res.Read(0, 0)
+	if rerr != nil {
+		return nil, errors.Wrapf(rerr.Cause, "failed reading alignment file %s", link.Href)
+	}
+
+	ext := path.Ext(link.URL(nil, nil).Path())
+	switch {
+	case link.MediaType != nil && link.MediaType.Equal(&mediatype.WebVTT), link.MediaType == nil && ext == ".vtt":
+		return alignment.ParseWebVTT(data)
+	case link.MediaType != nil && link.MediaType.Equal(&mediatype.JSON), link.MediaType == nil && ext == ".json":
+		return alignment.ParseAeneas(data)
+	case link.MediaType != nil && link.MediaType.Equal(&mediatype.SubRip), link.MediaType == nil && ext == ".srt":
+		return alignment.ParseSRT(data)
+	}
+	return nil, errors.Errorf("unsupported alignment file %s", link.Href)
+}
+
+// Extracts the text elements of the resource [href] with the HTML content iterator.
+func (s *AlignmentGuidedNavigationService) textElements(href string) ([]element.TextElement, error) {
+	u, err := url.URLFromString(href)
+	if err != nil {
+		return nil, err
+	}
+	link := s.readingOrder.FirstWithHref(u)
+	if link == nil {
+		return nil, errors.Errorf("%s is not in the reading order", href)
+	}
+	mt := link.MediaType
+	if mt == nil {
+		mt = &mediatype.HTML
+	}
+
+	res := s.fetcher.Get(*link)
+	defer res.Close()
+	it := iterator.NewHTML(res, manifest.Locator{Href: link.URL(nil, nil), MediaType: *mt})
+	var elements []element.TextElement
+	for {
+		hasNext, err := it.HasNext()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed iterating the content of %s", href)
+		}
+		if !hasNext {
+			return elements, nil
+		}
+		if el, ok := it.Next().(element.TextElement); ok {
+			elements = append(elements, el)
+		}
+	}
+}
+
+func alignedGuidedNavigationObjects(href string, audio string, cues []AlignedCue) []manifest.GuidedNavigationObject {
+	objects := make([]manifest.GuidedNavigationObject, 0, len(cues))
+	for _, c := range cues {
+		end := c.Cue.End
+		o := manifest.GuidedNavigationObject{
+			AudioRef: audio + "#" + manifest.TemporalFragment{Start: c.Cue.Start, End: &end}.String(),
+			TextRef:  href,
+			Text:     c.Cue.Text,
+		}
+		if c.Locator != nil {
+			if sel := c.Locator.Locations.CSSSelector(); isIDSelector(sel) {
+				o.TextRef += sel
+			}
+		}
+		if len(c.Children) > 0 {
+			o.Children = alignedGuidedNavigationObjects(href, audio, c.Children)
+		}
+		objects = append(objects, o)
+	}
+	return objects
+}
+
+// Returns whether the CSS selector targets a single element by its ID, e.g. #p1.
+func isIDSelector(selector string) bool {
+	return len(selector) > 1 && selector[0] == '#' && !strings.ContainsAny(selector[1:], " >+~.,:[\\#")
+}
+
+// Cue of an alignment file matched with the text of a resource.
+type AlignedCue struct {
+	Cue      alignment.Cue
+	Locator  *manifest.Locator // Locator of the aligned text, or nil when it was not found.
+	Children []AlignedCue      // Aligned children of the cue.
+}
+
+// AlignCues matches the [cues] of an alignment file with the text [elements] of a resource,
+// extracted by the content iterator.
+//
+// A cue is matched with the element or segment having its ID, or else with the next element
+// containing its text, ignoring case, punctuation and whitespaces.
+func AlignCues(elements []element.TextElement, cues []alignment.Cue) []AlignedCue {
+	a := &cueAligner{
+		elements: elements,
+		texts:    make([]string, len(elements)),
+		ids:      make(map[string]alignedID),
+	}
+	for i, el := range elements {
+		a.texts[i] = normalizeAlignedText(el.Text())
+		a.addID(i, el.Locator())
+		for _, seg := range el.Segments() {
+			a.addID(i, seg.Locator)
+		}
+	}
+	return a.align(cues)
+}
+
+type alignedID struct {
+	index   int // Index of the element
+	locator manifest.Locator
+}
+
+type cueAligner struct {
+	elements []element.TextElement
+	texts    []string // Normalized text of the elements
+	ids      map[string]alignedID
+
+	// Position in the normalized texts after the last aligned cue.
+	index  int
+	offset int
+}
+
+func (a *cueAligner) addID(index int, locator manifest.Locator) {
+	if sel := locator.Locations.CSSSelector(); isIDSelector(sel) {
+		if _, ok := a.ids[sel[1:]]; !ok {
+			a.ids[sel[1:]] = alignedID{index: index, locator: locator}
+		}
+	}
+}
+
+func (a *cueAligner) align(cues []alignment.Cue) []AlignedCue {
+	aligned := make([]AlignedCue, 0, len(cues))
+	for _, cue := range cues {
+		ac := AlignedCue{Cue: cue}
+		text := normalizeAlignedText(cue.Text)
+
+		endIndex, endOffset := a.index, a.offset
+		if id, ok := a.ids[cue.ID]; cue.ID != "" && ok {
+			locator := id.locator
+			ac.Locator = &locator
+			a.index, a.offset = id.index, 0
+			endIndex, endOffset = id.index, 0
+			if pos := strings.Index(a.texts[id.index], text); text != "" && pos >= 0 {
+				a.offset = pos
+				endOffset = pos + len(text)
+			}
+		} else if index, pos, ok := a.find(text); ok {
+			locator := a.elements[index].Locator()
+			locator.Text = manifest.Text{Highlight: cue.Text}
+			ac.Locator = &locator
+			a.index, a.offset = index, pos
+			endIndex, endOffset = index, pos+len(text)
+		}
+
+		// The children are looked up from the start of their parent.
+		if len(cue.Children) > 0 {
+			ac.Children = a.align(cue.Children)
+		}
+		if endIndex > a.index || (endIndex == a.index && endOffset > a.offset) {
+			a.index, a.offset = endIndex, endOffset
+		}
+		aligned = append(aligned, ac)
+	}
+	return aligned
+}
+
+// Finds the next occurrence of the normalized [text] from the current position.
+func (a *cueAligner) find(text string) (index int, pos int, ok bool) {
+	if text == "" {
+		return 0, 0, false
+	}
+	for i := a.index; i < len(a.texts); i++ {
+		from := 0
+		if i == a.index {
+			from = min(a.offset, len(a.texts[i]))
+		}
+		if pos := strings.Index(a.texts[i][from:], text); pos >= 0 {
+			return i, from + pos, true
+		}
+	}
+	return 0, 0, false
+}
+
+// Lowercases the text and replaces punctuation and whitespaces with single spaces.
+func normalizeAlignedText(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// Creates an [AlignmentGuidedNavigationService] synchronizing the resources of the reading order
+// with their narration, from the given alignment [sidecars]. The generated documents are cached.
+func AlignmentGuidedNavigationServiceFactory(sidecars []AlignmentSidecar) ServiceFactory {
+	return CachedGuidedNavigationServiceFactory(func(context Context) Service {
+		if len(sidecars) == 0 {
+			return nil
+		}
+		byText := make(map[string]AlignmentSidecar, len(sidecars))
+		for _, s := range sidecars {
+			byText[s.Text] = s
+		}
+		var hrefs []string
+		for _, link := range context.Manifest.ReadingOrder {
+			if _, ok := byText[link.Href.String()]; ok {
+				hrefs = append(hrefs, link.Href.String())
+			}
+		}
+		return &AlignmentGuidedNavigationService{
+			fetcher:      context.Fetcher,
+			readingOrder: context.Manifest.ReadingOrder,
+			sidecars:     byText,
+			hrefs:        hrefs,
+		}
+	})
+}
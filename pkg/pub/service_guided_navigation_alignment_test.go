@@ -0,0 +1,131 @@
+package pub
+
+import (
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAlignedPublication(sidecars []AlignmentSidecar) *Publication {
+	f := fetcher.NewBytesFetcher()
+	add := func(href string, mt *mediatype.MediaType, content string) manifest.Link {
+		link := manifest.Link{Href: manifest.MustNewHREFFromString(href, false), MediaType: mt}
+		f.Add(link, func() []byte { return []byte(content) })
+		return link
+	}
+	m := manifest.Manifest{
+		ReadingOrder: manifest.LinkList{
+			add("c1.xhtml", &mediatype.XHTML, `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><body>
+<h1 id="title">Loomings</h1>
+<p id="p1">Call me Ishmael. Some years ago, never mind how long precisely.</p>
+<p>It is a way I have of driving off the <span id="spleen">spleen</span>.</p>
+</body></html>`),
+			add("c2.xhtml", &mediatype.XHTML, `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><body><p id="c2p1">The Carpet-Bag.</p></body></html>`),
+		},
+	}
+	add("c1.json", &mediatype.JSON, `{"fragments": [
+		{"begin": "0", "end": "1", "id": "title", "lines": ["Loomings"]},
+		{"begin": "1", "end": "2.5", "id": "f2", "lines": ["Call me Ishmael."], "children": [
+			{"begin": "1", "end": "1.5", "id": "w1", "lines": ["Call"]},
+			{"begin": "1.5", "end": "2", "id": "w2", "lines": ["me"]},
+			{"begin": "2", "end": "2.5", "id": "w3", "lines": ["Ishmael"]}
+		]},
+		{"begin": "2.5", "end": "5", "id": "f3", "lines": ["Some years ago - never mind how long precisely -"]},
+		{"begin": "5", "end": "6", "id": "f4", "lines": ["Missing sentence."]},
+		{"begin": "6", "end": "9", "id": "f5", "lines": ["It is a way I have of driving off the spleen."]},
+		{"begin": "9", "end": "9.5", "id": "spleen", "lines": ["spleen"]}
+	]}`)
+	add("c2.vtt", nil, "WEBVTT\n\n00:00.000 --> 00:02.000\nThe Carpet-Bag.\n")
+
+	return New(m, f, NewServicesBuilder(map[string]ServiceFactory{
+		GuidedNavigationService_Name: AlignmentGuidedNavigationServiceFactory(sidecars),
+	}))
+}
+
+func TestAlignmentGuidedNavigationService(t *testing.T) {
+	p := newAlignedPublication([]AlignmentSidecar{
+		{Text: "c2.xhtml", Audio: "c2.mp3", Alignment: manifest.Link{Href: manifest.MustNewHREFFromString("c2.vtt", false)}},
+		{Text: "c1.xhtml", Audio: "audio/c1.mp3", Alignment: manifest.Link{Href: manifest.MustNewHREFFromString("c1.json", false), MediaType: &mediatype.JSON}},
+	})
+	service, ok := p.FindService(GuidedNavigationService_Name).(GuidedNavigationService)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.True(t, service.HasGuideForResource("c1.xhtml"))
+	assert.False(t, service.HasGuideForResource("c3.xhtml"))
+
+	doc, err := service.GuideForResource("c1.xhtml")
+	if !assert.NoError(t, err) || !assert.NotNil(t, doc) {
+		return
+	}
+	assert.Equal(t, []manifest.GuidedNavigationObject{
+		{AudioRef: "audio/c1.mp3#t=0,1", TextRef: "c1.xhtml#title", Text: "Loomings"},
+		{AudioRef: "audio/c1.mp3#t=1,2.5", TextRef: "c1.xhtml#p1", Text: "Call me Ishmael.", Children: []manifest.GuidedNavigationObject{
+			{AudioRef: "audio/c1.mp3#t=1,1.5", TextRef: "c1.xhtml#p1", Text: "Call"},
+			{AudioRef: "audio/c1.mp3#t=1.5,2", TextRef: "c1.xhtml#p1", Text: "me"},
+			{AudioRef: "audio/c1.mp3#t=2,2.5", TextRef: "c1.xhtml#p1", Text: "Ishmael"},
+		}},
+		{AudioRef: "audio/c1.mp3#t=2.5,5", TextRef: "c1.xhtml#p1", Text: "Some years ago - never mind how long precisely -"},
+		{AudioRef: "audio/c1.mp3#t=5,6", TextRef: "c1.xhtml", Text: "Missing sentence."},
+		{AudioRef: "audio/c1.mp3#t=6,9", TextRef: "c1.xhtml", Text: "It is a way I have of driving off the spleen."},
+		{AudioRef: "audio/c1.mp3#t=9,9.5", TextRef: "c1.xhtml", Text: "spleen"},
+	}, doc.Guided)
+	if assert.Len(t, doc.Links, 1) {
+		assert.Equal(t, manifest.Strings{"next"}, doc.Links[0].Rels)
+		assert.Equal(t, "~readium/guided-navigation.json?ref=c2.xhtml", doc.Links[0].Href.String())
+	}
+
+	doc, err = service.GuideForResource("c2.xhtml")
+	assert.NoError(t, err)
+	if assert.NotNil(t, doc) {
+		assert.Equal(t, []manifest.GuidedNavigationObject{
+			{AudioRef: "c2.mp3#t=0,2", TextRef: "c2.xhtml#c2p1", Text: "The Carpet-Bag."},
+		}, doc.Guided)
+	}
+
+	// Clips can be looked up in the generated documents.
+	clip, err := service.(*CachedGuidedNavigationService).ClipAtAudioTime("audio/c1.mp3", 1.7)
+	assert.NoError(t, err)
+	if assert.NotNil(t, clip) {
+		assert.Equal(t, "me", clip.Object.Text)
+	}
+}
+
+func TestAlignCuesLocators(t *testing.T) {
+	p := newAlignedPublication([]AlignmentSidecar{
+		{Text: "c1.xhtml", Audio: "c1.mp3", Alignment: manifest.Link{Href: manifest.MustNewHREFFromString("c1.json", false)}},
+	})
+	service := p.FindService(GuidedNavigationService_Name).(*CachedGuidedNavigationService).service.(*AlignmentGuidedNavigationService)
+	elements, err := service.textElements("c1.xhtml")
+	if !assert.NoError(t, err) {
+		return
+	}
+	cues, err := service.readCues(manifest.Link{Href: manifest.MustNewHREFFromString("c1.json", false)})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	aligned := AlignCues(elements, cues)
+	if assert.Len(t, aligned, 6) {
+		assert.Equal(t, "#p1", aligned[1].Locator.Locations.CSSSelector())
+		assert.Equal(t, "Call me Ishmael.", aligned[1].Locator.Text.Highlight)
+		assert.Equal(t, "Ishmael", aligned[1].Children[2].Locator.Text.Highlight)
+		assert.Nil(t, aligned[3].Locator)
+		if assert.NotNil(t, aligned[4].Locator) {
+			assert.Equal(t, "c1.xhtml", aligned[4].Locator.Href.String())
+			assert.NotEqual(t, "#p1", aligned[4].Locator.Locations.CSSSelector())
+		}
+	}
+}
+
+func TestGuidedNavigationRefLink(t *testing.T) {
+	l := GuidedNavigationRefLink("text/c 1.xhtml", "prev")
+	assert.Equal(t, "~readium/guided-navigation.json?ref=text%2Fc%201.xhtml", l.Href.String())
+	assert.Equal(t, manifest.Strings{"prev"}, l.Rels)
+	assert.Equal(t, &mediatype.ReadiumGuidedNavigationDocument, l.MediaType)
+}
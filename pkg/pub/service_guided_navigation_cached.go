@@ -0,0 +1,211 @@
+package pub
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+)
+
+// Number of top-level objects in a page of the guided navigation document of a publication.
+const GuidedNavigationPageSize = 100
+
+// PublicationGuidedNavigationService implements GuidedNavigationService
+// Also provides the guided navigation document of the whole publication.
+type PublicationGuidedNavigationService interface {
+	GuidedNavigationService
+	// Returns the documents of the resources merged in reading order, or only the given [page] of
+	// [GuidedNavigationPageSize] objects when [page] is at least 1. Returns nil if there is no
+	// such page.
+	GuideForPublication(page int) (*manifest.GuidedNavigationDocument, error)
+}
+
+// Guided navigation object found in the document of a resource.
+type GuidedNavigationClip struct {
+	Ref    string                          // HREF of the resource whose guided navigation document holds the object.
+	Object manifest.GuidedNavigationObject // Object matching the lookup.
+}
+
+// CachedGuidedNavigationService implements PublicationGuidedNavigationService
+// Caches the documents of another [GuidedNavigationService], to serve the document of the whole
+// publication and to look up clips without parsing the documents again.
+type CachedGuidedNavigationService struct {
+	service GuidedNavigationService
+	refs    []string // Resources having a document, in reading order
+
+	mu        sync.Mutex
+	documents map[string]*manifest.GuidedNavigationDocument
+}
+
+func (s *CachedGuidedNavigationService) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.documents)
+	s.service.Close()
+}
+
+func (s *CachedGuidedNavigationService) Links() manifest.LinkList {
+	return s.service.Links()
+}
+
+func (s *CachedGuidedNavigationService) Get(link manifest.Link) (fetcher.Resource, bool) {
+	return GetForGuidedNavigationService(s, link)
+}
+
+func (s *CachedGuidedNavigationService) HasGuideForResource(href string) bool {
+	return s.service.HasGuideForResource(href)
+}
+
+func (s *CachedGuidedNavigationService) GuideForResource(href string) (*manifest.GuidedNavigationDocument, error) {
+	doc, err := s.guide(href)
+	if doc == nil || err != nil {
+		return nil, err
+	}
+	cp := *doc
+	return &cp, nil
+}
+
+func (s *CachedGuidedNavigationService) guide(href string) (*manifest.GuidedNavigationDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if doc, ok := s.documents[href]; ok {
+		return doc, nil
+	}
+	doc, err := s.service.GuideForResource(href)
+	if err != nil {
+		return nil, err
+	}
+	s.documents[href] = doc
+	return doc, nil
+}
+
+func (s *CachedGuidedNavigationService) GuideForPublication(page int) (*manifest.GuidedNavigationDocument, error) {
+	if page < 0 {
+		return nil, errors.Errorf("invalid guided navigation page %d", page)
+	}
+	start, end := 0, -1
+	if page > 0 {
+		start = (page - 1) * GuidedNavigationPageSize
+		end = start + GuidedNavigationPageSize
+	}
+
+	guided := []manifest.GuidedNavigationObject{}
+	total := 0
+	for _, ref := range s.refs {
+		if end >= 0 && total > end {
+			// The following pages are not needed.
+			break
+		}
+		doc, err := s.guide(ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed loading the guided navigation document of %s", ref)
+		}
+		if doc == nil {
+			continue
+		}
+		for _, o := range doc.Guided {
+			if total >= start && (end < 0 || total < end) {
+				guided = append(guided, o)
+			}
+			total++
+		}
+	}
+
+	doc := &manifest.GuidedNavigationDocument{Guided: guided}
+	if page == 0 {
+		return doc, nil
+	}
+	if page > 1 && start >= total {
+		return nil, nil
+	}
+	if page > 1 {
+		doc.Links = append(doc.Links, guidedNavigationPageLink(page-1, "prev"))
+	}
+	if total > end {
+		doc.Links = append(doc.Links, guidedNavigationPageLink(page+1, "next"))
+	}
+	return doc, nil
+}
+
+func guidedNavigationPageLink(page int, rels ...string) manifest.Link {
+	l := GuidedNavigationLink
+	l.Href = manifest.NewHREF(l.URL(nil, map[string]string{
+		"page": strconv.Itoa(page),
+	}))
+	l.Rels = rels
+	return l
+}
+
+// Finds the deepest object referencing the audio resource [href] at the given [time], in seconds.
+func (s *CachedGuidedNavigationService) ClipAtAudioTime(href string, time float64) (*GuidedNavigationClip, error) {
+	return s.findClip(func(o manifest.GuidedNavigationObject) bool {
+		file := o.AudioFile()
+		if file == nil || file.String() != href {
+			return false
+		}
+		clip, err := o.AudioClip()
+		return err == nil && (clip == nil || clip.Contains(time))
+	})
+}
+
+// Finds the deepest object referencing the given text [ref]. When [ref] has no fragment, the
+// first object referencing the resource is returned.
+func (s *CachedGuidedNavigationService) ClipForTextRef(ref string) (*GuidedNavigationClip, error) {
+	path, fragment, _ := strings.Cut(ref, "#")
+	return s.findClip(func(o manifest.GuidedNavigationObject) bool {
+		file := o.TextFile()
+		return file != nil && file.String() == path && (fragment == "" || o.TextFragmentID() == fragment)
+	})
+}
+
+func (s *CachedGuidedNavigationService) findClip(match func(manifest.GuidedNavigationObject) bool) (*GuidedNavigationClip, error) {
+	for _, ref := range s.refs {
+		doc, err := s.guide(ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed loading the guided navigation document of %s", ref)
+		}
+		if doc == nil {
+			continue
+		}
+		if o := findGuidedNavigationObject(doc.Guided, match); o != nil {
+			return &GuidedNavigationClip{Ref: ref, Object: *o}, nil
+		}
+	}
+	return nil, nil
+}
+
+func findGuidedNavigationObject(objects []manifest.GuidedNavigationObject, match func(manifest.GuidedNavigationObject) bool) *manifest.GuidedNavigationObject {
+	for i := range objects {
+		if o := findGuidedNavigationObject(objects[i].Children, match); o != nil {
+			return o
+		}
+		if match(objects[i]) {
+			return &objects[i]
+		}
+	}
+	return nil
+}
+
+// Wraps the [GuidedNavigationService] created by [factory] in a [CachedGuidedNavigationService].
+func CachedGuidedNavigationServiceFactory(factory ServiceFactory) ServiceFactory {
+	return func(context Context) Service {
+		service, ok := factory(context).(GuidedNavigationService)
+		if !ok {
+			return nil
+		}
+		var refs []string
+		for _, link := range context.Manifest.ReadingOrder {
+			if href := link.Href.String(); service.HasGuideForResource(href) {
+				refs = append(refs, href)
+			}
+		}
+		return &CachedGuidedNavigationService{
+			service:   service,
+			refs:      refs,
+			documents: make(map[string]*manifest.GuidedNavigationDocument),
+		}
+	}
+}
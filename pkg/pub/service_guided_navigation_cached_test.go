@@ -0,0 +1,184 @@
+package pub
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+// Guided navigation service counting the documents it generates.
+type testGuidedNavigationService struct {
+	documents map[string][]manifest.GuidedNavigationObject
+	loads     map[string]int
+}
+
+func (s *testGuidedNavigationService) Close() {}
+
+func (s *testGuidedNavigationService) Links() manifest.LinkList {
+	return manifest.LinkList{GuidedNavigationLink}
+}
+
+func (s *testGuidedNavigationService) Get(link manifest.Link) (fetcher.Resource, bool) {
+	return GetForGuidedNavigationService(s, link)
+}
+
+func (s *testGuidedNavigationService) HasGuideForResource(href string) bool {
+	_, ok := s.documents[href]
+	return ok
+}
+
+func (s *testGuidedNavigationService) GuideForResource(href string) (*manifest.GuidedNavigationDocument, error) {
+	s.loads[href]++
+	return &manifest.GuidedNavigationDocument{Guided: s.documents[href]}, nil
+}
+
+func newTestCachedGuidedNavigationService(documents map[string][]manifest.GuidedNavigationObject) (*CachedGuidedNavigationService, *testGuidedNavigationService) {
+	inner := &testGuidedNavigationService{documents: documents, loads: make(map[string]int)}
+	factory := CachedGuidedNavigationServiceFactory(func(context Context) Service {
+		return inner
+	})
+	service := factory(Context{Manifest: manifest.Manifest{ReadingOrder: manifest.LinkList{
+		{Href: manifest.MustNewHREFFromString("c1.xhtml", false)},
+		{Href: manifest.MustNewHREFFromString("cover.xhtml", false)},
+		{Href: manifest.MustNewHREFFromString("c2.xhtml", false)},
+	}}})
+	return service.(*CachedGuidedNavigationService), inner
+}
+
+var testGuidedNavigationDocuments = map[string][]manifest.GuidedNavigationObject{
+	"c1.xhtml": {
+		{TextRef: "c1.xhtml#p1", AudioRef: "a.mp3#t=0,2.5"},
+		{TextRef: "c1.xhtml#s1", Children: []manifest.GuidedNavigationObject{
+			{TextRef: "c1.xhtml#p2", AudioRef: "a.mp3#t=2.5,4"},
+			{TextRef: "c1.xhtml#p3", AudioRef: "a.mp3#t=4"},
+		}},
+	},
+	"c2.xhtml": {
+		{TextRef: "c2.xhtml#p1", AudioRef: "b.mp3#t=0,1"},
+	},
+}
+
+func TestCachedGuidedNavigationServiceCachesDocuments(t *testing.T) {
+	service, inner := newTestCachedGuidedNavigationService(testGuidedNavigationDocuments)
+	assert.True(t, service.HasGuideForResource("c1.xhtml"))
+	assert.False(t, service.HasGuideForResource("cover.xhtml"))
+
+	for i := 0; i < 2; i++ {
+		doc, err := service.GuideForResource("c1.xhtml")
+		assert.NoError(t, err)
+		assert.Len(t, doc.Guided, 2)
+	}
+	_, err := service.GuideForPublication(0)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"c1.xhtml": 1, "c2.xhtml": 1}, inner.loads)
+}
+
+func TestCachedGuidedNavigationServiceWholePublication(t *testing.T) {
+	service, _ := newTestCachedGuidedNavigationService(testGuidedNavigationDocuments)
+
+	res, ok := service.Get(manifest.Link{Href: manifest.MustNewHREFFromString("~readium/guided-navigation.json", false)})
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "~readium/guided-navigation.json", res.Link().Href.String())
+	data, rerr := res.Read(0, 0)
+	if assert.Nil(t, rerr) {
+		var doc manifest.GuidedNavigationDocument
+		assert.NoError(t, json.Unmarshal(data, &doc))
+		assert.Equal(t, []manifest.GuidedNavigationObject{
+			testGuidedNavigationDocuments["c1.xhtml"][0],
+			testGuidedNavigationDocuments["c1.xhtml"][1],
+			testGuidedNavigationDocuments["c2.xhtml"][0],
+		}, doc.Guided)
+		assert.Empty(t, doc.Links)
+	}
+
+	res, _ = service.Get(manifest.Link{Href: manifest.MustNewHREFFromString("~readium/guided-navigation.json?page=0", false)})
+	_, rerr = res.Read(0, 0)
+	assert.Equal(t, fetcher.BadRequest(nil).Code, rerr.Code)
+}
+
+func TestCachedGuidedNavigationServicePages(t *testing.T) {
+	objects := make([]manifest.GuidedNavigationObject, GuidedNavigationPageSize+10)
+	for i := range objects {
+		objects[i] = manifest.GuidedNavigationObject{TextRef: fmt.Sprintf("c1.xhtml#p%d", i)}
+	}
+	service, inner := newTestCachedGuidedNavigationService(map[string][]manifest.GuidedNavigationObject{
+		"c1.xhtml": objects,
+		"c2.xhtml": {{TextRef: "c2.xhtml#p1"}},
+	})
+
+	doc, err := service.GuideForPublication(1)
+	assert.NoError(t, err)
+	assert.Len(t, doc.Guided, GuidedNavigationPageSize)
+	if assert.Len(t, doc.Links, 1) {
+		assert.Equal(t, manifest.Strings{"next"}, doc.Links[0].Rels)
+		assert.Equal(t, "~readium/guided-navigation.json?page=2", doc.Links[0].Href.String())
+	}
+	assert.Zero(t, inner.loads["c2.xhtml"], "the first page must not load the following documents")
+
+	doc, err = service.GuideForPublication(2)
+	assert.NoError(t, err)
+	if assert.Len(t, doc.Guided, 11) {
+		assert.Equal(t, "c1.xhtml#p100", doc.Guided[0].TextRef)
+		assert.Equal(t, "c2.xhtml#p1", doc.Guided[10].TextRef)
+	}
+	if assert.Len(t, doc.Links, 1) {
+		assert.Equal(t, manifest.Strings{"prev"}, doc.Links[0].Rels)
+	}
+
+	doc, err = service.GuideForPublication(3)
+	assert.NoError(t, err)
+	assert.Nil(t, doc)
+
+	res, _ := service.Get(manifest.Link{Href: manifest.MustNewHREFFromString("~readium/guided-navigation.json?page=3", false)})
+	_, rerr := res.Read(0, 0)
+	assert.Equal(t, fetcher.NotFound(nil).Code, rerr.Code)
+}
+
+func TestCachedGuidedNavigationServiceClipAtAudioTime(t *testing.T) {
+	service, _ := newTestCachedGuidedNavigationService(testGuidedNavigationDocuments)
+
+	clip, err := service.ClipAtAudioTime("a.mp3", 3)
+	assert.NoError(t, err)
+	if assert.NotNil(t, clip) {
+		assert.Equal(t, "c1.xhtml", clip.Ref)
+		assert.Equal(t, "c1.xhtml#p2", clip.Object.TextRef)
+	}
+
+	// Open-ended clip
+	clip, _ = service.ClipAtAudioTime("a.mp3", 100)
+	if assert.NotNil(t, clip) {
+		assert.Equal(t, "c1.xhtml#p3", clip.Object.TextRef)
+	}
+
+	clip, _ = service.ClipAtAudioTime("b.mp3", 0)
+	if assert.NotNil(t, clip) {
+		assert.Equal(t, "c2.xhtml", clip.Ref)
+	}
+
+	clip, _ = service.ClipAtAudioTime("b.mp3", 1)
+	assert.Nil(t, clip)
+}
+
+func TestCachedGuidedNavigationServiceClipForTextRef(t *testing.T) {
+	service, _ := newTestCachedGuidedNavigationService(testGuidedNavigationDocuments)
+
+	clip, err := service.ClipForTextRef("c1.xhtml#p3")
+	assert.NoError(t, err)
+	if assert.NotNil(t, clip) {
+		assert.Equal(t, "a.mp3#t=4", clip.Object.AudioRef)
+	}
+
+	clip, _ = service.ClipForTextRef("c2.xhtml")
+	if assert.NotNil(t, clip) {
+		assert.Equal(t, "c2.xhtml#p1", clip.Object.TextRef)
+	}
+
+	clip, _ = service.ClipForTextRef("c1.xhtml#unknown")
+	assert.Nil(t, clip)
+}
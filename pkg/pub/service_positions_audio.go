@@ -0,0 +1,177 @@
+package pub
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/readium/go-toolkit/pkg/fetcher"
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+)
+
+// Default duration of a position in an audiobook, in seconds.
+const DefaultAudioPositionDuration = 60.0
+
+// Maximum number of positions of a single audio resource. Longer resources get longer positions,
+// to protect against absurd durations.
+const MaxAudioPositionsPerResource = 10000
+
+// AudioPositionsService implements PositionsService
+// Splits the audio resources of the [readingOrder] into positions of a fixed duration,
+// using the [Link.Duration] of each resource.
+type AudioPositionsService struct {
+	readingOrder    manifest.LinkList
+	tableOfContents manifest.LinkList    // Used to compute the position titles.
+	duration        float64              // Duration of a position, in seconds.
+	positions       [][]manifest.Locator // Cached calculated positions
+}
+
+func (s *AudioPositionsService) Close() {}
+
+func (s *AudioPositionsService) Links() manifest.LinkList {
+	return manifest.LinkList{PositionsLink}
+}
+
+func (s *AudioPositionsService) Get(link manifest.Link) (fetcher.Resource, bool) {
+	return GetForPositionsService(s, link)
+}
+
+// Positions implements PositionsService
+func (s *AudioPositionsService) Positions() []manifest.Locator {
+	poss := s.PositionsByReadingOrder()
+	var positions []manifest.Locator
+	for _, v := range poss {
+		positions = append(positions, v...)
+	}
+	return positions
+}
+
+// PositionsByReadingOrder implements PositionsService
+func (s *AudioPositionsService) PositionsByReadingOrder() [][]manifest.Locator {
+	if s.positions == nil {
+		s.positions = s.computePositions()
+	}
+	return s.positions
+}
+
+func (s *AudioPositionsService) computePositions() [][]manifest.Locator {
+	duration := s.duration
+	if duration <= 0 {
+		duration = DefaultAudioPositionDuration
+	}
+	var total float64
+	for _, link := range s.readingOrder {
+		total += audioDuration(link)
+	}
+	if math.IsInf(total, 0) {
+		total = 0
+	}
+
+	toc := s.tableOfContents.Flatten()
+	positions := make([][]manifest.Locator, len(s.readingOrder))
+	position := uint(0)
+	elapsed := 0.0
+	for i, link := range s.readingOrder {
+		typ := link.MediaType
+		if typ == nil {
+			typ = &mediatype.Binary
+		}
+		u := link.URL(nil, nil)
+
+		linkDuration := audioDuration(link)
+		step := duration
+		count := 1
+		if linkDuration > 0 {
+			if c := math.Ceil(linkDuration / step); c > MaxAudioPositionsPerResource {
+				count = MaxAudioPositionsPerResource
+				step = linkDuration / MaxAudioPositionsPerResource
+			} else {
+				count = int(c)
+			}
+		}
+		positions[i] = make([]manifest.Locator, 0, count)
+		for j := 0; j < count; j++ {
+			start := float64(j) * step
+			progression := 0.0
+			if linkDuration > 0 {
+				progression = start / linkDuration
+			}
+			var totalProgression float64
+			if total > 0 {
+				totalProgression = (elapsed + start) / total
+			} else {
+				totalProgression = float64(i) / float64(len(s.readingOrder))
+			}
+			position++
+
+			title := link.Title
+			if t := audioChapterTitle(toc, link, start); t != "" {
+				title = t
+			}
+
+			positions[i] = append(positions[i], manifest.Locator{
+				Href:      u,
+				MediaType: *typ,
+				Title:     title,
+				Locations: manifest.Locations{
+					Fragments:        []string{"t=" + strconv.FormatFloat(start, 'f', -1, 64)},
+					Progression:      &progression,
+					TotalProgression: &totalProgression,
+					Position:         extensions.Pointer(position),
+				},
+			})
+		}
+		elapsed += linkDuration
+	}
+	return positions
+}
+
+// Returns the duration of the audio resource [link], or 0 when it is unknown or not a finite
+// positive number.
+func audioDuration(link manifest.Link) float64 {
+	if link.Duration > 0 && !math.IsInf(link.Duration, 0) {
+		return link.Duration
+	}
+	return 0
+}
+
+// Returns the title of the last table of contents entry of the resource [link] starting
+// before [start], according to its temporal media fragment (#t=).
+func audioChapterTitle(toc []manifest.Link, link manifest.Link, start float64) string {
+	path := link.URL(nil, nil).Path()
+	title := ""
+	best := -1.0
+	for _, entry := range toc {
+		u := entry.URL(nil, nil)
+		if u.Path() != path {
+			continue
+		}
+		t := 0.0
+		if v, ok := strings.CutPrefix(u.Fragment(), "t="); ok {
+			v, _, _ = strings.Cut(v, ",")
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			t = f
+		}
+		if t <= start && t >= best {
+			best, title = t, entry.Title
+		}
+	}
+	return title
+}
+
+// Creates an [AudioPositionsService] with positions lasting [duration] seconds,
+// or [DefaultAudioPositionDuration] if [duration] is 0.
+func AudioPositionsServiceFactory(duration float64) ServiceFactory {
+	return func(context Context) Service {
+		return &AudioPositionsService{
+			readingOrder:    context.Manifest.ReadingOrder,
+			tableOfContents: context.Manifest.TableOfContents,
+			duration:        duration,
+		}
+	}
+}
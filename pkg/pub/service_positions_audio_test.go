@@ -0,0 +1,111 @@
+package pub
+
+import (
+	"math"
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/internal/extensions"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/mediatype"
+	"github.com/readium/go-toolkit/pkg/util/url"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioPositionsServiceEmptyReadingOrder(t *testing.T) {
+	service := AudioPositionsService{}
+	assert.Equal(t, 0, len(service.Positions()))
+}
+
+func TestAudioPositionsServiceSplitsResources(t *testing.T) {
+	service := AudioPositionsService{
+		readingOrder: manifest.LinkList{
+			{Href: manifest.MustNewHREFFromString("chap1.mp3", false), MediaType: &mediatype.MP3, Duration: 25},
+			{Href: manifest.MustNewHREFFromString("chap2.mp3", false), MediaType: &mediatype.MP3, Duration: 15, Title: "Chapter 2"},
+		},
+		duration: 10,
+	}
+
+	locator := func(href string, fragment string, title string, position uint, progression float64, totalProgression float64) manifest.Locator {
+		return manifest.Locator{
+			Href:      url.MustURLFromString(href),
+			MediaType: mediatype.MP3,
+			Title:     title,
+			Locations: manifest.Locations{
+				Fragments:        []string{fragment},
+				Progression:      extensions.Pointer(progression),
+				TotalProgression: extensions.Pointer(totalProgression),
+				Position:         extensions.Pointer(position),
+			},
+		}
+	}
+	assert.Equal(t, [][]manifest.Locator{
+		{
+			locator("chap1.mp3", "t=0", "", 1, 0, 0),
+			locator("chap1.mp3", "t=10", "", 2, 0.4, 0.25),
+			locator("chap1.mp3", "t=20", "", 3, 0.8, 0.5),
+		},
+		{
+			locator("chap2.mp3", "t=0", "Chapter 2", 4, 0, 25.0/40.0),
+			locator("chap2.mp3", "t=10", "Chapter 2", 5, 10.0/15.0, 35.0/40.0),
+		},
+	}, service.PositionsByReadingOrder())
+	assert.Len(t, service.Positions(), 5)
+}
+
+func TestAudioPositionsServiceWithoutDuration(t *testing.T) {
+	service := AudioPositionsService{
+		readingOrder: manifest.LinkList{
+			{Href: manifest.MustNewHREFFromString("a.mp3", false)},
+			{Href: manifest.MustNewHREFFromString("b.mp3", false)},
+		},
+	}
+	positions := service.Positions()
+	if assert.Len(t, positions, 2) {
+		assert.Equal(t, mediatype.Binary, positions[0].MediaType)
+		assert.Equal(t, 0.5, *positions[1].Locations.TotalProgression)
+		assert.Equal(t, uint(2), *positions[1].Locations.Position)
+	}
+}
+
+func TestAudioPositionsServiceTitlesFromChapters(t *testing.T) {
+	service := AudioPositionsService{
+		readingOrder: manifest.LinkList{
+			{Href: manifest.MustNewHREFFromString("book.m4b", false), Duration: 30, Title: "Book"},
+		},
+		tableOfContents: manifest.LinkList{
+			{Href: manifest.MustNewHREFFromString("book.m4b#t=0", false), Title: "Opening"},
+			{Href: manifest.MustNewHREFFromString("book.m4b#t=15", false), Title: "Middle", Children: manifest.LinkList{
+				{Href: manifest.MustNewHREFFromString("book.m4b#t=25", false), Title: "End"},
+			}},
+		},
+		duration: 10,
+	}
+	titles := []string{}
+	for _, p := range service.Positions() {
+		titles = append(titles, p.Title)
+	}
+	assert.Equal(t, []string{"Opening", "Opening", "Middle"}, titles)
+}
+
+func TestAudioPositionsServiceInvalidDurations(t *testing.T) {
+	service := AudioPositionsService{
+		readingOrder: manifest.LinkList{
+			{Href: manifest.MustNewHREFFromString("huge.mp3", false), Duration: 1e300},
+			{Href: manifest.MustNewHREFFromString("inf.mp3", false), Duration: math.Inf(1)},
+			{Href: manifest.MustNewHREFFromString("nan.mp3", false), Duration: math.NaN()},
+			{Href: manifest.MustNewHREFFromString("negative.mp3", false), Duration: -5},
+		},
+	}
+	positions := service.PositionsByReadingOrder()
+	if assert.Len(t, positions, 4) {
+		if assert.Len(t, positions[0], MaxAudioPositionsPerResource) {
+			last := positions[0][MaxAudioPositionsPerResource-1]
+			assert.InDelta(t, 1.0, *last.Locations.Progression, 0.001)
+		}
+		for _, p := range positions[1:] {
+			if assert.Len(t, p, 1) {
+				assert.Equal(t, []string{"t=0"}, p[0].Locations.Fragments)
+			}
+		}
+	}
+}
@@ -8,8 +8,12 @@ import (
 	"github.com/readium/go-toolkit/pkg/asset"
 	"github.com/readium/go-toolkit/pkg/manifest"
 	"github.com/readium/go-toolkit/pkg/parser"
+	"github.com/readium/go-toolkit/pkg/parser/daisy"
 	"github.com/readium/go-toolkit/pkg/parser/epub"
+	"github.com/readium/go-toolkit/pkg/parser/fb2"
+	"github.com/readium/go-toolkit/pkg/parser/mobi"
 	"github.com/readium/go-toolkit/pkg/parser/pdf"
+	"github.com/readium/go-toolkit/pkg/parser/text"
 	"github.com/readium/go-toolkit/pkg/pub"
 )
 
@@ -24,6 +28,7 @@ type Streamer struct {
 	parsers           []parser.PublicationParser
 	inferA11yMetadata InferA11yMetadata
 	inferPageCount    bool
+	inferSpreads      bool
 	archiveFactory    archive.ArchiveFactory
 	// TODO pdfFactory
 	httpClient *http.Client
@@ -31,12 +36,14 @@ type Streamer struct {
 }
 
 type Config struct {
-	Parsers              []parser.PublicationParser // Parsers used to open a publication, in addition to the default parsers.
-	IgnoreDefaultParsers bool                       // When true, only parsers provided in parsers will be used.
-	InferA11yMetadata    InferA11yMetadata          // When not empty, additional accessibility metadata will be infered from the manifest.
-	InferPageCount       bool                       // When true, will infer `Metadata.NumberOfPages` from the generated position list.
-	ArchiveFactory       archive.ArchiveFactory     // Opens an archive (e.g. ZIP, RAR), optionally protected by credentials.
-	HttpClient           *http.Client               // Service performing HTTP requests.
+	Parsers                     []parser.PublicationParser // Parsers used to open a publication, in addition to the default parsers.
+	IgnoreDefaultParsers        bool                       // When true, only parsers provided in parsers will be used.
+	InferA11yMetadata           InferA11yMetadata          // When not empty, additional accessibility metadata will be infered from the manifest.
+	InferPageCount              bool                       // When true, will infer `Metadata.NumberOfPages` from the generated position list.
+	ArchiveFactory              archive.ArchiveFactory     // Opens an archive (e.g. ZIP, RAR), optionally protected by credentials.
+	InferSpreads                bool                       // When true, will add the synthetic spreads of fixed-layout publications to the manifest, in a `spreads` collection.
+	HttpClient                  *http.Client               // Service performing HTTP requests.
+	ReflowablePositionsStrategy epub.ReflowableStrategy    // Computes the positions of reflowable EPUB resources, defaults to `epub.RecommendedReflowableStrategy`.
 }
 
 type InferA11yMetadata uint8
@@ -61,11 +68,16 @@ func New(config Config) Streamer { // TODO contentProtections
 	}
 
 	defaultParsers := []parser.PublicationParser{
-		epub.NewParser(nil), // TODO pass strategy
+		epub.NewParser(config.ReflowablePositionsStrategy),
 		pdf.NewParser(),
 		parser.NewWebPubParser(config.HttpClient),
+		parser.LPFParser{},
+		daisy.NewParser(),
+		fb2.NewParser(),
+		mobi.NewParser(),
 		parser.ImageParser{},
 		parser.AudioParser{},
+		text.NewParser(),
 	}
 
 	if !config.IgnoreDefaultParsers {
@@ -76,6 +88,7 @@ func New(config Config) Streamer { // TODO contentProtections
 		parsers:           config.Parsers,
 		inferA11yMetadata: config.InferA11yMetadata,
 		inferPageCount:    config.InferPageCount,
+		inferSpreads:      config.InferSpreads,
 		archiveFactory:    config.ArchiveFactory,
 		httpClient:        config.HttpClient,
 	}
@@ -122,9 +135,31 @@ func (s Streamer) Open(a asset.PublicationAsset, credentials string) (*pub.Publi
 		}
 	}
 
+	if s.inferSpreads {
+		inferSpreadsInManifest(&pub.Manifest)
+	}
+
 	return pub, nil
 }
 
+// Adds the synthetic spreads of the publication to the manifest, one collection of links per
+// spread.
+func inferSpreadsInManifest(m *manifest.Manifest) {
+	spreads := m.Spreads()
+	if spreads == nil {
+		return
+	}
+	progression := m.Metadata.EffectiveReadingProgression()
+	collections := make([]manifest.PublicationCollection, len(spreads))
+	for i, spread := range spreads {
+		collections[i] = manifest.PublicationCollection{Links: spread.Links(progression)}
+	}
+	if m.Subcollections == nil {
+		m.Subcollections = make(manifest.PublicationCollectionMap)
+	}
+	m.Subcollections[manifest.SpreadsSubcollectionKey] = collections
+}
+
 func (s *Streamer) inferA11yMetadataInPublication(pub *pub.Publication) {
 	if s.inferA11yMetadata == InferA11yMetadataNo {
 		return
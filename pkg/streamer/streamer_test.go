@@ -0,0 +1,87 @@
+package streamer
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/readium/go-toolkit/pkg/asset"
+	"github.com/readium/go-toolkit/pkg/manifest"
+	"github.com/readium/go-toolkit/pkg/pub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamerInferSpreads(t *testing.T) {
+	s := New(Config{InferSpreads: true})
+	p, err := s.Open(asset.File("../../test/cole-voyage-of-life.epub"), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer p.Close()
+
+	// The fixed-layout paintings must not be displayed in a spread.
+	spreads := p.Manifest.Subcollections[manifest.SpreadsSubcollectionKey]
+	if assert.Len(t, spreads, len(p.Manifest.ReadingOrder)) {
+		for i, spread := range spreads {
+			if assert.Len(t, spread.Links, 1) {
+				assert.Equal(t, p.Manifest.ReadingOrder[i].Href, spread.Links[0].Href)
+				assert.Equal(t, manifest.PageCenter, spread.Links[0].Properties.Page())
+			}
+		}
+	}
+
+	p, err = New(Config{}).Open(asset.File("../../test/moby-dick.epub"), "")
+	if assert.NoError(t, err) {
+		defer p.Close()
+		assert.NotContains(t, p.Manifest.Subcollections, manifest.SpreadsSubcollectionKey)
+	}
+}
+
+// Writes a ZIP archive at [name] in a temporary directory, with the entries of the DAISY 3 test
+// book and the given extra [files].
+func writeArchiveWithDAISYBook(t *testing.T, name string, files map[string]string) string {
+	r, err := zip.OpenReader("../parser/daisy/testdata/daisy3.zip")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer r.Close()
+
+	p := filepath.Join(t.TempDir(), name)
+	out, err := os.Create(p)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer out.Close()
+	zw := zip.NewWriter(out)
+	for _, f := range r.File {
+		assert.NoError(t, zw.Copy(f))
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		w.Write([]byte(content))
+	}
+	assert.NoError(t, zw.Close())
+	return p
+}
+
+func TestStreamerKeepsOriginalParserOfArchivesWithStrayOPF(t *testing.T) {
+	p, err := New(Config{}).Open(asset.File(writeArchiveWithDAISYBook(t, "comic.cbz", map[string]string{
+		"page1.png": "png", "page2.png": "png",
+	})), "")
+	if assert.NoError(t, err) {
+		defer p.Close()
+		assert.Equal(t, manifest.Profiles{manifest.ProfileDivina}, p.Manifest.Metadata.ConformsTo)
+		assert.Len(t, p.Manifest.ReadingOrder, 2)
+	}
+
+	p, err = New(Config{}).Open(asset.File(writeArchiveWithDAISYBook(t, "audiobook.zab", map[string]string{
+		"track1.mp3": "mp3",
+	})), "")
+	if assert.NoError(t, err) {
+		defer p.Close()
+		assert.Equal(t, manifest.Profiles{manifest.ProfileAudiobook}, p.Manifest.Metadata.ConformsTo)
+		assert.Nil(t, p.FindService(pub.GuidedNavigationService_Name))
+	}
+}
@@ -0,0 +1,57 @@
+package alignment
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type aeneasFragment struct {
+	ID       string           `json:"id"`
+	Begin    string           `json:"begin"`
+	End      string           `json:"end"`
+	Lines    []string         `json:"lines"`
+	Children []aeneasFragment `json:"children"`
+}
+
+// Parses the JSON sync map produced by the Aeneas forced aligner.
+// https://www.readbeyond.it/aeneas/docs/syncmap.html
+func ParseAeneas(data []byte) ([]Cue, error) {
+	var syncMap struct {
+		Fragments []aeneasFragment `json:"fragments"`
+	}
+	if err := json.Unmarshal(data, &syncMap); err != nil {
+		return nil, errors.Wrap(err, "failed parsing Aeneas sync map")
+	}
+	return aeneasCues(syncMap.Fragments)
+}
+
+func aeneasCues(fragments []aeneasFragment) ([]Cue, error) {
+	cues := make([]Cue, 0, len(fragments))
+	for _, f := range fragments {
+		start, err := parseTimestamp(f.Begin)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid begin of fragment %s", f.ID)
+		}
+		end, err := parseTimestamp(f.End)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid end of fragment %s", f.ID)
+		}
+		children, err := aeneasCues(f.Children)
+		if err != nil {
+			return nil, err
+		}
+		cue := Cue{
+			ID:    f.ID,
+			Start: start,
+			End:   end,
+			Text:  strings.TrimSpace(strings.Join(f.Lines, " ")),
+		}
+		if len(children) > 0 {
+			cue.Children = children
+		}
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
@@ -0,0 +1,49 @@
+package alignment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAeneas(t *testing.T) {
+	cues, err := ParseAeneas([]byte(`{
+		"fragments": [
+			{"begin": "0.000", "end": "1.240", "id": "s1", "language": "eng", "lines": ["Call me", "Ishmael."], "children": [
+				{"begin": "0.000", "end": "0.400", "id": "s1w1", "lines": ["Call"], "children": []},
+				{"begin": "0.400", "end": "1.240", "id": "s1w2", "lines": ["me"]}
+			]},
+			{"begin": "1.240", "end": "61.5", "id": "s2", "lines": ["Some years ago."], "children": []}
+		]
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Cue{
+		{ID: "s1", Start: 0, End: 1.24, Text: "Call me Ishmael.", Children: []Cue{
+			{ID: "s1w1", Start: 0, End: 0.4, Text: "Call"},
+			{ID: "s1w2", Start: 0.4, End: 1.24, Text: "me"},
+		}},
+		{ID: "s2", Start: 1.24, End: 61.5, Text: "Some years ago."},
+	}, cues)
+
+	_, err = ParseAeneas([]byte(`{"fragments": [{"begin": "a", "end": "1"}]}`))
+	assert.Error(t, err)
+	_, err = ParseAeneas([]byte(`[`))
+	assert.Error(t, err)
+}
+
+func TestParseTimestamp(t *testing.T) {
+	for value, expected := range map[string]float64{
+		"1.5":          1.5,
+		"01:02.500":    62.5,
+		"01:00:02.250": 3602.25,
+		"00:00:01,200": 1.2,
+	} {
+		v, err := parseTimestamp(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, v, value)
+	}
+	for _, value := range []string{"1:xx", "NaN", "00:00:Inf"} {
+		_, err := parseTimestamp(value)
+		assert.Error(t, err, value)
+	}
+}
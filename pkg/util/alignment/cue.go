@@ -0,0 +1,29 @@
+// Package alignment parses the files synchronizing a text with the time ranges of an audio or
+// video resource, such as forced alignment outputs and captions.
+package alignment
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/readium/go-toolkit/pkg/manifest"
+)
+
+// Text synchronized with a time range of a media resource.
+type Cue struct {
+	ID       string  // Identifier of the cue, e.g. the ID of the aligned HTML element.
+	Start    float64 // Start of the cue, in seconds.
+	End      float64 // End of the cue, in seconds.
+	Text     string  // Plain text of the cue.
+	Children []Cue   // Finer-grained cues, e.g. the words of a sentence.
+}
+
+// Parses a timestamp in the [[hh:]mm:]ss[.fff] format, where the fraction can be separated by a
+// comma like in SubRip files.
+func parseTimestamp(value string) (float64, error) {
+	seconds, err := manifest.ParseClockValue(strings.Replace(strings.TrimSpace(value), ",", ".", 1))
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid timestamp %q", value)
+	}
+	return seconds, nil
+}
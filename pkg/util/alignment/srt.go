@@ -0,0 +1,35 @@
+package alignment
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Matches the positioning overrides of SubRip cues, e.g. {\an8}
+var srtOverrides = regexp.MustCompile(`\{\\[^}]*\}`)
+
+// Parses a SubRip (SRT) subtitles file.
+// https://en.wikipedia.org/wiki/SubRip#Format
+func ParseSRT(data []byte) ([]Cue, error) {
+	var cues []Cue
+	for _, lines := range splitBlocks(data) {
+		var id string
+		if !strings.Contains(lines[0], "-->") {
+			if len(lines) < 2 {
+				return nil, errors.Errorf("invalid SubRip cue %q", lines[0])
+			}
+			id, lines = lines[0], lines[1:]
+		}
+		for i := range lines[1:] {
+			lines[i+1] = srtOverrides.ReplaceAllString(lines[i+1], "")
+		}
+		cue, err := parseCue(id, lines[0], lines[1:])
+		if err != nil {
+			return nil, err
+		}
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
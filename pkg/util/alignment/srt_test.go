@@ -0,0 +1,24 @@
+package alignment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSRT(t *testing.T) {
+	cues, err := ParseSRT([]byte("1\r\n00:00:00,000 --> 00:00:01,240\r\n<i>Call me</i>\r\nIshmael.\r\n\r\n" +
+		"2\r\n00:00:01,240 --> 00:01:01,500 X1:40 X2:600 Y1:20 Y2:50\r\n{\\an8}Some years ago.\r\n\r\n\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Cue{
+		{ID: "1", Start: 0, End: 1.24, Text: "Call me Ishmael."},
+		{ID: "2", Start: 1.24, End: 61.5, Text: "Some years ago."},
+	}, cues)
+}
+
+func TestParseSRTErrors(t *testing.T) {
+	_, err := ParseSRT([]byte("1\n"))
+	assert.Error(t, err)
+	_, err = ParseSRT([]byte("1\n00:00:01,000 -> 00:00:02,000\nInvalid\n"))
+	assert.Error(t, err)
+}
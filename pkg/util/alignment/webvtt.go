@@ -0,0 +1,160 @@
+package alignment
+
+import (
+	"html"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Parses a WebVTT file.
+//
+// Inline timestamps of the cue payloads, as used for karaoke-style captions, are turned into
+// children cues.
+// https://www.w3.org/TR/webvtt1/
+func ParseWebVTT(data []byte) ([]Cue, error) {
+	blocks := splitBlocks(data)
+	if len(blocks) == 0 || !isWebVTTHeader(blocks[0][0]) {
+		return nil, errors.New("missing WEBVTT file header")
+	}
+
+	var cues []Cue
+	for _, lines := range blocks[1:] {
+		var id string
+		if !strings.Contains(lines[0], "-->") {
+			if len(lines) < 2 || !strings.Contains(lines[1], "-->") {
+				// NOTE, STYLE and REGION blocks
+				continue
+			}
+			id, lines = lines[0], lines[1:]
+		}
+		cue, err := parseCue(id, lines[0], lines[1:])
+		if err != nil {
+			return nil, err
+		}
+		cue.Children = inlineTimedCues(cue, strings.Join(lines[1:], "\n"))
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
+
+func isWebVTTHeader(line string) bool {
+	rest, ok := strings.CutPrefix(line, "WEBVTT")
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// Splits a captions file into blocks of non-empty lines separated by blank lines.
+func splitBlocks(data []byte) [][]string {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var blocks [][]string
+	var block []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+				block = nil
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// Parses a cue from its timing line, e.g. "00:01.000 --> 00:04.000 align:start", and its
+// payload.
+func parseCue(id string, timing string, payload []string) (Cue, error) {
+	start, rest, ok := strings.Cut(timing, "-->")
+	if !ok {
+		return Cue{}, errors.Errorf("invalid cue timing %q", timing)
+	}
+	end := strings.Fields(rest)
+	if len(end) == 0 {
+		return Cue{}, errors.Errorf("invalid cue timing %q", timing)
+	}
+
+	cue := Cue{ID: strings.TrimSpace(id)}
+	var err error
+	if cue.Start, err = parseTimestamp(start); err != nil {
+		return Cue{}, errors.Wrap(err, "invalid cue start")
+	}
+	if cue.End, err = parseTimestamp(end[0]); err != nil {
+		return Cue{}, errors.Wrap(err, "invalid cue end")
+	}
+	if cue.End < cue.Start {
+		return Cue{}, errors.Errorf("cue %q ends before it starts", timing)
+	}
+	cue.Text = plainText(strings.Join(payload, "\n"))
+	return cue, nil
+}
+
+// Removes the markup of a cue payload, e.g. voice spans and inline timestamps, and collapses
+// its whitespaces.
+func plainText(payload string) string {
+	var sb strings.Builder
+	for len(payload) > 0 {
+		i := strings.IndexByte(payload, '<')
+		if i < 0 {
+			sb.WriteString(payload)
+			break
+		}
+		sb.WriteString(payload[:i])
+		j := strings.IndexByte(payload[i:], '>')
+		if j < 0 {
+			break
+		}
+		payload = payload[i+j+1:]
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(sb.String())), " ")
+}
+
+// Splits the payload of [cue] at its inline timestamps, e.g. "<00:01.500>".
+func inlineTimedCues(cue Cue, payload string) []Cue {
+	type segment struct {
+		start float64
+		text  string
+	}
+	segments := []segment{{start: cue.Start}}
+	for {
+		i := strings.IndexByte(payload, '<')
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(payload[i:], '>')
+		if j < 0 {
+			break
+		}
+		tag := payload[i+1 : i+j]
+		if t, err := parseTimestamp(tag); err == nil {
+			segments[len(segments)-1].text += payload[:i]
+			segments = append(segments, segment{start: t})
+		} else {
+			segments[len(segments)-1].text += payload[:i+j+1]
+		}
+		payload = payload[i+j+1:]
+	}
+	if len(segments) == 1 {
+		return nil
+	}
+	segments[len(segments)-1].text += payload
+
+	var children []Cue
+	for i, s := range segments {
+		text := plainText(s.text)
+		if text == "" {
+			continue
+		}
+		end := cue.End
+		if i+1 < len(segments) {
+			end = segments[i+1].start
+		}
+		children = append(children, Cue{Start: s.start, End: end, Text: text})
+	}
+	return children
+}
@@ -0,0 +1,42 @@
+package alignment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWebVTT(t *testing.T) {
+	cues, err := ParseWebVTT([]byte("\ufeffWEBVTT - Moby-Dick\r\n\r\n" +
+		"NOTE narrated by\r\nsomeone\r\n\r\n" +
+		"STYLE\r\n::cue { color: red }\r\n\r\n" +
+		"p1\r\n00:00.000 --> 00:01.240 align:start\r\n<v Narrator>Call me</v>\r\n<b>Ishmael</b> &amp; co.\r\n\r\n" +
+		"00:01.240 --> 00:01:01.500\r\nSome years ago.\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Cue{
+		{ID: "p1", Start: 0, End: 1.24, Text: "Call me Ishmael & co."},
+		{Start: 1.24, End: 61.5, Text: "Some years ago."},
+	}, cues)
+}
+
+func TestParseWebVTTInlineTimestamps(t *testing.T) {
+	cues, err := ParseWebVTT([]byte("WEBVTT\n\n00:01.000 --> 00:03.000\n<c>Call</c> <00:01.500><c>me</c> <00:02.000><c>Ishmael.</c>\n"))
+	assert.NoError(t, err)
+	if assert.Len(t, cues, 1) {
+		assert.Equal(t, "Call me Ishmael.", cues[0].Text)
+		assert.Equal(t, []Cue{
+			{Start: 1, End: 1.5, Text: "Call"},
+			{Start: 1.5, End: 2, Text: "me"},
+			{Start: 2, End: 3, Text: "Ishmael."},
+		}, cues[0].Children)
+	}
+}
+
+func TestParseWebVTTErrors(t *testing.T) {
+	_, err := ParseWebVTT([]byte("1\n00:00:01,000 --> 00:00:02,000\nSubRip\n"))
+	assert.Error(t, err)
+	_, err = ParseWebVTT([]byte("WEBVTTX\n"))
+	assert.Error(t, err)
+	_, err = ParseWebVTT([]byte("WEBVTT\n\n00:02.000 --> 00:01.000\nBackwards\n"))
+	assert.Error(t, err)
+}
@@ -88,8 +88,9 @@ func (u RelativeURL) Extension() string {
 
 // RemoveQuery implements URL
 func (u RelativeURL) RemoveQuery() URL {
-	u.url.RawQuery = ""
-	return RelativeURL{url: u.url, normalized: u.normalized}
+	res := *u.url // Copy, to keep the original URL intact
+	res.RawQuery = ""
+	return RelativeURL{url: &res, normalized: u.normalized}
 }
 
 // Fragment implements URL
@@ -99,8 +100,9 @@ func (u RelativeURL) Fragment() string {
 
 // RemoveFragment implements URL
 func (u RelativeURL) RemoveFragment() URL {
-	u.url.Fragment = ""
-	return RelativeURL{url: u.url, normalized: u.normalized}
+	res := *u.url // Copy, to keep the original URL intact
+	res.Fragment = ""
+	return RelativeURL{url: &res, normalized: u.normalized}
 }
 
 // Resolve implements URL
@@ -238,8 +240,9 @@ func (u AbsoluteURL) Extension() string {
 
 // RemoveQuery implements URL
 func (u AbsoluteURL) RemoveQuery() URL {
-	u.url.RawQuery = ""
-	return AbsoluteURL{url: u.url, scheme: u.scheme, normalized: u.normalized}
+	res := *u.url // Copy, to keep the original URL intact
+	res.RawQuery = ""
+	return AbsoluteURL{url: &res, scheme: u.scheme, normalized: u.normalized}
 }
 
 // Fragment implements URL
@@ -249,8 +252,9 @@ func (u AbsoluteURL) Fragment() string {
 
 // RemoveFragment implements URL
 func (u AbsoluteURL) RemoveFragment() URL {
-	u.url.Fragment = ""
-	return AbsoluteURL{url: u.url, scheme: u.scheme, normalized: u.normalized}
+	res := *u.url // Copy, to keep the original URL intact
+	res.Fragment = ""
+	return AbsoluteURL{url: &res, scheme: u.scheme, normalized: u.normalized}
 }
 
 // Resolve implements URL